@@ -0,0 +1,89 @@
+package authvital
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Management API scopes, for minting narrowly scoped admin tokens.
+const (
+	ScopeUsersRead          = "users:read"
+	ScopeUsersWrite         = "users:write"
+	ScopeOrganizationsRead  = "organizations:read"
+	ScopeOrganizationsWrite = "organizations:write"
+	ScopeRolesWrite         = "roles:write"
+	ScopeAuditLogsRead      = "audit_logs:read"
+	ScopeWebhooksWrite      = "webhooks:write"
+)
+
+// AdminToken is a management API token limited to a set of scopes.
+type AdminToken struct {
+	ID string `json:"id"`
+	// Token is only returned by Create.
+	Token       string    `json:"token,omitempty"`
+	Scopes      []string  `json:"scopes"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"createdBy"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// AdminTokensService mints least-privilege management tokens for
+// automation jobs. A token can only be minted with scopes the caller
+// holds itself.
+type AdminTokensService struct{}
+
+// Create mints a token with exactly scopes, expiring after ttl.
+func (s *AdminTokensService) Create(ctx context.Context, scopes []string, ttl time.Duration) (*AdminToken, error) {
+	return nil, ErrNotImplemented
+}
+
+// List lists unexpired admin tokens, without their values.
+func (s *AdminTokensService) List(ctx context.Context) ([]AdminToken, error) {
+	return nil, ErrNotImplemented
+}
+
+// Revoke revokes an admin token.
+func (s *AdminTokensService) Revoke(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// WithRequiredScopes makes New check up front that the client's token
+// grants scopes, failing with an *InsufficientScopeError listing the
+// missing ones instead of on the first call that needs them.
+func WithRequiredScopes(scopes ...string) Option {
+	return func(c *Client) {}
+}
+
+// RequireScopes returns an *InsufficientScopeError listing the scopes in
+// required that t was not granted, or nil if it has them all.
+func (t *Token) RequireScopes(required ...string) error {
+	return missingScopes(strings.Fields(t.Scope), required)
+}
+
+// RequireScopes is like Token.RequireScopes for a verified token's scope
+// claim.
+func (c Claims) RequireScopes(required ...string) error {
+	granted := c.strs("scope")
+	if len(granted) == 1 {
+		granted = strings.Fields(granted[0])
+	}
+	return missingScopes(granted, required)
+}
+
+func missingScopes(granted, required []string) error {
+	var missing []string
+	for _, s := range required {
+		if !contains(granted, s) && !contains(missing, s) {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &InsufficientScopeError{
+		APIError: APIError{Code: "insufficient_scope"},
+		Required: missing,
+	}
+}
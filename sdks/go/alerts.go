@@ -0,0 +1,156 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AlertTrigger is a condition that raises an alert.
+type AlertTrigger string
+
+const (
+	// AlertFailedLoginSpike fires when failed sign-ins within Window exceed
+	// Threshold.
+	AlertFailedLoginSpike AlertTrigger = "failed_login_spike"
+	// AlertAdminCreated fires when a user is granted an admin role.
+	AlertAdminCreated AlertTrigger = "admin_created"
+	// AlertSigningKeyRotated fires when a token signing key is rotated or
+	// revoked.
+	AlertSigningKeyRotated AlertTrigger = "signing_key_rotated"
+)
+
+// thresholded reports whether the trigger needs a Threshold and Window.
+func (t AlertTrigger) thresholded() bool {
+	return t == AlertFailedLoginSpike
+}
+
+// AlertChannelType identifies where alerts are delivered.
+type AlertChannelType string
+
+const (
+	AlertEmail     AlertChannelType = "email"
+	AlertWebhook   AlertChannelType = "webhook"
+	AlertPagerDuty AlertChannelType = "pagerduty"
+)
+
+// AlertChannel is one delivery target. Exactly the fields for Type are
+// used; secrets are write-only and empty when read back.
+type AlertChannel struct {
+	Type AlertChannelType `json:"type"`
+	// Emails are the AlertEmail recipients.
+	Emails []string `json:"emails,omitempty"`
+	// URL receives AlertWebhook deliveries, signed like other webhooks.
+	URL string `json:"url,omitempty"`
+	// RoutingKey is the PagerDuty Events API v2 integration key.
+	RoutingKey string `json:"routingKey,omitempty"`
+}
+
+// AlertRule raises an alert to Channels when Trigger fires.
+type AlertRule struct {
+	ID      string       `json:"id,omitempty"`
+	Name    string       `json:"name"`
+	Trigger AlertTrigger `json:"trigger"`
+	Enabled bool         `json:"enabled"`
+	// Threshold and Window apply to thresholded triggers, e.g. 50 failed
+	// sign-ins within 5 minutes.
+	Threshold int     `json:"threshold,omitempty"`
+	Window    Seconds `json:"window,omitempty"`
+	// OrganizationID scopes the rule to one organization; empty means
+	// the whole instance.
+	OrganizationID string         `json:"organizationId,omitempty"`
+	Channels       []AlertChannel `json:"channels"`
+	// Cooldown suppresses repeat alerts for the same rule.
+	Cooldown  Seconds   `json:"cooldown,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// Validate checks the rule before a request is made.
+func (r *AlertRule) Validate() error {
+	var errs []error
+	switch r.Trigger {
+	case AlertFailedLoginSpike, AlertAdminCreated, AlertSigningKeyRotated:
+	default:
+		errs = append(errs, fmt.Errorf("unknown trigger %q", r.Trigger))
+	}
+	if r.Trigger.thresholded() && (r.Threshold <= 0 || r.Window <= 0) {
+		errs = append(errs, fmt.Errorf("%s needs a threshold and window", r.Trigger))
+	}
+	if len(r.Channels) == 0 {
+		errs = append(errs, errors.New("no channels"))
+	}
+	for i, c := range r.Channels {
+		missing := false
+		switch c.Type {
+		case AlertEmail:
+			missing = len(c.Emails) == 0
+		case AlertWebhook:
+			missing = c.URL == ""
+		case AlertPagerDuty:
+			missing = c.RoutingKey == ""
+		default:
+			errs = append(errs, fmt.Errorf("channel %d: unknown type %q", i, c.Type))
+			continue
+		}
+		if missing {
+			errs = append(errs, fmt.Errorf("channel %d: incomplete %s settings", i, c.Type))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("authvital: alert rule %q: %w", r.Name, errors.Join(errs...))
+}
+
+// Alert is a fired alert.
+type Alert struct {
+	ID      string       `json:"id"`
+	RuleID  string       `json:"ruleId"`
+	Trigger AlertTrigger `json:"trigger"`
+	// Summary is the human-readable message sent to channels.
+	Summary string `json:"summary"`
+	// Count is the observed value for thresholded triggers.
+	Count     int       `json:"count,omitempty"`
+	FiredAt   time.Time `json:"firedAt"`
+	Delivered bool      `json:"delivered"`
+}
+
+// AlertsService configures anomaly alerts.
+type AlertsService struct{}
+
+// List lists alert rules.
+func (s *AlertsService) List(ctx context.Context) ([]AlertRule, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get returns an alert rule.
+func (s *AlertsService) Get(ctx context.Context, id string) (*AlertRule, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create adds an alert rule. It is validated with Validate first.
+func (s *AlertsService) Create(ctx context.Context, rule *AlertRule) (*AlertRule, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces an alert rule.
+func (s *AlertsService) Update(ctx context.Context, id string, rule *AlertRule) (*AlertRule, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete removes an alert rule.
+func (s *AlertsService) Delete(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// Test sends a sample alert for the rule to its channels.
+func (s *AlertsService) Test(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// History lists fired alerts, newest first.
+func (s *AlertsService) History(ctx context.Context, opts *ListOptions) (*List[Alert], error) {
+	return nil, ErrNotImplemented
+}
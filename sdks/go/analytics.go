@@ -0,0 +1,137 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// Granularity is the bucket size of a time series.
+type Granularity string
+
+const (
+	GranularityHour  Granularity = "hour"
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+)
+
+// AnalyticsQuery selects the period and scope of analytics.
+type AnalyticsQuery struct {
+	// From and To bound the period; To defaults to now and From to 30 days
+	// before To. Buckets are aligned to Granularity in UTC.
+	From, To time.Time
+	// Granularity defaults to GranularityDay.
+	Granularity Granularity
+	// OrganizationID restricts the results to one organization.
+	OrganizationID string
+	// ClientID restricts the results to one application.
+	ClientID string
+}
+
+// Point is one bucket of a time series.
+type Point struct {
+	// Time is the start of the bucket.
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// TimeSeries is a metric over time, oldest bucket first. Buckets with no
+// data are present with a zero value.
+type TimeSeries struct {
+	Metric      string      `json:"metric"`
+	Granularity Granularity `json:"granularity"`
+	Points      []Point     `json:"points"`
+}
+
+// Total returns the sum of the series' values.
+func (s *TimeSeries) Total() float64 {
+	var t float64
+	for _, p := range s.Points {
+		t += p.Value
+	}
+	return t
+}
+
+// Last returns the most recent point, or a zero Point for an empty series.
+func (s *TimeSeries) Last() Point {
+	if len(s.Points) == 0 {
+		return Point{}
+	}
+	return s.Points[len(s.Points)-1]
+}
+
+// LoginStats breaks sign-in attempts down by outcome.
+type LoginStats struct {
+	Succeeded TimeSeries `json:"succeeded"`
+	Failed    TimeSeries `json:"failed"`
+	// FailureReasons counts failures over the whole period by reason, such
+	// as "invalid_password" or "mfa_failed".
+	FailureReasons map[string]int64 `json:"failureReasons"`
+}
+
+// SuccessRate returns the fraction of attempts in the period that
+// succeeded, or 0 when there were none.
+func (s *LoginStats) SuccessRate() float64 {
+	ok, failed := s.Succeeded.Total(), s.Failed.Total()
+	if ok+failed == 0 {
+		return 0
+	}
+	return ok / (ok + failed)
+}
+
+// MFAAdoption is the share of active users with a second factor.
+type MFAAdoption struct {
+	// Rate is the fraction of active users enrolled, per bucket.
+	Rate TimeSeries `json:"rate"`
+	// ByMethod counts enrolled users at the end of the period by method,
+	// such as "totp", "webauthn" or "sms".
+	ByMethod map[string]int64 `json:"byMethod"`
+}
+
+// FunnelStep is one stage of the signup funnel.
+type FunnelStep struct {
+	// Name is the stage, e.g. "started", "email_verified" or "completed".
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// SignupFunnel counts users reaching each stage of sign-up in the period,
+// in order.
+type SignupFunnel struct {
+	Steps []FunnelStep `json:"steps"`
+}
+
+// Conversion returns the fraction of users entering the funnel who
+// completed it, or 0 for an empty funnel.
+func (f *SignupFunnel) Conversion() float64 {
+	if len(f.Steps) == 0 || f.Steps[0].Count == 0 {
+		return 0
+	}
+	return float64(f.Steps[len(f.Steps)-1].Count) / float64(f.Steps[0].Count)
+}
+
+// AnalyticsService reports usage metrics shown in the console dashboards.
+type AnalyticsService struct{}
+
+// ActiveUsers returns the number of distinct users who signed in per
+// bucket: daily active users at GranularityDay, monthly at
+// GranularityMonth.
+func (s *AnalyticsService) ActiveUsers(ctx context.Context, q *AnalyticsQuery) (*TimeSeries, error) {
+	return nil, ErrNotImplemented
+}
+
+// Logins returns sign-in attempts by outcome.
+func (s *AnalyticsService) Logins(ctx context.Context, q *AnalyticsQuery) (*LoginStats, error) {
+	return nil, ErrNotImplemented
+}
+
+// MFAAdoption returns second-factor enrollment among active users.
+func (s *AnalyticsService) MFAAdoption(ctx context.Context, q *AnalyticsQuery) (*MFAAdoption, error) {
+	return nil, ErrNotImplemented
+}
+
+// SignupFunnel returns the sign-up funnel for the period. q.Granularity is
+// ignored.
+func (s *AnalyticsService) SignupFunnel(ctx context.Context, q *AnalyticsQuery) (*SignupFunnel, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,187 @@
+package authvital
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIError is an error response from the AuthVital API that has no more
+// specific type.
+type APIError struct {
+	StatusCode int
+	// Code is the machine-readable error code, such as "not_found".
+	Code      string
+	Message   string
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("authvital: %d %s", e.StatusCode, e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// InsufficientScopeError is returned when the access token lacks a scope
+// the endpoint requires.
+type InsufficientScopeError struct {
+	APIError
+	// Required are the scopes the endpoint needs.
+	Required []string
+}
+
+func (e *InsufficientScopeError) Error() string {
+	return "authvital: missing scope " + strings.Join(e.Required, " ") + "; " + e.Hint()
+}
+
+// Hint suggests how to fix the error.
+func (e *InsufficientScopeError) Hint() string {
+	return "grant the scope to the application and request it when obtaining the token"
+}
+
+// FeatureRequiredError is returned when the organization's plan or feature
+// flags do not include a feature the request needs.
+type FeatureRequiredError struct {
+	APIError
+	Feature string
+	// UpgradeURL, when set, is where an admin can enable the feature.
+	UpgradeURL string
+}
+
+func (e *FeatureRequiredError) Error() string {
+	return "authvital: feature " + e.Feature + " is not enabled; " + e.Hint()
+}
+
+// Hint suggests how to fix the error.
+func (e *FeatureRequiredError) Hint() string {
+	if e.UpgradeURL != "" {
+		return "enable it at " + e.UpgradeURL
+	}
+	return "upgrade the plan or enable the feature flag"
+}
+
+// QuotaExceededError is returned when a plan quota, such as monthly active
+// users or API calls, is used up.
+type QuotaExceededError struct {
+	APIError
+	Quota string
+	Limit int64
+	Used  int64
+	// ResetAt is when the quota resets, or zero for quotas that do not.
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("authvital: quota %s exceeded (%d of %d); %s", e.Quota, e.Used, e.Limit, e.Hint())
+}
+
+// Hint suggests how to fix the error.
+func (e *QuotaExceededError) Hint() string {
+	if !e.ResetAt.IsZero() {
+		return "it resets at " + e.ResetAt.Format(time.RFC3339) + ", or upgrade the plan"
+	}
+	return "upgrade the plan or free up usage"
+}
+
+// apiErrorBody is the JSON body of API error responses.
+type apiErrorBody struct {
+	Code       string    `json:"code"`
+	Error      string    `json:"error"`
+	Message    any       `json:"message"`
+	Scope      string    `json:"scope"`
+	Feature    string    `json:"feature"`
+	UpgradeURL string    `json:"upgradeUrl"`
+	Quota      string    `json:"quota"`
+	Limit      int64     `json:"limit"`
+	Used       int64     `json:"used"`
+	ResetAt    time.Time `json:"resetAt"`
+	// Seat limit errors.
+	OrganizationID string `json:"organizationId"`
+	SeatsUsed      int    `json:"seatsUsed"`
+	SeatsPending   int    `json:"seatsPending"`
+	SeatLimit      int    `json:"seatLimit"`
+	// Approval required errors.
+	Operation  string `json:"operation"`
+	ApprovalID string `json:"approvalId"`
+}
+
+// parseAPIError converts an unsuccessful response to the most specific
+// error type. It consumes at most 64 KiB of the body.
+func parseAPIError(resp *http.Response) error {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	base, body := decodeAPIError(resp, raw)
+	switch base.Code {
+	case "insufficient_scope":
+		return &InsufficientScopeError{APIError: base, Required: strings.Fields(body.Scope)}
+	case "feature_required", "feature_not_enabled":
+		return &FeatureRequiredError{APIError: base, Feature: body.Feature, UpgradeURL: body.UpgradeURL}
+	case "quota_exceeded":
+		return &QuotaExceededError{APIError: base, Quota: body.Quota, Limit: body.Limit, Used: body.Used, ResetAt: body.ResetAt}
+	case "seat_limit_reached":
+		return &SeatLimitError{
+			APIError:       base,
+			OrganizationID: body.OrganizationID,
+			Seats:          Seats{Used: body.SeatsUsed, Pending: body.SeatsPending, Limit: body.SeatLimit},
+		}
+	case "approval_required":
+		return &ApprovalRequiredError{APIError: base, Operation: body.Operation, ApprovalID: body.ApprovalID}
+	}
+	return &base
+}
+
+// maxErrorBody bounds how much of an error response is read.
+const maxErrorBody = 64 << 10
+
+// decodeAPIError reads the error code and message of an error response
+// whose body is raw.
+func decodeAPIError(resp *http.Response, raw []byte) (APIError, apiErrorBody) {
+	base := APIError{StatusCode: resp.StatusCode, RequestID: resp.Header.Get("X-Request-Id")}
+	var body apiErrorBody
+	if json.Unmarshal(raw, &body) == nil {
+		base.Code = body.Code
+		if base.Code == "" {
+			base.Code = body.Error
+		}
+		switch m := body.Message.(type) {
+		case string:
+			base.Message = m
+		case []any:
+			// NestJS validation errors are a list of messages.
+			parts := make([]string, 0, len(m))
+			for _, p := range m {
+				parts = append(parts, fmt.Sprint(p))
+			}
+			base.Message = strings.Join(parts, "; ")
+		}
+	}
+	if base.Code == "" {
+		base.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+	}
+
+	// Bearer errors may only be in WWW-Authenticate (RFC 6750).
+	if scope, ok := bearerScopeError(resp.Header.Get("WWW-Authenticate")); ok && body.Scope == "" {
+		base.Code, body.Scope = "insufficient_scope", scope
+	}
+	return base, body
+}
+
+// bearerScopeError extracts the scope of an insufficient_scope challenge.
+func bearerScopeError(h string) (string, bool) {
+	if !strings.Contains(h, `error="insufficient_scope"`) {
+		return "", false
+	}
+	_, rest, ok := strings.Cut(h, `scope="`)
+	if !ok {
+		return "", true
+	}
+	scope, _, _ := strings.Cut(rest, `"`)
+	return scope, true
+}
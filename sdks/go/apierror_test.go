@@ -0,0 +1,37 @@
+package authvital
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func errorResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"X-Request-Id": {"req_1"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestParseAPIErrorKeepsBase(t *testing.T) {
+	err := parseAPIError(errorResponse(403, `{"code":"seat_limit_reached","message":"no seats","organizationId":"org_1","seatsUsed":5,"seatLimit":5}`))
+	var seat *SeatLimitError
+	if !errors.As(err, &seat) || !errors.Is(err, ErrSeatLimitReached) {
+		t.Fatalf("got %T %v", err, err)
+	}
+	if seat.StatusCode != 403 || seat.RequestID != "req_1" || seat.Code != "seat_limit_reached" || seat.OrganizationID != "org_1" {
+		t.Errorf("SeatLimitError = %+v", seat)
+	}
+
+	err = parseAPIError(errorResponse(409, `{"code":"approval_required","operation":"users.delete","approvalId":"apr_1"}`))
+	var approval *ApprovalRequiredError
+	if !errors.As(err, &approval) || !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("got %T %v", err, err)
+	}
+	if approval.StatusCode != 409 || approval.RequestID != "req_1" || approval.Code != "approval_required" || approval.ApprovalID != "apr_1" {
+		t.Errorf("ApprovalRequiredError = %+v", approval)
+	}
+}
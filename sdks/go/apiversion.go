@@ -0,0 +1,103 @@
+package authvital
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIVersionHeader carries the API version a request is written against.
+const APIVersionHeader = "AuthVital-Version"
+
+// WithAPIVersion pins the API version, such as "2026-06-01", so platform
+// upgrades do not change response shapes until the client opts in.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {}
+}
+
+// WithDeprecationHandler calls fn the first time each endpoint responds
+// with deprecation or sunset headers.
+func WithDeprecationHandler(fn func(Deprecation)) Option {
+	return func(c *Client) {}
+}
+
+// Deprecation describes a deprecated endpoint, from the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) response headers.
+type Deprecation struct {
+	Method string
+	Route  string
+	// Since is when the endpoint was deprecated; zero if not stated.
+	Since time.Time
+	// Sunset is when the endpoint will stop working; zero if not stated.
+	Sunset time.Time
+	// Link is documentation on migrating away, from a Link header with
+	// rel="deprecation".
+	Link string
+}
+
+// VersionTransport is an http.RoundTripper sending APIVersionHeader and
+// reporting deprecated endpoints.
+type VersionTransport struct {
+	Base    http.RoundTripper
+	Version string
+	// OnDeprecation is called once per method and route.
+	OnDeprecation func(Deprecation)
+
+	seen sync.Map
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *VersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Version != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(APIVersionHeader, t.Version)
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || t.OnDeprecation == nil {
+		return resp, err
+	}
+	if d, ok := parseDeprecation(resp.Header); ok {
+		d.Method, d.Route = req.Method, routeTemplate(req.URL.Path)
+		if _, dup := t.seen.LoadOrStore(d.Method+" "+d.Route, true); !dup {
+			t.OnDeprecation(d)
+		}
+	}
+	return resp, nil
+}
+
+// parseDeprecation reads the deprecation headers of a response.
+func parseDeprecation(h http.Header) (Deprecation, bool) {
+	var d Deprecation
+	dep, sunset := h.Get("Deprecation"), h.Get("Sunset")
+	if dep == "" && sunset == "" {
+		return d, false
+	}
+	// RFC 9745 uses a structured date ("@1688169599"); earlier drafts
+	// used an HTTP date or "true".
+	if strings.HasPrefix(dep, "@") {
+		if sec, err := strconv.ParseInt(dep[1:], 10, 64); err == nil {
+			d.Since = time.Unix(sec, 0).UTC()
+		}
+	} else if t, err := http.ParseTime(dep); err == nil {
+		d.Since = t
+	}
+	if t, err := http.ParseTime(sunset); err == nil {
+		d.Sunset = t
+	}
+	for _, link := range h.Values("Link") {
+		for _, l := range strings.Split(link, ",") {
+			if strings.Contains(l, `rel="deprecation"`) {
+				if start, end := strings.Index(l, "<"), strings.Index(l, ">"); start >= 0 && end > start {
+					d.Link = l[start+1 : end]
+				}
+			}
+		}
+	}
+	return d, true
+}
@@ -0,0 +1,129 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrApprovalRequired is matched by errors.Is for an *ApprovalRequiredError.
+var ErrApprovalRequired = errors.New("authvital: operation requires approval")
+
+// ApprovalRequiredError is returned by an operation under dual control
+// when it is called without an approved request. Retry the call with
+// WithApproval once ApprovalID is approved.
+type ApprovalRequiredError struct {
+	APIError
+	Operation string
+	// ApprovalID is the pending request created for this call.
+	ApprovalID string
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("authvital: %s requires approval; approval request %s is pending", e.Operation, e.ApprovalID)
+}
+
+// Is reports whether target is ErrApprovalRequired.
+func (e *ApprovalRequiredError) Is(target error) bool { return target == ErrApprovalRequired }
+
+// WithApproval runs a guarded call under an approved request.
+func WithApproval(approvalID string) CallOption {
+	return func(o *CallOptions) { o.ApprovalID = approvalID }
+}
+
+// ApprovalStatus is the state of an approval request.
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+	ApprovalExpired  ApprovalStatus = "expired"
+	// ApprovalUsed means the approved operation was carried out. An
+	// approval authorizes exactly one call.
+	ApprovalUsed ApprovalStatus = "used"
+)
+
+// ApprovalDecision is one approver's vote.
+type ApprovalDecision struct {
+	ApproverID string    `json:"approverId"`
+	Approved   bool      `json:"approved"`
+	Reason     string    `json:"reason,omitempty"`
+	DecidedAt  time.Time `json:"decidedAt"`
+}
+
+// Approval is a request to carry out a dual-control operation, such as
+// "organization.delete" or "signing_key.rotate".
+type Approval struct {
+	ID        string `json:"id"`
+	Operation string `json:"operation"`
+	// Resource is the ID of the object the operation targets.
+	Resource    string         `json:"resource,omitempty"`
+	Status      ApprovalStatus `json:"status"`
+	RequestedBy string         `json:"requestedBy"`
+	Reason      string         `json:"reason"`
+	// RequiredApprovals is how many approvers other than the requester
+	// must approve.
+	RequiredApprovals int                `json:"requiredApprovals"`
+	Decisions         []ApprovalDecision `json:"decisions"`
+	ExpiresAt         time.Time          `json:"expiresAt"`
+	CreatedAt         time.Time          `json:"createdAt"`
+}
+
+// ApprovalsService manages approval requests for dual-control operations.
+// Requesters cannot approve their own requests.
+type ApprovalsService struct{}
+
+// Request asks for approval to perform operation on resource.
+func (s *ApprovalsService) Request(ctx context.Context, operation, resource, reason string) (*Approval, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get retrieves an approval request.
+func (s *ApprovalsService) Get(ctx context.Context, id string) (*Approval, error) {
+	return nil, ErrNotImplemented
+}
+
+// List lists approval requests with status, or all when it is empty,
+// newest first.
+func (s *ApprovalsService) List(ctx context.Context, status ApprovalStatus, opts *ListOptions) (*List[Approval], error) {
+	return nil, ErrNotImplemented
+}
+
+// Approve records the caller's approval. A reason is required.
+func (s *ApprovalsService) Approve(ctx context.Context, id, reason string) (*Approval, error) {
+	return nil, ErrNotImplemented
+}
+
+// Reject rejects the request. A reason is required.
+func (s *ApprovalsService) Reject(ctx context.Context, id, reason string) (*Approval, error) {
+	return nil, ErrNotImplemented
+}
+
+// Cancel withdraws a pending request. Only the requester may cancel.
+func (s *ApprovalsService) Cancel(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// Wait polls the request every interval until it is no longer pending and
+// returns it. Check Status: only ApprovalApproved permits the operation.
+func (s *ApprovalsService) Wait(ctx context.Context, id string, interval time.Duration) (*Approval, error) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	for {
+		a, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if a.Status != ApprovalPending {
+			return a, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
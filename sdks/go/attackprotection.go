@@ -0,0 +1,49 @@
+package authvital
+
+import "context"
+
+// AttackProtectionSettings configures brute-force and suspicious-IP defenses.
+type AttackProtectionSettings struct {
+	BruteForce    BruteForceSettings   `json:"bruteForce"`
+	SuspiciousIP  SuspiciousIPSettings `json:"suspiciousIp"`
+	Notifications AttackNotifySettings `json:"notifications"`
+}
+
+// BruteForceSettings locks out an account after repeated failed sign-ins.
+type BruteForceSettings struct {
+	Enabled bool `json:"enabled"`
+	// MaxAttempts is the number of consecutive failures before lockout.
+	MaxAttempts int `json:"maxAttempts"`
+	// LockoutDuration is how long the lockout lasts. Zero locks until cleared.
+	LockoutDuration Seconds `json:"lockoutDuration"`
+}
+
+// SuspiciousIPSettings throttles addresses that fail against many accounts.
+type SuspiciousIPSettings struct {
+	Enabled bool `json:"enabled"`
+	// MaxAttemptsPerDay is the number of failures from one IP before throttling.
+	MaxAttemptsPerDay int `json:"maxAttemptsPerDay"`
+	// Allowlist lists CIDRs or IPs that are never throttled.
+	Allowlist []string `json:"allowlist,omitempty"`
+}
+
+// AttackNotifySettings controls who is told about detected attacks.
+type AttackNotifySettings struct {
+	// NotifyUser emails the account owner when their account is locked.
+	NotifyUser bool `json:"notifyUser"`
+	// AdminEmails receive a summary when throttling starts.
+	AdminEmails []string `json:"adminEmails,omitempty"`
+}
+
+// AttackProtectionService tunes brute-force and suspicious-IP protection.
+type AttackProtectionService struct{}
+
+// Get retrieves the attack protection settings.
+func (s *AttackProtectionService) Get(ctx context.Context) (*AttackProtectionSettings, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces the attack protection settings.
+func (s *AttackProtectionService) Update(ctx context.Context, settings *AttackProtectionSettings) (*AttackProtectionSettings, error) {
+	return nil, ErrNotImplemented
+}
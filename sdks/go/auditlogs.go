@@ -0,0 +1,42 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditLogEntry records a security-relevant operation.
+type AuditLogEntry struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenantId,omitempty"`
+	// UserID is the actor; it is empty for system actions.
+	UserID string `json:"userId,omitempty"`
+	// Action is "<target>:<verb>", e.g. "role:create" or "member:remove".
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId,omitempty"`
+	Metadata   json.RawMessage `json:"metadata,omitempty"`
+	IPAddress  string          `json:"ipAddress,omitempty"`
+	UserAgent  string          `json:"userAgent,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditLogListOptions filters AuditLogsService.List.
+type AuditLogListOptions struct {
+	ListOptions
+	TenantID string
+	UserID   string
+	// Action matches exactly, or by prefix when it ends in ":".
+	Action string
+	Since  time.Time
+}
+
+// AuditLogsService reads the audit log.
+type AuditLogsService struct{}
+
+// List lists audit log entries, oldest first. NextCursor is set even on
+// the last page, so it can be saved to fetch only newer entries later.
+func (s *AuditLogsService) List(ctx context.Context, opts *AuditLogListOptions) (*List[AuditLogEntry], error) {
+	return nil, ErrNotImplemented
+}
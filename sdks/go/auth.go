@@ -0,0 +1,63 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPasswordGrantDisabled is returned by LoginWithPassword unless the
+// client was created with WithDangerousPasswordGrant.
+var ErrPasswordGrantDisabled = errors.New("authvital: password grant is disabled; see WithDangerousPasswordGrant")
+
+// WithDangerousPasswordGrant enables Auth.LoginWithPassword. The password
+// grant hands the user's credentials to the application, bypasses the
+// hosted login page's protections and is deprecated by OAuth 2.1. Use it
+// only for legacy native apps that cannot open a browser yet. The
+// application must also have the grant enabled in AuthVital.
+func WithDangerousPasswordGrant() Option {
+	return func(c *Client) {}
+}
+
+// PasswordLogin are the credentials and scope for LoginWithPassword.
+type PasswordLogin struct {
+	Username string
+	Password string
+	// Scope defaults to DefaultScope.
+	Scope string
+	// TenantID requests a token scoped to that organization.
+	TenantID string
+}
+
+// MFARequiredError is returned by LoginWithPassword when the user must
+// complete a second factor. Continue with Challenge.
+type MFARequiredError struct {
+	// MFAToken identifies the half-finished login. It is short-lived and
+	// single-use.
+	MFAToken string
+	// Challenge completes the login.
+	Challenge *Challenge
+}
+
+func (e *MFARequiredError) Error() string {
+	return "authvital: multi-factor authentication required"
+}
+
+// AuthService signs users in directly, without the hosted login page.
+type AuthService struct {
+	// clock is the client's clock, for challenge expiry.
+	clock Clock
+}
+
+// LoginWithPassword exchanges a username and password for tokens using the
+// resource owner password grant. It returns ErrPasswordGrantDisabled
+// unless WithDangerousPasswordGrant was given, and *MFARequiredError when
+// a second factor is needed.
+func (s *AuthService) LoginWithPassword(ctx context.Context, login *PasswordLogin) (*Token, error) {
+	return nil, ErrNotImplemented
+}
+
+// VerifyMFA completes a login that returned *MFARequiredError with a
+// one-time code.
+func (s *AuthService) VerifyMFA(ctx context.Context, mfaToken, code string) (*Token, error) {
+	return nil, ErrNotImplemented
+}
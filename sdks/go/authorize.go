@@ -0,0 +1,140 @@
+package authvital
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// CheckRequest asks whether Subject has Relation to Object. Subjects and
+// objects are "type:id" strings, such as "user:123" and "document:42".
+type CheckRequest struct {
+	Subject  string `json:"subject"`
+	Relation string `json:"relation"`
+	Object   string `json:"object"`
+}
+
+// PermissionResult is the outcome of one check in CheckMany.
+type PermissionResult struct {
+	Allowed bool
+	// Err is set when this check failed; the others may still succeed.
+	Err error
+}
+
+// CheckManyOptions configure CheckMany.
+type CheckManyOptions struct {
+	// Batch sends all checks in one request to the batch endpoint instead
+	// of one request per distinct check.
+	Batch bool
+	// Concurrency bounds parallel requests when not batching. Defaults to 8.
+	Concurrency int
+}
+
+// AuthorizeService checks fine-grained permissions.
+type AuthorizeService struct{}
+
+// Check reports whether the subject has the relation to the object.
+func (s *AuthorizeService) Check(ctx context.Context, req CheckRequest) (bool, error) {
+	return false, ErrNotImplemented
+}
+
+// checkBatch checks reqs in a single request.
+func (s *AuthorizeService) checkBatch(ctx context.Context, reqs []CheckRequest) ([]bool, error) {
+	return nil, ErrNotImplemented
+}
+
+// CheckMany runs many checks, such as one per row of a list page, and
+// returns results in the order of reqs. Identical checks are made once.
+// The error is only for failures affecting every check, such as a failed
+// batch request; per-check failures are in PermissionResult.Err.
+func (s *AuthorizeService) CheckMany(ctx context.Context, reqs []CheckRequest, opts *CheckManyOptions) ([]PermissionResult, error) {
+	if opts == nil {
+		opts = &CheckManyOptions{}
+	}
+	index := make(map[CheckRequest]int, len(reqs))
+	var unique []CheckRequest
+	for _, r := range reqs {
+		if _, ok := index[r]; !ok {
+			index[r] = len(unique)
+			unique = append(unique, r)
+		}
+	}
+
+	results := make([]PermissionResult, len(unique))
+	if opts.Batch {
+		allowed, err := s.checkBatch(ctx, unique)
+		if err != nil {
+			return nil, err
+		}
+		if len(allowed) != len(unique) {
+			return nil, fmt.Errorf("authvital: batch check returned %d results for %d checks", len(allowed), len(unique))
+		}
+		for i := range unique {
+			results[i].Allowed = allowed[i]
+		}
+	} else {
+		n := opts.Concurrency
+		if n <= 0 {
+			n = 8
+		}
+		sem := make(chan struct{}, n)
+		var wg sync.WaitGroup
+		for i, r := range unique {
+			wg.Add(1)
+			go func(i int, r CheckRequest) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					results[i].Err = ctx.Err()
+					return
+				}
+				defer func() { <-sem }()
+				results[i].Allowed, results[i].Err = s.Check(ctx, r)
+			}(i, r)
+		}
+		wg.Wait()
+	}
+
+	out := make([]PermissionResult, len(reqs))
+	for i, r := range reqs {
+		out[i] = results[index[r]]
+	}
+	return out, nil
+}
+
+// RelationFilter selects objects Subject has Relation to.
+type RelationFilter struct {
+	Subject  string `json:"subject"`
+	Relation string `json:"relation"`
+}
+
+// listObjectsPage returns one page of object IDs.
+func (s *AuthorizeService) listObjectsPage(ctx context.Context, subject, relation, objectType, cursor string) (*List[string], error) {
+	return nil, ErrNotImplemented
+}
+
+// ListAuthorizedObjects calls fn with the ID of each object of objectType
+// that subject has relation to, fetching pages as needed. It stops at the
+// first error from fn and returns it.
+func (s *AuthorizeService) ListAuthorizedObjects(ctx context.Context, subject, relation, objectType string, fn func(objectID string) error) error {
+	cursor := ""
+	for {
+		page, err := s.listObjectsPage(ctx, subject, relation, objectType, cursor)
+		if err != nil {
+			return err
+		}
+		for _, id := range page.Items {
+			if err := fn(id); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		if page.NextCursor == cursor {
+			return fmt.Errorf("authvital: list authorized objects did not advance past cursor %q", cursor)
+		}
+		cursor = page.NextCursor
+	}
+}
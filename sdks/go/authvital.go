@@ -6,7 +6,11 @@
 // Follow https://github.com/authvital/authvital for updates!
 package authvital
 
-import "errors"
+import (
+	"crypto"
+	"errors"
+	"io"
+)
 
 // ErrNotImplemented is returned when calling placeholder methods.
 var ErrNotImplemented = errors.New("authvital: SDK is coming soon! Follow https://github.com/authvital/authvital for updates")
@@ -15,7 +19,101 @@ var ErrNotImplemented = errors.New("authvital: SDK is coming soon! Follow https:
 const Version = "0.0.1"
 
 // Client is a placeholder for the AuthVital client.
-type Client struct{}
+type Client struct {
+	// Users manages user accounts.
+	Users *UsersService
+	// Security exposes platform security signals.
+	Security *SecurityService
+	// Risk evaluates request risk.
+	Risk *RiskService
+	// Devices manages users' known devices.
+	Devices *DevicesService
+	// BotProtection configures CAPTCHA and bot protection.
+	BotProtection *BotProtectionService
+	// NetworkPolicies manages IP and geo restrictions.
+	NetworkPolicies *NetworkPoliciesService
+	// AttackProtection tunes brute-force and suspicious-IP protection.
+	AttackProtection *AttackProtectionService
+	// Connections manages enterprise SSO connections.
+	Connections *ConnectionsService
+	// DirectorySync inspects and controls directory sync.
+	DirectorySync *DirectorySyncService
+	// Portal creates sessions for the self-serve admin portal.
+	Portal *PortalService
+	// Entitlements attaches plan and feature entitlements.
+	Entitlements *EntitlementsService
+	// Organizations manages organizations.
+	Organizations *OrganizationsService
+	// Jobs tracks long-running jobs.
+	Jobs *JobsService
+	// Privacy fulfils data subject requests.
+	Privacy *PrivacyService
+	// ConsentRecords records and queries document acceptance.
+	ConsentRecords *ConsentRecordsService
+	// Roles reads global roles.
+	Roles *RolesService
+	// Config watches configuration for changes.
+	Config *ConfigService
+	// Auth signs users in directly, for native apps.
+	Auth *AuthService
+	// MFA manages users' second factors.
+	MFA *MFAService
+	// Passkeys verifies passkey sign-ins.
+	Passkeys *PasskeysService
+	// Recovery runs guided account recovery.
+	Recovery *RecoveryService
+	// Sessions manages user sessions and session limits.
+	Sessions *SessionsService
+	// TokenSettings configures token lifetimes per application.
+	TokenSettings *TokenSettingsService
+	// Authorize checks fine-grained permissions.
+	Authorize *AuthorizeService
+	// ServiceAccounts manages machine principals and their keys.
+	ServiceAccounts *ServiceAccountsService
+	// Tokens issues and redeems single-use tokens.
+	Tokens *TokensService
+	// Messaging configures email and SMS delivery.
+	Messaging *MessagingService
+	// Webhooks manages webhook endpoints and deliveries.
+	Webhooks *WebhooksService
+	// Events reads the event log for pull-based consumers.
+	Events *EventsService
+	// AuditLogs reads the audit log.
+	AuditLogs *AuditLogsService
+	// Approvals manages approvals for dual-control operations.
+	Approvals *ApprovalsService
+	// AdminTokens mints least-privilege management tokens.
+	AdminTokens *AdminTokensService
+	// Logs streams authentication logs.
+	Logs *LogsService
+	// Analytics reports sign-in and sign-up metrics.
+	Analytics *AnalyticsService
+	// Alerts configures anomaly alerts.
+	Alerts *AlertsService
+	// Segments manages saved user segments.
+	Segments *SegmentsService
+	// LifecyclePolicies schedules deactivation, deletion and password rotation.
+	LifecyclePolicies *LifecyclePoliciesService
+	// DelegatedAdmin mints admin sessions scoped to one organization.
+	DelegatedAdmin *DelegatedAdminService
+	// Flows runs headless authentication flows for custom login pages.
+	Flows *FlowsService
+	// EmailCanonicalization configures email alias detection.
+	EmailCanonicalization *EmailCanonicalizationService
+	// UsernamePolicy configures usernames and checks their availability.
+	UsernamePolicy *UsernamePolicyService
+	// PasswordPolicy configures password requirements.
+	PasswordPolicy *PasswordPolicyService
+
+	clock         Clock
+	rand          io.Reader
+	decryptionKey crypto.Decrypter
+	// serviceAccountKeyFile authenticates the client instead of a client
+	// secret; see WithServiceAccountKeyFile.
+	serviceAccountKeyFile string
+	// credentials, if set, replaces the static client ID and secret.
+	credentials CredentialProvider
+}
 
 // New creates a new AuthVital client.
 //
@@ -0,0 +1,131 @@
+// Package authvitalbridge forwards AuthVital events to a message bus, for
+// systems that consume identity changes from Kafka or NATS rather than
+// webhooks. It builds on authvital.Consumer, so forwarding is at least
+// once and resumes from a checkpoint.
+//
+//	pub, err := authvitalbridge.DialNATS(ctx, "nats://localhost:4222", nil)
+//	bridge := &authvitalbridge.Bridge{Publisher: pub}
+//	consumer := &authvital.Consumer{Name: "bus", Source: client.Events, Checkpoints: store}
+//	bridge.Attach(consumer)
+//	err = consumer.Run(ctx)
+//
+// A ready-made binary is in cmd/authvital-bridge.
+package authvitalbridge
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// SchemaHeader carries a message's schema tag.
+const SchemaHeader = "authvital-schema"
+
+// SchemaFor returns the schema tag of an event type's payload, such as
+// "authvital.user.password_changed.v1". The version changes only on
+// incompatible changes to the event data.
+func SchemaFor(t authvital.EventType) string {
+	return "authvital." + string(t) + ".v1"
+}
+
+// Message is one event as published to the bus.
+type Message struct {
+	// Subject is the NATS subject or Kafka topic.
+	Subject string
+	// Key orders messages: events of one tenant share a key.
+	Key     string
+	Headers map[string]string
+	Value   []byte
+}
+
+// Publisher sends messages to a bus. Publish must return only once the
+// broker has accepted the message.
+type Publisher interface {
+	Publish(ctx context.Context, m *Message) error
+}
+
+// PublisherFunc adapts a function, such as a wrapper around a Kafka
+// client's producer, to Publisher.
+type PublisherFunc func(ctx context.Context, m *Message) error
+
+// Publish implements Publisher.
+func (f PublisherFunc) Publish(ctx context.Context, m *Message) error { return f(ctx, m) }
+
+// Envelope is the JSON payload of a published message.
+type Envelope struct {
+	Schema        string              `json:"schema"`
+	ID            string              `json:"id"`
+	Type          authvital.EventType `json:"type"`
+	Timestamp     time.Time           `json:"timestamp"`
+	TenantID      string              `json:"tenant_id"`
+	ApplicationID string              `json:"application_id,omitempty"`
+	Data          json.RawMessage     `json:"data"`
+}
+
+// DefaultSubject maps events to "authvital.events.<type>", e.g.
+// "authvital.events.user.email_changed".
+func DefaultSubject(e *authvital.Event) string {
+	return "authvital.events." + string(e.Type)
+}
+
+// Bridge publishes events it receives to Publisher.
+type Bridge struct {
+	Publisher Publisher
+	// Subject picks the subject or topic of an event. Defaults to
+	// DefaultSubject; return a constant to send everything to one Kafka
+	// topic.
+	Subject func(e *authvital.Event) string
+	// Filter, when set, skips events for which it returns false.
+	Filter func(e *authvital.Event) bool
+}
+
+// Attach makes b handle every event of c without a handler of its own.
+func (b *Bridge) Attach(c *authvital.Consumer) {
+	c.HandleOther(b.Forward)
+}
+
+// Forward publishes e. It is an authvital.EventHandler.
+func (b *Bridge) Forward(ctx context.Context, e *authvital.Event) error {
+	if b.Filter != nil && !b.Filter(e) {
+		return nil
+	}
+	m, err := b.message(e)
+	if err != nil {
+		return err
+	}
+	return b.Publisher.Publish(ctx, m)
+}
+
+func (b *Bridge) message(e *authvital.Event) (*Message, error) {
+	schema := SchemaFor(e.Type)
+	value, err := json.Marshal(&Envelope{
+		Schema:        schema,
+		ID:            e.ID,
+		Type:          e.Type,
+		Timestamp:     e.Timestamp,
+		TenantID:      e.TenantID,
+		ApplicationID: e.ApplicationID,
+		Data:          e.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	subject := DefaultSubject
+	if b.Subject != nil {
+		subject = b.Subject
+	}
+	return &Message{
+		Subject: subject(e),
+		Key:     e.TenantID,
+		Headers: map[string]string{
+			SchemaHeader:   schema,
+			"content-type": "application/json",
+			// Nats-Msg-Id lets JetStream drop redeliveries; other
+			// brokers ignore it.
+			"Nats-Msg-Id": e.ID,
+		},
+		Value: value,
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Command authvital-bridge forwards AuthVital events to NATS or Kafka.
+//
+// It is configured through the environment:
+//
+//	AUTHVITAL_HOST, AUTHVITAL_CLIENT_ID, AUTHVITAL_CLIENT_SECRET
+//	BRIDGE_NATS_URL          publish to NATS, e.g. nats://localhost:4222
+//	BRIDGE_KAFKA_REST_URL    or to Kafka through a REST Proxy
+//	BRIDGE_KAFKA_CLUSTER_ID  required with BRIDGE_KAFKA_REST_URL
+//	BRIDGE_KAFKA_TOPIC       defaults to authvital.events
+//	BRIDGE_CHECKPOINT_FILE   defaults to authvital-bridge.checkpoint
+//	BRIDGE_DROP_FAILED       set to 1 to log and skip events that cannot
+//	                         be published instead of stopping
+//
+// NATS subjects are authvital.events.<event type>. Kafka records all go
+// to one topic, keyed by tenant ID.
+//
+// By default the bridge stops when an event cannot be published, leaving
+// the checkpoint before it so a restart retries it.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+	"github.com/authvital/authvital/sdks/go/authvitalbridge"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context) error {
+	client, err := authvital.New(
+		authvital.WithHost(os.Getenv("AUTHVITAL_HOST")),
+		authvital.WithClientID(os.Getenv("AUTHVITAL_CLIENT_ID")),
+		authvital.WithClientSecret(os.Getenv("AUTHVITAL_CLIENT_SECRET")),
+	)
+	if err != nil {
+		return err
+	}
+	bridge := &authvitalbridge.Bridge{}
+	switch {
+	case os.Getenv("BRIDGE_NATS_URL") != "":
+		pub, err := authvitalbridge.DialNATS(ctx, os.Getenv("BRIDGE_NATS_URL"), nil)
+		if err != nil {
+			return err
+		}
+		defer pub.Close()
+		bridge.Publisher = pub
+	case os.Getenv("BRIDGE_KAFKA_REST_URL") != "":
+		bridge.Publisher = &authvitalbridge.KafkaRESTPublisher{
+			URL:       os.Getenv("BRIDGE_KAFKA_REST_URL"),
+			ClusterID: os.Getenv("BRIDGE_KAFKA_CLUSTER_ID"),
+		}
+		topic := getenv("BRIDGE_KAFKA_TOPIC", "authvital.events")
+		bridge.Subject = func(*authvital.Event) string { return topic }
+	default:
+		return errors.New("set BRIDGE_NATS_URL or BRIDGE_KAFKA_REST_URL")
+	}
+	consumer := &authvital.Consumer{
+		Name:        "authvital-bridge",
+		Source:      client.Events,
+		Checkpoints: fileCheckpoints(getenv("BRIDGE_CHECKPOINT_FILE", "authvital-bridge.checkpoint")),
+	}
+	if os.Getenv("BRIDGE_DROP_FAILED") == "1" {
+		consumer.DeadLetter = func(ctx context.Context, e *authvital.Event, err error) error {
+			log.Printf("dropping event %s (%s): %v", e.ID, e.Type, err)
+			return nil
+		}
+	}
+	bridge.Attach(consumer)
+	log.Printf("forwarding events")
+	return consumer.Run(ctx)
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// fileCheckpoints stores the cursor in a file, replaced atomically.
+type fileCheckpoints string
+
+func (f fileCheckpoints) Load(ctx context.Context, name string) (string, error) {
+	b, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(b)), err
+}
+
+func (f fileCheckpoints) Save(ctx context.Context, name, cursor string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(string(f)), ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := fmt.Fprintln(tmp, cursor); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), string(f))
+}
@@ -0,0 +1,98 @@
+package authvitalbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// KafkaRESTPublisher publishes to Kafka through a REST Proxy implementing
+// the v3 records API, such as Confluent REST Proxy or Redpanda's
+// pandaproxy. To use a native Kafka client instead, wrap its producer in
+// a PublisherFunc.
+type KafkaRESTPublisher struct {
+	// URL is the proxy's base URL, e.g. "http://localhost:8082".
+	URL       string
+	ClusterID string
+	// Header adds to every request, e.g. for proxy authentication.
+	Header http.Header
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Publisher = (*KafkaRESTPublisher)(nil)
+
+type kafkaData struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+type kafkaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Publish implements Publisher, producing m to the topic m.Subject with
+// m.Key as the record key.
+func (p *KafkaRESTPublisher) Publish(ctx context.Context, m *Message) error {
+	rec := struct {
+		Key     kafkaData     `json:"key"`
+		Value   kafkaData     `json:"value"`
+		Headers []kafkaHeader `json:"headers,omitempty"`
+	}{
+		Key:   kafkaData{Type: "BINARY", Data: base64.StdEncoding.EncodeToString([]byte(m.Key))},
+		Value: kafkaData{Type: "BINARY", Data: base64.StdEncoding.EncodeToString(m.Value)},
+	}
+	names := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		rec.Headers = append(rec.Headers, kafkaHeader{Name: k, Value: base64.StdEncoding.EncodeToString([]byte(m.Headers[k]))})
+	}
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v3/clusters/%s/topics/%s/records",
+		strings.TrimRight(p.URL, "/"), url.PathEscape(p.ClusterID), url.PathEscape(m.Subject))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range p.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	hc := p.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var res struct {
+		ErrorCode int    `json:"error_code"`
+		Message   string `json:"message"`
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	json.Unmarshal(b, &res)
+	if resp.StatusCode >= 300 || res.ErrorCode >= 300 {
+		msg := res.Message
+		if msg == "" {
+			msg = resp.Status
+		}
+		return fmt.Errorf("authvitalbridge: produce to %s: %s", m.Subject, msg)
+	}
+	return nil
+}
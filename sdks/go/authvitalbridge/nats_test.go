@@ -0,0 +1,94 @@
+package authvitalbridge
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeNATS accepts connections, answering PINGs, and closes each one after
+// its first published message.
+func fakeNATS(t *testing.T) (addr string, accepted chan struct{}) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	accepted = make(chan struct{}, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- struct{}{}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte("INFO {}\r\n"))
+				r := bufio.NewReader(conn)
+				published := false
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					switch {
+					case strings.HasPrefix(line, "PING"):
+						conn.Write([]byte("PONG\r\n"))
+						if published {
+							return
+						}
+					case strings.HasPrefix(line, "HPUB"):
+						r.ReadString('\n') // headers end in a blank line
+						r.ReadString('\n')
+						r.ReadString('\n')
+						published = true
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String(), accepted
+}
+
+func TestNATSPublisherRedials(t *testing.T) {
+	addr, accepted := fakeNATS(t)
+	ctx := context.Background()
+	p, err := DialNATS(ctx, "nats://"+addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	m := &Message{Subject: "authvital.events.user.created", Value: []byte("{}")}
+	if err := p.Publish(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	// The server has hung up; the next publishes fail at most once
+	// before a new connection is made.
+	if err := p.Publish(ctx, m); err != nil {
+		if err := p.Publish(ctx, m); err != nil {
+			t.Fatalf("publish after redial: %v", err)
+		}
+	}
+	if n := len(accepted); n != 2 {
+		t.Fatalf("%d connections, want 2", n)
+	}
+}
+
+func TestNATSPublisherRejectsInjection(t *testing.T) {
+	p := &NATSPublisher{}
+	for _, m := range []*Message{
+		{Subject: ""},
+		{Subject: "a b"},
+		{Subject: "a\r\nPUB evil 0\r\n"},
+		{Subject: "a", Headers: map[string]string{"k": "v\r\nX: y"}},
+		{Subject: "a", Headers: map[string]string{"k\r\n": "v"}},
+	} {
+		if err := p.Publish(context.Background(), m); err == nil || !strings.Contains(err.Error(), "invalid NATS") {
+			t.Errorf("Publish(%q, %q) = %v, want invalid", m.Subject, m.Headers, err)
+		}
+	}
+}
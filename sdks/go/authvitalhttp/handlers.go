@@ -0,0 +1,189 @@
+// Package authvitalhttp provides ready-made HTTP handlers for signing users
+// in with AuthVital using the authorization code flow with PKCE.
+//
+//	cfg := &authvitalhttp.Config{
+//		Host:        "https://auth.example.com",
+//		ClientID:    clientID,
+//		RedirectURI: "https://app.example.com/auth/callback",
+//		States:      states,   // an authvital.StateStore
+//		Sessions:    sessions, // an *authvitalsession.Manager
+//		Verifier:    verifier, // accepting ID tokens for clientID
+//	}
+//	mux.Handle("/auth/login", authvitalhttp.NewLoginHandler(cfg))
+//	mux.Handle("/auth/callback", authvitalhttp.NewCallbackHandler(cfg))
+package authvitalhttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+	"github.com/authvital/authvital/sdks/go/authvitalsession"
+)
+
+// ErrNonceMismatch is returned when the ID token's nonce does not match
+// the one sent with the authorization request.
+var ErrNonceMismatch = errors.New("authvitalhttp: ID token nonce mismatch")
+
+// Config is shared by the login and callback handlers.
+type Config struct {
+	Host         string
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	// Scope defaults to authvital.DefaultScope.
+	Scope string
+	// States keeps the state, nonce and PKCE verifier between the two
+	// handlers.
+	States authvital.StateStore
+	// Sessions stores the signed-in session.
+	Sessions *authvitalsession.Manager
+	// Verifier verifies the ID token. Its audience must include ClientID.
+	Verifier *authvital.Verifier
+	// HTTPClient is used for the token request. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+	// DefaultReturnTo is where users go after sign-in when the login
+	// request had no return_to parameter. Defaults to "/".
+	DefaultReturnTo string
+	// Clock and Rand, normally the client's Clock and Rand, are used for
+	// the flow state and token expiry. They default to the system clock
+	// and crypto/rand.
+	Clock authvital.Clock
+	Rand  io.Reader
+
+	// PrepareAuthorize may adjust the authorize request, for example to
+	// add a tenant or hosted login extras.
+	PrepareAuthorize func(r *http.Request, p *authvital.AuthorizeParams) error
+	// PrepareSession may adjust or veto the session before it is saved.
+	// The session holds only the subject and refresh token.
+	PrepareSession func(r *http.Request, s *authvitalsession.Session, idToken authvital.Claims) error
+	// OnError renders failures. The default writes a plain error page
+	// with status 400 for callback and state errors and 502 otherwise.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// NewLoginHandler returns a handler that redirects to AuthVital to sign in.
+// A return_to query parameter, if it is a local path, is where the user
+// lands afterwards.
+func NewLoginHandler(cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := &authvital.AuthorizeParams{ClientID: cfg.ClientID, RedirectURI: cfg.RedirectURI, Scope: cfg.Scope, Clock: cfg.Clock, Rand: cfg.Rand}
+		if cfg.PrepareAuthorize != nil {
+			if err := cfg.PrepareAuthorize(r, p); err != nil {
+				cfg.fail(w, r, err)
+				return
+			}
+		}
+		returnTo := r.URL.Query().Get("return_to")
+		if !localPath(returnTo) {
+			returnTo = ""
+		}
+		u, err := authvital.StartFlow(w, r, cfg.States, cfg.Host, p, returnTo)
+		if err != nil {
+			cfg.fail(w, r, err)
+			return
+		}
+		http.Redirect(w, r, u, http.StatusFound)
+	})
+}
+
+// NewCallbackHandler returns the handler for RedirectURI. It exchanges the
+// code, verifies the ID token and its nonce, saves the session and
+// redirects to the page the user started from.
+func NewCallbackHandler(cfg *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.Verifier == nil || cfg.Sessions == nil {
+			cfg.fail(w, r, errors.New("authvitalhttp: Config.Verifier and Config.Sessions are required"))
+			return
+		}
+		st, code, err := authvital.ResumeFlow(w, r, cfg.States)
+		if err != nil {
+			cfg.fail(w, r, err)
+			return
+		}
+		tok, err := authvital.ExchangeCode(r.Context(), cfg.HTTPClient, cfg.Host, &authvital.ExchangeParams{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Code:         code,
+			RedirectURI:  st.RedirectURI,
+			CodeVerifier: st.CodeVerifier,
+			Clock:        cfg.Clock,
+		})
+		if err != nil {
+			cfg.fail(w, r, err)
+			return
+		}
+		if tok.IDToken == "" {
+			cfg.fail(w, r, errors.New("authvitalhttp: token response has no ID token; request the openid scope"))
+			return
+		}
+		claims, err := cfg.Verifier.Verify(r.Context(), tok.IDToken)
+		if err != nil {
+			cfg.fail(w, r, fmt.Errorf("authvitalhttp: ID token: %w", err))
+			return
+		}
+		if nonce, _ := claims["nonce"].(string); nonce != st.Nonce {
+			cfg.fail(w, r, ErrNonceMismatch)
+			return
+		}
+		// The access and ID tokens are left out of the cookie; the
+		// refresh token obtains new ones when they are needed.
+		s := &authvitalsession.Session{
+			Subject:      claims.Subject(),
+			RefreshToken: tok.RefreshToken,
+		}
+		if cfg.PrepareSession != nil {
+			if err := cfg.PrepareSession(r, s, claims); err != nil {
+				cfg.fail(w, r, err)
+				return
+			}
+		}
+		if err := cfg.Sessions.Save(w, r, s); err != nil {
+			cfg.fail(w, r, err)
+			return
+		}
+		returnTo := st.ReturnTo
+		if returnTo == "" {
+			returnTo = cfg.DefaultReturnTo
+		}
+		if returnTo == "" {
+			returnTo = "/"
+		}
+		http.Redirect(w, r, returnTo, http.StatusFound)
+	})
+}
+
+func (cfg *Config) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if cfg.OnError != nil {
+		cfg.OnError(w, r, err)
+		return
+	}
+	status := http.StatusBadGateway
+	var cbErr *authvital.CallbackError
+	if errors.As(err, &cbErr) || errors.Is(err, authvital.ErrFlowStateNotFound) || errors.Is(err, ErrNonceMismatch) {
+		status = http.StatusBadRequest
+	}
+	http.Error(w, "Sign-in failed. Please try again.", status)
+}
+
+// localPath reports whether p is a path on this site, preventing the
+// return_to parameter from being used as an open redirect. Browsers drop
+// tabs and newlines and read backslashes as slashes, so p may contain
+// neither.
+func localPath(p string) bool {
+	if !strings.HasPrefix(p, "/") || strings.ContainsRune(p, '\\') {
+		return false
+	}
+	for _, r := range p {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	u, err := url.Parse(p)
+	return err == nil && u.Scheme == "" && u.Host == "" && !strings.HasPrefix(u.Path, "//")
+}
@@ -0,0 +1,30 @@
+package authvitalhttp
+
+import "testing"
+
+func TestLocalPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/settings?tab=security#mfa", true},
+		{"/a//b", true},
+		{"", false},
+		{"settings", false},
+		{"//evil.com", false},
+		{"/\\evil.com", false},
+		{"/\t/evil.com", false},
+		{"/\n/evil.com", false},
+		{"/\r\n/evil.com", false},
+		{"/%2F/evil.com", false},
+		{"/x\x7f", false},
+		{"https://evil.com/", false},
+		{"/\\\\evil.com", false},
+	}
+	for _, tt := range tests {
+		if got := localPath(tt.path); got != tt.want {
+			t.Errorf("localPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
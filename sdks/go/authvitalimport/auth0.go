@@ -0,0 +1,83 @@
+package authvitalimport
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// Auth0Reader reads an Auth0 bulk user export (NDJSON or JSON).
+type Auth0Reader struct {
+	recs    *jsonRecords
+	hashes  map[string]string
+	mapping Mapping
+}
+
+// NewAuth0Reader reads users from an export job's output. hashes, which
+// may be nil, is the password hash export Auth0 support provides; its
+// bcrypt hashes are attached to users by ID.
+func NewAuth0Reader(users, hashes io.Reader, m Mapping) (*Auth0Reader, error) {
+	recs, err := newJSONRecords(users)
+	if err != nil {
+		return nil, err
+	}
+	r := &Auth0Reader{recs: recs, hashes: map[string]string{}, mapping: m}
+	if hashes != nil {
+		hr, err := newJSONRecords(hashes)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			rec, err := hr.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if h := str(rec, "passwordHash"); h != "" {
+				r.hashes[str(rec, "_id.$oid")] = h
+			}
+		}
+	}
+	return r, nil
+}
+
+// Next implements Reader.
+func (r *Auth0Reader) Next() (*authvital.ImportUser, error) {
+	rec, err := r.recs.next()
+	if err != nil {
+		return nil, err
+	}
+	u := &authvital.ImportUser{
+		ExternalID:    str(rec, "user_id"),
+		Email:         str(rec, "email"),
+		EmailVerified: boolean(rec, "email_verified"),
+		Phone:         str(rec, "phone_number"),
+		Blocked:       boolean(rec, "blocked"),
+	}
+	if u.ExternalID == "" {
+		// Password hash exports use the database ID instead.
+		u.ExternalID = "auth0|" + str(rec, "_id.$oid")
+	}
+	if t, err := time.Parse(time.RFC3339, str(rec, "created_at")); err == nil {
+		u.CreatedAt = t
+	}
+	setProfile(u, "name", str(rec, "name"))
+	setProfile(u, "givenName", str(rec, "given_name"))
+	setProfile(u, "familyName", str(rec, "family_name"))
+	setProfile(u, "nickname", str(rec, "nickname"))
+	setProfile(u, "picture", str(rec, "picture"))
+	hash := str(rec, "passwordHash")
+	if hash == "" {
+		_, id, _ := strings.Cut(u.ExternalID, "|")
+		hash = r.hashes[id]
+	}
+	if hash != "" {
+		u.Password = &authvital.PasswordHash{Algorithm: authvital.HashBcrypt, Hash: hash}
+	}
+	r.mapping.apply(rec, u)
+	return u, nil
+}
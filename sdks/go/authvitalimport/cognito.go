@@ -0,0 +1,57 @@
+package authvitalimport
+
+import (
+	"encoding/csv"
+	"io"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// CognitoReader reads an Amazon Cognito user pool CSV export, with the
+// header row Cognito's CSV import template uses. Cognito never exports
+// password hashes: imported users must reset their password, or be
+// migrated on sign-in.
+type CognitoReader struct {
+	r       *csv.Reader
+	header  []string
+	mapping Mapping
+}
+
+// NewCognitoReader reads the header row of r. Mapping keys are column
+// names, such as "custom:tier".
+func NewCognitoReader(r io.Reader, m Mapping) (*CognitoReader, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	cr.FieldsPerRecord = len(header)
+	return &CognitoReader{r: cr, header: header, mapping: m}, nil
+}
+
+// Next implements Reader.
+func (r *CognitoReader) Next() (*authvital.ImportUser, error) {
+	row, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	rec := make(map[string]any, len(row))
+	for i, v := range row {
+		if v != "" {
+			rec[r.header[i]] = v
+		}
+	}
+	u := &authvital.ImportUser{
+		ExternalID:    str(rec, "cognito:username"),
+		Email:         str(rec, "email"),
+		EmailVerified: boolean(rec, "email_verified"),
+		Phone:         str(rec, "phone_number"),
+	}
+	setProfile(u, "name", str(rec, "name"))
+	setProfile(u, "givenName", str(rec, "given_name"))
+	setProfile(u, "familyName", str(rec, "family_name"))
+	setProfile(u, "nickname", str(rec, "nickname"))
+	setProfile(u, "picture", str(rec, "picture"))
+	r.mapping.apply(rec, u)
+	return u, nil
+}
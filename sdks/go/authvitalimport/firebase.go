@@ -0,0 +1,105 @@
+package authvitalimport
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// FirebaseHashConfig is the project's password hash configuration, shown
+// in the Firebase console under Authentication, Users, "Password hash
+// parameters". Keys are base64 as displayed.
+type FirebaseHashConfig struct {
+	SignerKey     string
+	SaltSeparator string
+	Rounds        int
+	MemoryCost    int
+}
+
+// FirebaseReader reads the JSON output of `firebase auth:export`.
+type FirebaseReader struct {
+	dec     *json.Decoder
+	hash    *FirebaseHashConfig
+	mapping Mapping
+}
+
+// NewFirebaseReader returns a reader over r. hash may be nil to import
+// users without passwords. customAttributes are decoded, so mapping keys
+// like "customAttributes.role" work.
+func NewFirebaseReader(r io.Reader, hash *FirebaseHashConfig, m Mapping) (*FirebaseReader, error) {
+	dec := json.NewDecoder(r)
+	// Stream {"users": [ ... ]} without loading it whole. Only a top-level
+	// key counts: other members are skipped whole, so a nested "users" key
+	// or string value cannot be mistaken for it.
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("authvitalimport: export is not a JSON object")
+	}
+	for {
+		if !dec.More() {
+			return nil, errors.New("authvitalimport: export has no users array")
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if tok == "users" {
+			break
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("authvitalimport: users is not an array")
+	}
+	return &FirebaseReader{dec: dec, hash: hash, mapping: m}, nil
+}
+
+// Next implements Reader.
+func (r *FirebaseReader) Next() (*authvital.ImportUser, error) {
+	if !r.dec.More() {
+		return nil, io.EOF
+	}
+	var rec map[string]any
+	if err := r.dec.Decode(&rec); err != nil {
+		return nil, err
+	}
+	if s := str(rec, "customAttributes"); s != "" {
+		var attrs map[string]any
+		if json.Unmarshal([]byte(s), &attrs) == nil {
+			rec["customAttributes"] = attrs
+		}
+	}
+	u := &authvital.ImportUser{
+		ExternalID:    str(rec, "localId"),
+		Email:         str(rec, "email"),
+		EmailVerified: boolean(rec, "emailVerified"),
+		Phone:         str(rec, "phoneNumber"),
+		Blocked:       boolean(rec, "disabled"),
+	}
+	if ms, err := strconv.ParseInt(str(rec, "createdAt"), 10, 64); err == nil {
+		u.CreatedAt = time.UnixMilli(ms)
+	}
+	setProfile(u, "name", str(rec, "displayName"))
+	setProfile(u, "picture", str(rec, "photoUrl"))
+	if h := str(rec, "passwordHash"); h != "" && r.hash != nil {
+		u.Password = &authvital.PasswordHash{
+			Algorithm: authvital.HashFirebaseScrypt,
+			Hash:      h,
+			Salt:      str(rec, "salt"),
+			Rounds:    r.hash.Rounds,
+			Params: map[string]string{
+				"signerKey":     r.hash.SignerKey,
+				"saltSeparator": r.hash.SaltSeparator,
+				"memoryCost":    strconv.Itoa(r.hash.MemoryCost),
+			},
+		}
+	}
+	r.mapping.apply(rec, u)
+	return u, nil
+}
@@ -0,0 +1,72 @@
+package authvitalimport
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFirebaseReaderFindsTopLevelUsers(t *testing.T) {
+	const export = `{
+		"meta": {"users": "not this", "note": "users"},
+		"users": [
+			{"localId": "u1", "email": "a@example.com"},
+			{"localId": "u2", "email": "b@example.com", "disabled": true}
+		]
+	}`
+	r, err := NewFirebaseReader(strings.NewReader(export), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for {
+		u, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, u.ExternalID)
+	}
+	if strings.Join(ids, ",") != "u1,u2" {
+		t.Fatalf("users = %v, want [u1 u2]", ids)
+	}
+}
+
+func TestFirebaseReaderRejectsMissingUsers(t *testing.T) {
+	for _, export := range []string{
+		`{"meta": {"users": []}}`,
+		`["users", []]`,
+		`{"note": "users"}`,
+	} {
+		if _, err := NewFirebaseReader(strings.NewReader(export), nil, nil); err == nil {
+			t.Errorf("%s: accepted an export without a top-level users array", export)
+		}
+	}
+}
+
+func TestOktaReaderStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status            string
+		verified, blocked bool
+	}{
+		{"ACTIVE", true, false},
+		{"LOCKED_OUT", true, false},
+		{"SUSPENDED", false, true},
+		{"DEPROVISIONED", false, true},
+		{"STAGED", false, false},
+	} {
+		r, err := NewOktaReader(strings.NewReader(`[{"id": "00u1", "status": "`+tc.status+`", "profile": {"email": "a@example.com"}}]`), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.EmailVerified != tc.verified || u.Blocked != tc.blocked {
+			t.Errorf("%s: verified %v blocked %v, want %v %v", tc.status, u.EmailVerified, u.Blocked, tc.verified, tc.blocked)
+		}
+	}
+}
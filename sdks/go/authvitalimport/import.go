@@ -0,0 +1,198 @@
+// Package authvitalimport migrates users from other identity providers.
+// Readers parse Auth0, Okta, Amazon Cognito and Firebase exports,
+// including password hashes where the provider exports them, and Import
+// feeds the result to the bulk import API.
+//
+//	f, _ := os.Open("auth0-users.ndjson")
+//	r, err := authvitalimport.NewAuth0Reader(f, nil, authvitalimport.Mapping{
+//		"app_metadata.plan": "plan",
+//	})
+//	jobs, err := authvitalimport.Import(ctx, client.Users, client.Jobs, r, nil)
+package authvitalimport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// Reader yields users from an export. Next returns io.EOF after the last
+// one.
+type Reader interface {
+	Next() (*authvital.ImportUser, error)
+}
+
+// Mapping copies custom source attributes into profile fields. Keys are
+// dotted paths into the source record, such as "user_metadata.department"
+// for Auth0 or "custom:tier" for Cognito; values are profile field names.
+type Mapping map[string]string
+
+// apply copies the mapped attributes of rec into u.Profile.
+func (m Mapping) apply(rec map[string]any, u *authvital.ImportUser) {
+	for path, field := range m {
+		if v, ok := lookup(rec, path); ok {
+			if u.Profile == nil {
+				u.Profile = map[string]any{}
+			}
+			u.Profile[field] = v
+		}
+	}
+}
+
+// lookup resolves a dotted path in rec. A key containing dots is matched
+// whole before the path is split.
+func lookup(rec map[string]any, path string) (any, bool) {
+	if v, ok := rec[path]; ok {
+		return v, true
+	}
+	head, rest, ok := strings.Cut(path, ".")
+	if !ok {
+		return nil, false
+	}
+	sub, _ := rec[head].(map[string]any)
+	if sub == nil {
+		return nil, false
+	}
+	return lookup(sub, rest)
+}
+
+func str(rec map[string]any, path string) string {
+	v, _ := lookup(rec, path)
+	s, _ := v.(string)
+	return s
+}
+
+func boolean(rec map[string]any, path string) bool {
+	switch v, _ := lookup(rec, path); v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// setProfile sets a profile field if v is not empty.
+func setProfile(u *authvital.ImportUser, field, v string) {
+	if v == "" {
+		return
+	}
+	if u.Profile == nil {
+		u.Profile = map[string]any{}
+	}
+	u.Profile[field] = v
+}
+
+// jsonRecords reads a JSON array of objects or newline-delimited objects.
+type jsonRecords struct {
+	dec   *json.Decoder
+	array bool
+}
+
+func newJSONRecords(r io.Reader) (*jsonRecords, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return &jsonRecords{dec: json.NewDecoder(br)}, nil
+			}
+			return nil, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b[0])) {
+			break
+		}
+		br.ReadByte()
+	}
+	j := &jsonRecords{dec: json.NewDecoder(br)}
+	if b, _ := br.Peek(1); len(b) == 1 && b[0] == '[' {
+		if _, err := j.dec.Token(); err != nil {
+			return nil, err
+		}
+		j.array = true
+	}
+	return j, nil
+}
+
+func (j *jsonRecords) next() (map[string]any, error) {
+	if j.array && !j.dec.More() {
+		return nil, io.EOF
+	}
+	var rec map[string]any
+	if err := j.dec.Decode(&rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Options configure Import.
+type Options struct {
+	// BatchSize defaults to authvital.MaxImportUsers.
+	BatchSize int
+	// OnJob is called when each batch's job finishes.
+	OnJob func(*authvital.Job)
+	// PhoneRegion, when set, normalizes phone numbers to E.164 with
+	// authvital.NormalizePhone, treating national numbers as being in this
+	// region. Records with invalid numbers fail the import.
+	PhoneRegion string
+}
+
+// Import reads all users from r and imports them in batches, waiting for
+// each batch's job before starting the next. It returns the finished
+// jobs; check their ErrorCount and list item errors with jobs.ListErrors.
+func Import(ctx context.Context, users *authvital.UsersService, jobs *authvital.JobsService, r Reader, opts *Options) ([]*authvital.Job, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	size := opts.BatchSize
+	if size <= 0 || size > authvital.MaxImportUsers {
+		size = authvital.MaxImportUsers
+	}
+	var done []*authvital.Job
+	batch := make([]authvital.ImportUser, 0, size)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		job, err := users.Import(ctx, batch)
+		if err != nil {
+			return err
+		}
+		if job, err = jobs.Wait(ctx, job.ID, nil); err != nil {
+			return err
+		}
+		done = append(done, job)
+		if opts.OnJob != nil {
+			opts.OnJob(job)
+		}
+		batch = batch[:0]
+		return nil
+	}
+	for n := 1; ; n++ {
+		u, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return done, fmt.Errorf("authvitalimport: record %d: %w", n, err)
+		}
+		if opts.PhoneRegion != "" && u.Phone != "" {
+			if u.Phone, err = authvital.NormalizePhone(u.Phone, opts.PhoneRegion); err != nil {
+				return done, fmt.Errorf("authvitalimport: record %d: %w", n, err)
+			}
+		}
+		batch = append(batch, *u)
+		if len(batch) == size {
+			if err := flush(); err != nil {
+				return done, err
+			}
+		}
+	}
+	return done, flush()
+}
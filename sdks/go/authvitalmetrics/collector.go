@@ -0,0 +1,187 @@
+// Package authvitalmetrics exports AuthVital SDK telemetry in the
+// Prometheus text format, for services that use Prometheus without
+// OpenTelemetry. It has no dependencies beyond the SDK.
+//
+//	metrics := authvitalmetrics.NewCollector()
+//	client, err := authvital.New(metrics.ClientOption(), ...)
+//	http.Handle("/metrics", metrics)
+package authvitalmetrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// DefaultBuckets are the latency histogram buckets, in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector accumulates SDK metrics. It implements authvital.Observer and
+// serves the metrics over HTTP.
+type Collector struct {
+	mu              sync.Mutex
+	requests        map[[4]string]uint64 // method, route, code, error
+	requestDuration map[[2]string]*histogram
+	cacheHits       uint64
+	cacheMisses     uint64
+	refreshes       *histogram
+	refreshErrors   uint64
+}
+
+var _ authvital.Observer = (*Collector)(nil)
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{
+		requests:        map[[4]string]uint64{},
+		requestDuration: map[[2]string]*histogram{},
+		refreshes:       newHistogram(),
+	}
+}
+
+// ClientOption returns the client option that reports to c.
+func (c *Collector) ClientOption() authvital.Option {
+	return authvital.WithObserver(c)
+}
+
+// ObserveRequest implements authvital.Observer.
+func (c *Collector) ObserveRequest(info authvital.RequestInfo) {
+	code := strconv.Itoa(info.Status)
+	if info.Status == 0 {
+		code = info.ErrorCode
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests[[4]string{info.Method, info.Route, code, info.ErrorCode}]++
+	k := [2]string{info.Method, info.Route}
+	h := c.requestDuration[k]
+	if h == nil {
+		h = newHistogram()
+		c.requestDuration[k] = h
+	}
+	h.observe(info.Duration)
+}
+
+// ObserveTokenCache implements authvital.Observer.
+func (c *Collector) ObserveTokenCache(hit bool) {
+	c.mu.Lock()
+	if hit {
+		c.cacheHits++
+	} else {
+		c.cacheMisses++
+	}
+	c.mu.Unlock()
+}
+
+// ObserveTokenRefresh implements authvital.Observer.
+func (c *Collector) ObserveTokenRefresh(d time.Duration, err error) {
+	c.mu.Lock()
+	c.refreshes.observe(d)
+	if err != nil {
+		c.refreshErrors++
+	}
+	c.mu.Unlock()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var b strings.Builder
+
+	header(&b, "authvital_requests_total", "counter", "AuthVital API requests by method, route, status code, and API error code.")
+	keys := make([][4]string, 0, len(c.requests))
+	for k := range c.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00") })
+	for _, k := range keys {
+		fmt.Fprintf(&b, "authvital_requests_total{method=%s,route=%s,code=%s,error=%s} %d\n", quote(k[0]), quote(k[1]), quote(k[2]), quote(k[3]), c.requests[k])
+	}
+
+	header(&b, "authvital_request_duration_seconds", "histogram", "AuthVital API request latency.")
+	dkeys := make([][2]string, 0, len(c.requestDuration))
+	for k := range c.requestDuration {
+		dkeys = append(dkeys, k)
+	}
+	sort.Slice(dkeys, func(i, j int) bool { return dkeys[i][0]+"\x00"+dkeys[i][1] < dkeys[j][0]+"\x00"+dkeys[j][1] })
+	for _, k := range dkeys {
+		c.requestDuration[k].write(&b, "authvital_request_duration_seconds", "method="+quote(k[0])+",route="+quote(k[1]))
+	}
+
+	header(&b, "authvital_token_cache_hits_total", "counter", "Access token lookups served from cache.")
+	fmt.Fprintf(&b, "authvital_token_cache_hits_total %d\n", c.cacheHits)
+	header(&b, "authvital_token_cache_misses_total", "counter", "Access token lookups that required a new token.")
+	fmt.Fprintf(&b, "authvital_token_cache_misses_total %d\n", c.cacheMisses)
+
+	header(&b, "authvital_token_refresh_duration_seconds", "histogram", "Latency of fetching new access tokens.")
+	c.refreshes.write(&b, "authvital_token_refresh_duration_seconds", "")
+	header(&b, "authvital_token_refresh_errors_total", "counter", "Failed access token fetches.")
+	fmt.Fprintf(&b, "authvital_token_refresh_errors_total %d\n", c.refreshErrors)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func header(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func quote(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(v) + `"`
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, non-cumulative
+	count  uint64
+	sum    float64
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make([]uint64, len(DefaultBuckets))}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	s := d.Seconds()
+	h.count++
+	h.sum += s
+	for i, le := range DefaultBuckets {
+		if s <= le {
+			h.counts[i]++
+			return
+		}
+	}
+}
+
+func (h *histogram) write(b *strings.Builder, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	var cum uint64
+	for i, le := range DefaultBuckets {
+		cum += h.counts[i]
+		fmt.Fprintf(b, "%s_bucket{%s%sle=%q} %d\n", name, labels, sep, strconv.FormatFloat(le, 'g', -1, 64), cum)
+	}
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+	braces := ""
+	if labels != "" {
+		braces = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, braces, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, braces, h.count)
+}
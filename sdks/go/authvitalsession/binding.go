@@ -0,0 +1,98 @@
+package authvitalsession
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrFingerprintMismatch is returned by Load when a bound session is
+// presented by a different client than the one it was issued to.
+var ErrFingerprintMismatch = errors.New("authvitalsession: session fingerprint mismatch")
+
+// MismatchPolicy selects what happens when a bound session's fingerprint
+// does not match the request.
+type MismatchPolicy int
+
+const (
+	// RejectMismatch makes Load fail with ErrFingerprintMismatch.
+	RejectMismatch MismatchPolicy = iota
+	// ReportMismatch accepts the session and calls Binding.OnMismatch, for
+	// measuring false positives before enforcing.
+	ReportMismatch
+)
+
+// Binding ties a session cookie to a fingerprint of the client it was
+// issued to, so a stolen cookie is useless from another network or
+// browser. Binding to the exact IP breaks mobile users and dual-stack
+// clients, so the address is reduced to a prefix first.
+type Binding struct {
+	// IPv4Prefix and IPv6Prefix are the address prefix lengths included
+	// in the fingerprint, defaulting to 24 and 48. Set IgnoreIP to leave
+	// the address out entirely.
+	IPv4Prefix int
+	IPv6Prefix int
+	IgnoreIP   bool
+	// UserAgent includes a hash of the User-Agent header.
+	UserAgent bool
+	// ClientIP extracts the client address. It defaults to the host of
+	// r.RemoteAddr; behind a proxy, supply one that reads the trusted
+	// forwarding header.
+	ClientIP func(r *http.Request) net.IP
+	Policy   MismatchPolicy
+	// OnMismatch is called for every mismatch, whatever the Policy.
+	OnMismatch func(r *http.Request, s *Session)
+}
+
+func (b *Binding) check(r *http.Request, s *Session) error {
+	if subtle.ConstantTimeCompare([]byte(s.Fingerprint), []byte(b.fingerprint(r))) == 1 {
+		return nil
+	}
+	if b.OnMismatch != nil {
+		b.OnMismatch(r, s)
+	}
+	if b.Policy == ReportMismatch {
+		return nil
+	}
+	return ErrFingerprintMismatch
+}
+
+func (b *Binding) fingerprint(r *http.Request) string {
+	h := sha256.New()
+	if !b.IgnoreIP {
+		h.Write([]byte("ip:"))
+		h.Write([]byte(b.network(r)))
+	}
+	if b.UserAgent {
+		h.Write([]byte("\nua:"))
+		h.Write([]byte(r.UserAgent()))
+	}
+	return b64.EncodeToString(h.Sum(nil)[:16])
+}
+
+// network returns the client's address masked to the configured prefix.
+func (b *Binding) network(r *http.Request) string {
+	var ip net.IP
+	if b.ClientIP != nil {
+		ip = b.ClientIP(r)
+	} else if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = net.ParseIP(host)
+	}
+	if ip == nil {
+		return ""
+	}
+	if v4 := ip.To4(); v4 != nil {
+		bits := b.IPv4Prefix
+		if bits <= 0 || bits > 32 {
+			bits = 24
+		}
+		return v4.Mask(net.CIDRMask(bits, 32)).String()
+	}
+	bits := b.IPv6Prefix
+	if bits <= 0 || bits > 128 {
+		bits = 48
+	}
+	return ip.Mask(net.CIDRMask(bits, 128)).String()
+}
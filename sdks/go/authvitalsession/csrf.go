@@ -0,0 +1,172 @@
+package authvitalsession
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrCSRFMismatch is reported to CSRF.OnFailure when an unsafe request
+// carries no valid CSRF token.
+var ErrCSRFMismatch = errors.New("authvitalsession: missing or invalid CSRF token")
+
+// CSRFMode selects how CSRF tokens are kept.
+type CSRFMode int
+
+const (
+	// SynchronizerToken checks submitted tokens against Session.CSRFToken.
+	// It needs a session, so it only protects signed-in requests.
+	SynchronizerToken CSRFMode = iota
+	// DoubleSubmit keeps the token in its own cookie, signed with the
+	// Manager's secret and bound to the current session, if any. It also
+	// protects pre-login forms such as sign-up.
+	DoubleSubmit
+)
+
+// CSRF is middleware rejecting state-changing requests that do not echo
+// the CSRF token in a header or form field. Safe methods (GET, HEAD,
+// OPTIONS, TRACE) always pass and make the token available via CSRFToken.
+//
+// Tokens rotate when the user signs in: Save gives a new session a new
+// synchronizer token, and double-submit tokens are bound to the session's
+// subject and issue time.
+type CSRF struct {
+	Sessions *Manager
+	Mode     CSRFMode
+	// HeaderName defaults to "X-CSRF-Token".
+	HeaderName string
+	// FieldName is the form field checked when the header is absent.
+	// Defaults to "csrf_token".
+	FieldName string
+	// CookieName is the DoubleSubmit cookie. Defaults to "authvital_csrf".
+	CookieName string
+	// OnFailure handles rejected requests. The default responds 403.
+	OnFailure http.Handler
+}
+
+type csrfKey struct{}
+
+// CSRFToken returns the token to embed in forms or send as a header, as
+// made available by CSRF.Handler. It is empty when there is none, such
+// as in SynchronizerToken mode without a session.
+func CSRFToken(r *http.Request) string {
+	tok, _ := r.Context().Value(csrfKey{}).(string)
+	return tok
+}
+
+// Handler wraps next with CSRF protection. It should run inside
+// Manager.Middleware so the session is loaded once.
+func (c *CSRF) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := c.session(r)
+		token := c.expected(r, s)
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			if token == "" && c.Mode == DoubleSubmit {
+				var err error
+				if token, err = c.issue(w, s); err != nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+			}
+		default:
+			if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.submitted(r))) != 1 {
+				c.fail(w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, token)))
+	})
+}
+
+func (c *CSRF) session(r *http.Request) *Session {
+	if s, ok := FromContext(r.Context()); ok {
+		return s
+	}
+	s, _ := c.Sessions.Load(r)
+	return s
+}
+
+// expected returns the currently valid token, or "" if there is none.
+func (c *CSRF) expected(r *http.Request, s *Session) string {
+	if c.Mode == SynchronizerToken {
+		if s == nil {
+			return ""
+		}
+		return s.CSRFToken
+	}
+	ck, err := r.Cookie(c.cookieName())
+	if err != nil {
+		return ""
+	}
+	nonce, sig, ok := strings.Cut(ck.Value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(c.sign(nonce, s))) {
+		return ""
+	}
+	return ck.Value
+}
+
+func (c *CSRF) issue(w http.ResponseWriter, s *Session) (string, error) {
+	nonce, err := c.Sessions.randomToken()
+	if err != nil {
+		return "", err
+	}
+	tok := nonce + "." + c.sign(nonce, s)
+	opts := c.Sessions.opts
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.cookieName(),
+		Value:    tok,
+		Path:     opts.Path,
+		Domain:   opts.Domain,
+		HttpOnly: true,
+		Secure:   !opts.Insecure,
+		SameSite: opts.SameSite,
+	})
+	return tok, nil
+}
+
+// sign binds a double-submit nonce to the session, so a token planted
+// before sign-in stops working after it.
+func (c *CSRF) sign(nonce string, s *Session) string {
+	mac := hmac.New(sha256.New, c.Sessions.macKey)
+	mac.Write([]byte(nonce))
+	if s != nil {
+		mac.Write([]byte("\x00" + s.Subject + "\x00" + strconv.FormatInt(s.IssuedAt.UnixNano(), 10)))
+	}
+	return b64.EncodeToString(mac.Sum(nil))
+}
+
+func (c *CSRF) submitted(r *http.Request) string {
+	header := c.HeaderName
+	if header == "" {
+		header = "X-CSRF-Token"
+	}
+	if v := r.Header.Get(header); v != "" {
+		return v
+	}
+	field := c.FieldName
+	if field == "" {
+		field = "csrf_token"
+	}
+	return r.PostFormValue(field)
+}
+
+func (c *CSRF) cookieName() string {
+	if c.CookieName != "" {
+		return c.CookieName
+	}
+	return "authvital_csrf"
+}
+
+func (c *CSRF) fail(w http.ResponseWriter, r *http.Request) {
+	if c.OnFailure != nil {
+		c.OnFailure.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, ErrCSRFMismatch.Error(), http.StatusForbidden)
+}
@@ -0,0 +1,262 @@
+// Package authvitalsession keeps a signed-in user's AuthVital session in an
+// encrypted cookie, so web applications need no server-side session store.
+//
+//	sessions, err := authvitalsession.NewManager(secret, authvitalsession.Options{})
+//	...
+//	err = sessions.Save(w, r, &authvitalsession.Session{Subject: claims.Subject(), ...})
+//	s, err := sessions.Load(r)
+package authvitalsession
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+var (
+	// ErrNoSession is returned by Load when the request carries no valid
+	// session cookie.
+	ErrNoSession = errors.New("authvitalsession: no session")
+	// ErrSessionExpired is returned by Load for a session past its MaxAge.
+	ErrSessionExpired = errors.New("authvitalsession: session expired")
+	// ErrSessionTooLarge is returned by Save when the encoded cookie would
+	// exceed MaxCookieSize, which browsers silently drop.
+	ErrSessionTooLarge = errors.New("authvitalsession: session too large for a cookie")
+)
+
+// MaxCookieSize is the largest Set-Cookie value, attributes included, that
+// all major browsers store.
+const MaxCookieSize = 4096
+
+// DefaultCookieName is the session cookie name unless Options.CookieName is set.
+const DefaultCookieName = "authvital_session"
+
+// DefaultMaxAge bounds a session's lifetime unless Options.MaxAge is set.
+const DefaultMaxAge = 24 * time.Hour
+
+// Session is the state kept for a signed-in user. Browsers limit cookies to
+// about 4 KB, so keep Values small.
+type Session struct {
+	Subject      string            `json:"sub"`
+	AccessToken  string            `json:"at,omitempty"`
+	RefreshToken string            `json:"rt,omitempty"`
+	IDToken      string            `json:"idt,omitempty"`
+	ExpiresAt    time.Time         `json:"exp,omitempty"`
+	Values       map[string]string `json:"v,omitempty"`
+	IssuedAt     time.Time         `json:"iat"`
+	// Fingerprint is set by Save when Options.Binding is configured.
+	Fingerprint string `json:"fp,omitempty"`
+	// CSRFToken is the synchronizer token for this session. Save generates
+	// one for a new session, so each sign-in gets a fresh token.
+	CSRFToken string `json:"csrf,omitempty"`
+}
+
+// Options configures a Manager. The zero value is usable.
+type Options struct {
+	// CookieName defaults to DefaultCookieName.
+	CookieName string
+	// Path defaults to "/".
+	Path   string
+	Domain string
+	// MaxAge defaults to DefaultMaxAge.
+	MaxAge time.Duration
+	// SameSite defaults to Lax, which lets the cookie accompany the
+	// top-level redirect back from AuthVital.
+	SameSite http.SameSite
+	// Insecure omits the Secure attribute, for local HTTP development only.
+	Insecure bool
+	// Binding, if set, ties sessions to the client that created them.
+	Binding *Binding
+	// Clock sets IssuedAt and checks MaxAge, normally the AuthVital client's
+	// Clock. It defaults to the system clock.
+	Clock authvital.Clock
+	// Rand supplies CSRF tokens and encryption nonces. It defaults to
+	// crypto/rand.
+	Rand io.Reader
+}
+
+// Manager reads and writes session cookies.
+type Manager struct {
+	aead   cipher.AEAD
+	macKey []byte
+	opts   Options
+}
+
+// NewManager returns a Manager encrypting with a key derived from secret,
+// which must be at least 32 bytes and shared by all instances.
+func NewManager(secret []byte, opts Options) (*Manager, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("authvitalsession: secret must be at least 32 bytes")
+	}
+	key := sha256.Sum256(secret)
+	mac := sha256.Sum256(append([]byte("authvitalsession csrf\x00"), secret...))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CookieName == "" {
+		opts.CookieName = DefaultCookieName
+	}
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	if opts.SameSite == 0 {
+		opts.SameSite = http.SameSiteLaxMode
+	}
+	if opts.Clock == nil {
+		opts.Clock = authvital.SystemClock{}
+	}
+	if opts.Rand == nil {
+		opts.Rand = rand.Reader
+	}
+	return &Manager{aead: aead, macKey: mac[:], opts: opts}, nil
+}
+
+// Save writes s as the session cookie. IssuedAt and CSRFToken are set if
+// empty. It returns ErrSessionTooLarge, writing nothing, if the cookie
+// would be too large.
+func (m *Manager) Save(w http.ResponseWriter, r *http.Request, s *Session) error {
+	if s.IssuedAt.IsZero() {
+		s.IssuedAt = m.opts.Clock.Now()
+	}
+	if s.CSRFToken == "" {
+		tok, err := m.randomToken()
+		if err != nil {
+			return err
+		}
+		s.CSRFToken = tok
+	}
+	if m.opts.Binding != nil {
+		s.Fingerprint = m.opts.Binding.fingerprint(r)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	v, err := m.seal(b)
+	if err != nil {
+		return err
+	}
+	c := m.cookie(v, int(m.opts.MaxAge/time.Second))
+	if n := len(c.String()); n > MaxCookieSize {
+		return fmt.Errorf("%w: %d bytes", ErrSessionTooLarge, n)
+	}
+	http.SetCookie(w, c)
+	return nil
+}
+
+// Load returns the request's session. It returns ErrNoSession if there is
+// none or it fails to decrypt, ErrSessionExpired if it is too old, and
+// ErrFingerprintMismatch if Binding rejects it.
+func (m *Manager) Load(r *http.Request) (*Session, error) {
+	c, err := r.Cookie(m.opts.CookieName)
+	if err != nil {
+		return nil, ErrNoSession
+	}
+	b, err := m.open(c.Value)
+	if err != nil {
+		return nil, ErrNoSession
+	}
+	var s Session
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, ErrNoSession
+	}
+	if m.opts.Clock.Now().Sub(s.IssuedAt) > m.opts.MaxAge {
+		return nil, ErrSessionExpired
+	}
+	if m.opts.Binding != nil {
+		if err := m.opts.Binding.check(r, &s); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// Clear deletes the session cookie.
+func (m *Manager) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, m.cookie("", -1))
+}
+
+// Middleware loads the session, if any, into the request context for
+// FromContext. Requests without a valid session pass through unchanged;
+// ones rejected by Binding also have the cookie cleared.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := m.Load(r)
+		switch {
+		case err == nil:
+			r = r.WithContext(context.WithValue(r.Context(), sessionKey{}, s))
+		case errors.Is(err, ErrFingerprintMismatch):
+			m.Clear(w)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type sessionKey struct{}
+
+// FromContext returns the session stored by Manager.Middleware.
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(sessionKey{}).(*Session)
+	return s, ok
+}
+
+func (m *Manager) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.opts.CookieName,
+		Value:    value,
+		Path:     m.opts.Path,
+		Domain:   m.opts.Domain,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !m.opts.Insecure,
+		SameSite: m.opts.SameSite,
+	}
+}
+
+var b64 = base64.RawURLEncoding
+
+func (m *Manager) randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(m.opts.Rand, b); err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(b), nil
+}
+
+func (m *Manager) seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, m.aead.NonceSize(), m.aead.NonceSize()+len(plaintext)+m.aead.Overhead())
+	if _, err := io.ReadFull(m.opts.Rand, nonce); err != nil {
+		return "", err
+	}
+	// The cookie name is bound as associated data so a value cannot be
+	// replayed under another of the application's cookies.
+	return b64.EncodeToString(m.aead.Seal(nonce, nonce, plaintext, []byte(m.opts.CookieName))), nil
+}
+
+func (m *Manager) open(value string) ([]byte, error) {
+	raw, err := b64.DecodeString(value)
+	n := m.aead.NonceSize()
+	if err != nil || len(raw) < n {
+		return nil, ErrNoSession
+	}
+	return m.aead.Open(nil, raw[:n], raw[n:], []byte(m.opts.CookieName))
+}
@@ -0,0 +1,89 @@
+package authvitalsession
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fixedClock struct{ t time.Time }
+
+func (c *fixedClock) Now() time.Time { return c.t }
+
+func newTestManager(t *testing.T, opts Options) *Manager {
+	t.Helper()
+	m, err := NewManager([]byte(strings.Repeat("s", 32)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// roundTrip saves s and returns a request carrying the resulting cookies.
+func roundTrip(t *testing.T, m *Manager, s *Session) *http.Request {
+	t.Helper()
+	w := httptest.NewRecorder()
+	if err := m.Save(w, httptest.NewRequest("GET", "/", nil), s); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestSaveLoad(t *testing.T) {
+	clock := &fixedClock{time.Unix(1700000000, 0)}
+	m := newTestManager(t, Options{Clock: clock, MaxAge: time.Hour})
+	r := roundTrip(t, m, &Session{Subject: "usr_1", Values: map[string]string{"k": "v"}})
+	s, err := m.Load(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Subject != "usr_1" || s.Values["k"] != "v" || s.CSRFToken == "" || !s.IssuedAt.Equal(clock.t) {
+		t.Errorf("loaded %+v", s)
+	}
+
+	clock.t = clock.t.Add(2 * time.Hour)
+	if _, err := m.Load(r); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expired session: %v", err)
+	}
+}
+
+func TestLoadRejectsTampering(t *testing.T) {
+	m := newTestManager(t, Options{})
+	r := roundTrip(t, m, &Session{Subject: "usr_1"})
+	c, _ := r.Cookie(DefaultCookieName)
+
+	tampered := httptest.NewRequest("GET", "/", nil)
+	v := []byte(c.Value)
+	v[len(v)/2] ^= 1
+	tampered.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: string(v)})
+	if _, err := m.Load(tampered); !errors.Is(err, ErrNoSession) {
+		t.Errorf("tampered cookie: %v", err)
+	}
+
+	// A value sealed under another cookie name is not accepted.
+	other := newTestManager(t, Options{CookieName: "other"})
+	moved := httptest.NewRequest("GET", "/", nil)
+	moved.AddCookie(&http.Cookie{Name: "other", Value: c.Value})
+	if _, err := other.Load(moved); !errors.Is(err, ErrNoSession) {
+		t.Errorf("cookie under another name: %v", err)
+	}
+}
+
+func TestSaveTooLarge(t *testing.T) {
+	m := newTestManager(t, Options{})
+	w := httptest.NewRecorder()
+	s := &Session{Subject: "usr_1", Values: map[string]string{"big": strings.Repeat("x", MaxCookieSize)}}
+	if err := m.Save(w, httptest.NewRequest("GET", "/", nil), s); !errors.Is(err, ErrSessionTooLarge) {
+		t.Fatalf("Save = %v, want ErrSessionTooLarge", err)
+	}
+	if h := w.Header().Get("Set-Cookie"); h != "" {
+		t.Errorf("cookie written: %.40s...", h)
+	}
+}
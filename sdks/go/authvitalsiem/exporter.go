@@ -0,0 +1,98 @@
+package authvitalsiem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// DefaultInterval is how often Exporter checks for new entries.
+const DefaultInterval = time.Minute
+
+// Exporter periodically pushes new audit log entries to a sink. Its
+// position is checkpointed after every batch the sink accepts, so entries
+// are exported at least once across restarts.
+type Exporter struct {
+	// Name identifies the exporter's checkpoint.
+	Name        string
+	Source      *authvital.AuditLogsService
+	Filter      authvital.AuditLogListOptions
+	Format      Format
+	Sink        Sink
+	Checkpoints authvital.CheckpointStore
+	// Interval defaults to DefaultInterval.
+	Interval time.Duration
+	// OnError is called when an export round fails; the round is retried
+	// at the next interval. Defaults to ignoring errors.
+	OnError func(error)
+}
+
+// Run exports until ctx is done and returns ctx.Err().
+func (x *Exporter) Run(ctx context.Context) error {
+	interval := x.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := x.Export(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, authvital.ErrNotImplemented) {
+				return err
+			}
+			if x.OnError != nil {
+				x.OnError(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Export pushes all entries since the checkpoint, one page per batch.
+func (x *Exporter) Export(ctx context.Context) error {
+	cursor, err := x.Checkpoints.Load(ctx, x.Name)
+	if err != nil {
+		return fmt.Errorf("authvitalsiem: load checkpoint: %w", err)
+	}
+	for {
+		opts := x.Filter
+		opts.Cursor = cursor
+		page, err := x.Source.List(ctx, &opts)
+		if err != nil {
+			return err
+		}
+		records := make([][]byte, 0, len(page.Items))
+		for i := range page.Items {
+			r, err := x.Format(&page.Items[i])
+			if err != nil {
+				return fmt.Errorf("authvitalsiem: format entry %s: %w", page.Items[i].ID, err)
+			}
+			records = append(records, r)
+		}
+		if len(records) > 0 {
+			if err := x.Sink.Write(ctx, records); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return nil
+		}
+		if err := x.Checkpoints.Save(ctx, x.Name, page.NextCursor); err != nil {
+			return fmt.Errorf("authvitalsiem: save checkpoint: %w", err)
+		}
+		cursor = page.NextCursor
+		if len(page.Items) == 0 {
+			return nil
+		}
+	}
+}
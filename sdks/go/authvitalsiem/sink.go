@@ -0,0 +1,107 @@
+package authvitalsiem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// Sink receives formatted records in batches. Write must be all-or-nothing
+// from the exporter's view: on error the whole batch is sent again.
+type Sink interface {
+	Write(ctx context.Context, records [][]byte) error
+}
+
+// SplunkHEC sends records to a Splunk HTTP Event Collector. Records that
+// are JSON, such as OCSF, are sent as objects; others, such as CEF, as
+// strings.
+type SplunkHEC struct {
+	// URL is the collector's base URL, e.g. "https://splunk:8088".
+	URL   string
+	Token string
+	// Index and SourceType override the token's defaults.
+	Index      string
+	SourceType string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Write implements Sink.
+func (s *SplunkHEC) Write(ctx context.Context, records [][]byte) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, r := range records {
+		ev := map[string]any{"source": "authvital"}
+		if json.Valid(r) {
+			ev["event"] = json.RawMessage(r)
+		} else {
+			ev["event"] = string(r)
+		}
+		if s.Index != "" {
+			ev["index"] = s.Index
+		}
+		if s.SourceType != "" {
+			ev["sourcetype"] = s.SourceType
+		}
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(s.URL, "/")+"/services/collector/event", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+	hc := s.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("authvitalsiem: Splunk HEC returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// ObjectPutter stores an object. Wrap an S3 client's PutObject, or that of
+// any S3-compatible store, to use it with S3.
+type ObjectPutter interface {
+	PutObject(ctx context.Context, key string, body []byte) error
+}
+
+// S3 writes each batch as a newline-delimited object named
+// "<Prefix>YYYY/MM/DD/HHMMSS-<n>.log", so objects sort by time and
+// partition by day for Athena or a SIEM's S3 input.
+type S3 struct {
+	Bucket ObjectPutter
+	Prefix string
+	// Clock names objects; it defaults to the system clock.
+	Clock authvital.Clock
+}
+
+// Write implements Sink.
+func (s *S3) Write(ctx context.Context, records [][]byte) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var clock authvital.Clock = authvital.SystemClock{}
+	if s.Clock != nil {
+		clock = s.Clock
+	}
+	t := clock.Now().UTC()
+	key := fmt.Sprintf("%s%s-%09d.log", s.Prefix, t.Format("2006/01/02/150405"), t.Nanosecond())
+	body := append(bytes.Join(records, []byte("\n")), '\n')
+	return s.Bucket.PutObject(ctx, key, body)
+}
@@ -0,0 +1,18 @@
+package authvital
+
+// BillingLink associates a user or organization with an external billing
+// system record.
+type BillingLink struct {
+	// Provider is the billing system, e.g. "stripe".
+	Provider       string `json:"provider"`
+	CustomerID     string `json:"customerId,omitempty"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+}
+
+// ExternalIDKind selects which external ID GetByExternalID matches.
+type ExternalIDKind string
+
+const (
+	ExternalCustomerID     ExternalIDKind = "customer"
+	ExternalSubscriptionID ExternalIDKind = "subscription"
+)
@@ -0,0 +1,67 @@
+package authvital
+
+import "context"
+
+// ChallengeMode controls when a bot challenge is shown.
+type ChallengeMode string
+
+const (
+	// ChallengeAlways challenges every attempt.
+	ChallengeAlways ChallengeMode = "always"
+	// ChallengeRiskBased challenges only attempts assessed as risky.
+	ChallengeRiskBased ChallengeMode = "risk_based"
+	// ChallengeOff disables challenges.
+	ChallengeOff ChallengeMode = "off"
+)
+
+// Flow identifies an end-user authentication flow.
+type Flow string
+
+const (
+	// FlowSignIn is password or social sign-in.
+	FlowSignIn Flow = "sign_in"
+	// FlowSignUp is account registration.
+	FlowSignUp Flow = "sign_up"
+	// FlowPasswordReset is requesting a password reset email.
+	FlowPasswordReset Flow = "password_reset"
+	// FlowPasswordless is requesting a magic link or one-time code.
+	FlowPasswordless Flow = "passwordless"
+)
+
+// BotProtectionSettings is the tenant's bot protection configuration.
+type BotProtectionSettings struct {
+	// Provider is the challenge provider, e.g. "recaptcha" or "turnstile".
+	Provider string `json:"provider"`
+	// Policies maps each flow to its challenge mode. Flows absent from the
+	// map use ChallengeOff.
+	Policies map[Flow]ChallengeMode `json:"policies"`
+}
+
+// ChallengeSiteKey is the public key a frontend needs to render a challenge.
+type ChallengeSiteKey struct {
+	Provider string `json:"provider"`
+	SiteKey  string `json:"siteKey"`
+}
+
+// BotProtectionService configures CAPTCHA and bot protection.
+type BotProtectionService struct{}
+
+// Get retrieves the bot protection settings.
+func (s *BotProtectionService) Get(ctx context.Context) (*BotProtectionSettings, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces the bot protection settings.
+func (s *BotProtectionService) Update(ctx context.Context, settings *BotProtectionSettings) (*BotProtectionSettings, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetPolicy sets the challenge mode of a single flow.
+func (s *BotProtectionService) SetPolicy(ctx context.Context, flow Flow, mode ChallengeMode) error {
+	return ErrNotImplemented
+}
+
+// GetSiteKey retrieves the public challenge site key.
+func (s *BotProtectionService) GetSiteKey(ctx context.Context) (*ChallengeSiteKey, error) {
+	return nil, ErrNotImplemented
+}
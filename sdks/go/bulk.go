@@ -0,0 +1,44 @@
+package authvital
+
+import "context"
+
+// MaxBulkChanges is the most changes one BulkApply call accepts.
+const MaxBulkChanges = 1000
+
+// UserChangeOp is the kind of a UserChange.
+type UserChangeOp string
+
+const (
+	UserCreate     UserChangeOp = "create"
+	UserUpdate     UserChangeOp = "update"
+	UserDeactivate UserChangeOp = "deactivate"
+	UserReactivate UserChangeOp = "reactivate"
+)
+
+// UserChange is one operation of a bulk request.
+type UserChange struct {
+	Op UserChangeOp `json:"op"`
+	// UserID is required for all operations but UserCreate.
+	UserID string `json:"userId,omitempty"`
+	Email  string `json:"email,omitempty"`
+	// Profile fields are merged into the user's profile; a nil value
+	// removes the field.
+	Profile map[string]any `json:"profile,omitempty"`
+	// Reason is recorded for deactivations.
+	Reason string `json:"reason,omitempty"`
+}
+
+// BulkResult is the outcome of BulkApply.
+type BulkResult struct {
+	// UserIDs holds, for each change, the affected user's ID, or "" where
+	// the change failed.
+	UserIDs []string       `json:"userIds"`
+	Errors  []JobItemError `json:"errors"`
+}
+
+// BulkApply applies up to MaxBulkChanges changes in one request. Changes
+// are independent: one failing does not stop the others, and is reported
+// in Errors by its index.
+func (s *UsersService) BulkApply(ctx context.Context, changes []UserChange) (*BulkResult, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,298 @@
+package authvital
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores serialized responses. Implementations must be safe for
+// concurrent use; a Redis or memcached wrapper lets several processes share
+// one cache.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, key string)
+}
+
+// TTLPolicy returns how long the response to req may be cached. Zero or
+// less disables caching of req.
+type TTLPolicy func(req *http.Request) time.Duration
+
+// PathTTLPolicy caches GET requests whose path starts with one of the keys
+// of ttls, using the longest matching prefix.
+func PathTTLPolicy(ttls map[string]time.Duration) TTLPolicy {
+	return func(req *http.Request) time.Duration {
+		if req.Method != http.MethodGet {
+			return 0
+		}
+		best, ttl := -1, time.Duration(0)
+		for prefix, d := range ttls {
+			if strings.HasPrefix(req.URL.Path, prefix) && len(prefix) > best {
+				best, ttl = len(prefix), d
+			}
+		}
+		return ttl
+	}
+}
+
+// WithCache enables a read-through cache for stable resources such as
+// roles, permissions, and application metadata; see CachingTransport.
+func WithCache(cache Cache, policy TTLPolicy) Option {
+	return func(c *Client) {}
+}
+
+// CachingTransport is an http.RoundTripper serving repeated GETs from a
+// Cache. Entries are keyed by URL and credentials, so callers with
+// different tokens never share responses. Successful non-GET requests
+// invalidate cached entries in the same group; see Invalidate. A
+// transport without a Cache or Policy caches nothing.
+//
+// Expired entries that carry an ETag are revalidated with If-None-Match
+// rather than refetched; a 304 Not Modified renews them without a body,
+// which keeps polling loops cheap.
+type CachingTransport struct {
+	Base  http.RoundTripper
+	Cache Cache
+	// Policy decides which requests are cached and for how long.
+	Policy TTLPolicy
+	// RevalidateFor is how long past its TTL an entry with an ETag is kept
+	// for revalidation. Defaults to one hour.
+	RevalidateFor time.Duration
+	// Clock decides freshness; it defaults to the system clock.
+	Clock Clock
+}
+
+// cacheGenerationTTL is how long a group's generation is kept in the
+// Cache. It bounds the useful TTL of cached responses: when a generation
+// expires, its group starts over empty.
+const cacheGenerationTTL = 30 * 24 * time.Hour
+
+type cachedResponse struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	FreshUntil time.Time   `json:"freshUntil"`
+}
+
+// Invalidate drops cached responses in the same group as path, the group
+// being its first two segments, e.g. "/api/roles". Call it after changing
+// resources outside this transport. Each group has a generation kept in
+// the Cache itself and mixed into its keys, so invalidating orphans the
+// group's entries for every process sharing the Cache, without enumerating
+// it.
+func (t *CachingTransport) Invalidate(ctx context.Context, path string) {
+	if t.Cache != nil {
+		t.newGeneration(ctx, cacheGroup(path))
+	}
+}
+
+// generation returns the current generation of group, starting a new one
+// if the Cache has none. A lost generation thus empties the group rather
+// than bringing back entries from before an invalidation.
+func (t *CachingTransport) generation(ctx context.Context, group string) string {
+	if b, ok := t.Cache.Get(ctx, "authvital:http:gen:"+group); ok {
+		return string(b)
+	}
+	return t.newGeneration(ctx, group)
+}
+
+func (t *CachingTransport) newGeneration(ctx context.Context, group string) string {
+	// Random rather than counted, since the Cache cannot increment
+	// atomically; concurrent invalidations each orphan the old entries.
+	gen, err := randomString(nil, 9)
+	if err != nil {
+		gen = strconv.FormatInt(now(t.Clock).UnixNano(), 36)
+	}
+	t.Cache.Set(ctx, "authvital:http:gen:"+group, []byte(gen), cacheGenerationTTL)
+	return gen
+}
+
+// cacheGroup is the invalidation group of path: its first two segments,
+// e.g. "/api/roles" for "/api/roles/admin/permissions".
+func cacheGroup(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+func (t *CachingTransport) key(req *http.Request) string {
+	gen := t.generation(req.Context(), cacheGroup(req.URL.Path))
+	h := sha256.New()
+	io.WriteString(h, req.Header.Get("Authorization"))
+	io.WriteString(h, "\x00")
+	io.WriteString(h, req.URL.String())
+	return "authvital:http:" + gen + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (t *CachingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var ttl time.Duration
+	if t.Cache != nil && t.Policy != nil {
+		ttl = t.Policy(req)
+	}
+	if ttl <= 0 || req.Header.Get("If-None-Match") != "" {
+		resp, err := t.base().RoundTrip(req)
+		if err == nil && req.Method != http.MethodGet && req.Method != http.MethodHead && resp.StatusCode < 300 {
+			t.Invalidate(req.Context(), req.URL.Path)
+		}
+		return resp, err
+	}
+
+	key := t.key(req)
+	var stale *cachedResponse
+	if b, ok := t.Cache.Get(req.Context(), key); ok {
+		var cr cachedResponse
+		if json.Unmarshal(b, &cr) == nil {
+			if now(t.Clock).Before(cr.FreshUntil) {
+				return cr.response(req), nil
+			}
+			if cr.Header.Get("ETag") != "" {
+				stale = &cr
+			}
+		}
+	}
+
+	out := req
+	if stale != nil {
+		out = req.Clone(req.Context())
+		out.Header.Set("If-None-Match", stale.Header.Get("ETag"))
+	}
+	resp, err := t.base().RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	if stale != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		t.store(req, key, stale, ttl)
+		return stale.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.store(req, key, &cachedResponse{Status: resp.StatusCode, Header: resp.Header, Body: body}, ttl)
+	return resp, nil
+}
+
+func (t *CachingTransport) store(req *http.Request, key string, cr *cachedResponse, ttl time.Duration) {
+	cr.FreshUntil = now(t.Clock).Add(ttl)
+	keep := ttl
+	if cr.Header.Get("ETag") != "" {
+		if t.RevalidateFor > 0 {
+			keep += t.RevalidateFor
+		} else {
+			keep += time.Hour
+		}
+	}
+	if b, err := json.Marshal(cr); err == nil {
+		t.Cache.Set(req.Context(), key, b, keep)
+	}
+}
+
+func (cr *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(cr.Status) + " " + http.StatusText(cr.Status),
+		StatusCode:    cr.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cr.Header,
+		Body:          io.NopCloser(bytes.NewReader(cr.Body)),
+		ContentLength: int64(len(cr.Body)),
+		Request:       req,
+	}
+}
+
+// MemoryCache is an in-process Cache bounded to a maximum number of entries.
+type MemoryCache struct {
+	max int
+	// Clock expires entries; it defaults to the system clock.
+	Clock Clock
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache holding at most maxEntries entries.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{max: maxEntries, entries: map[string]memoryEntry{}}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if now(c.Clock).After(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	now := now(c.Clock)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.max {
+		c.evict(now)
+	}
+	c.entries[key] = memoryEntry{value: value, expires: now.Add(ttl)}
+}
+
+// evict drops expired entries, or the entry closest to expiry if none are.
+func (c *MemoryCache) evict(now time.Time) {
+	var victim string
+	var soonest time.Time
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+			continue
+		}
+		if soonest.IsZero() || e.expires.Before(soonest) {
+			victim, soonest = k, e.expires
+		}
+	}
+	if len(c.entries) >= c.max && victim != "" {
+		delete(c.entries, victim)
+	}
+}
+
+// Delete implements Cache.
+func (c *MemoryCache) Delete(ctx context.Context, key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
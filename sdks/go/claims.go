@@ -0,0 +1,150 @@
+package authvital
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrWrongOrganization is returned when a token is not scoped to the
+// organization a request targets.
+var ErrWrongOrganization = errors.New("authvital: token is not scoped to this organization")
+
+// Claims is the decoded payload of an AuthVital access or ID token.
+type Claims map[string]any
+
+// Subject returns the sub claim, the user ID.
+func (c Claims) Subject() string { return c.str("sub") }
+
+// Issuer returns the iss claim.
+func (c Claims) Issuer() string { return c.str("iss") }
+
+// Audience returns the aud claim, normalized to a slice.
+func (c Claims) Audience() []string { return c.strs("aud") }
+
+// Email returns the email claim.
+func (c Claims) Email() string { return c.str("email") }
+
+// TenantID returns the tenant_id claim of tenant-scoped tokens.
+func (c Claims) TenantID() string { return c.str("tenant_id") }
+
+// DeviceID returns the device_id claim identifying the device the session
+// was established on. It is empty when the device is unknown.
+func (c Claims) DeviceID() string { return c.str("device_id") }
+
+// OrgClaims is the organization context of a tenant-scoped token.
+type OrgClaims struct {
+	ID          string
+	Subdomain   string
+	Roles       []string
+	Permissions []string
+}
+
+// Organization returns the organization the token is scoped to. It returns
+// false for tokens that are not tenant-scoped.
+func (c Claims) Organization() (OrgClaims, bool) {
+	id := c.TenantID()
+	if id == "" {
+		return OrgClaims{}, false
+	}
+	return OrgClaims{
+		ID:          id,
+		Subdomain:   c.str("tenant_subdomain"),
+		Roles:       c.OrgRoles(),
+		Permissions: c.OrgPermissions(),
+	}, true
+}
+
+// OrgRoles returns the tenant_roles claim, the user's role slugs in the
+// token's organization.
+func (c Claims) OrgRoles() []string { return c.strs("tenant_roles") }
+
+// OrgPermissions returns the tenant_permissions claim.
+func (c Claims) OrgPermissions() []string { return c.strs("tenant_permissions") }
+
+// HasOrgRole reports whether the user has role in the token's organization.
+func (c Claims) HasOrgRole(role string) bool { return contains(c.OrgRoles(), role) }
+
+// RequireOrganization returns ErrWrongOrganization unless the token is
+// scoped to orgID.
+func (c Claims) RequireOrganization(orgID string) error {
+	if got := c.TenantID(); got != orgID {
+		return fmt.Errorf("%w: token is scoped to %q", ErrWrongOrganization, got)
+	}
+	return nil
+}
+
+// HasFeature reports whether the token's license grants feature. It reads
+// the license claim and needs no API call.
+func (c Claims) HasFeature(feature string) bool {
+	lic, _ := c["license"].(map[string]any)
+	return contains(Claims(lic).strs("features"), feature)
+}
+
+// ExpiresAt returns the exp claim.
+func (c Claims) ExpiresAt() time.Time {
+	t, _ := c.time("exp")
+	return t
+}
+
+// IssuedAt returns the iat claim.
+func (c Claims) IssuedAt() time.Time {
+	t, _ := c.time("iat")
+	return t
+}
+
+func (c Claims) str(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// strs reads a claim that may be a single string or an array of strings.
+func (c Claims) strs(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// time reads a NumericDate claim.
+func (c Claims) time(name string) (time.Time, bool) {
+	var secs float64
+	switch v := c[name].(type) {
+	case float64:
+		secs = v
+	case int64:
+		secs = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		secs = f
+	default:
+		return time.Time{}, false
+	}
+	whole, frac := math.Modf(secs)
+	return time.Unix(int64(whole), int64(frac*1e9)), true
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package authvital
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Claim validation errors, matched by errors.Is for a *ClaimError.
+var (
+	ErrMissingClaim = errors.New("authvital: required claim missing")
+	ErrUnknownClaim = errors.New("authvital: unknown claim")
+	ErrClaimType    = errors.New("authvital: claim has the wrong type")
+)
+
+// ClaimError reports a claim that does not match a ClaimsSchema.
+type ClaimError struct {
+	Claim string
+	// Err is ErrMissingClaim, ErrUnknownClaim or ErrClaimType.
+	Err error
+	// Want is the expected type, for ErrClaimType.
+	Want ClaimType
+}
+
+func (e *ClaimError) Error() string {
+	if e.Err == ErrClaimType {
+		return fmt.Sprintf("%v: %s is not %s", e.Err, e.Claim, e.Want)
+	}
+	return fmt.Sprintf("%v: %s", e.Err, e.Claim)
+}
+
+func (e *ClaimError) Unwrap() error { return e.Err }
+
+// ClaimType is the JSON type of a claim, named as in JSON Schema.
+type ClaimType string
+
+const (
+	ClaimString  ClaimType = "string"
+	ClaimNumber  ClaimType = "number"
+	ClaimInteger ClaimType = "integer"
+	ClaimBoolean ClaimType = "boolean"
+	ClaimArray   ClaimType = "array"
+	ClaimObject  ClaimType = "object"
+)
+
+// ClaimSpec describes one custom claim.
+type ClaimSpec struct {
+	Type     ClaimType `json:"type"`
+	Required bool      `json:"required,omitempty"`
+	// Items is the element type of ClaimArray claims.
+	Items       ClaimType `json:"items,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// registeredClaims are always allowed by a ClaimsSchema: the JWT
+// registered claims and those AuthVital sets itself.
+var registeredClaims = []string{
+	"iss", "sub", "aud", "exp", "nbf", "iat", "jti", "azp", "scope", "client_id",
+	"sid", "act", "nonce", "auth_time", "amr", "acr", "at_hash", "typ", "token_type",
+	"email", "email_verified", "name", "given_name", "family_name", "picture",
+	"tenant_id", "tenant_subdomain", "tenant_roles", "tenant_permissions",
+	"license", "device_id", "subject_type",
+}
+
+// ClaimsSchema is the contract for a token's custom claims, shared between
+// the services that issue and accept them. Pass it to WithClaimsSchema to
+// enforce it at verification, and generate typed accessors from it with
+// cmd/authvital-claimsgen.
+type ClaimsSchema struct {
+	Claims map[string]ClaimSpec `json:"claims"`
+	// AllowUnknown accepts claims outside the schema and the registered
+	// claims. By default they are rejected with ErrUnknownClaim.
+	AllowUnknown bool `json:"allowUnknown,omitempty"`
+}
+
+// Validate checks c against the schema and returns every mismatch, each a
+// *ClaimError, joined with errors.Join.
+func (s *ClaimsSchema) Validate(c Claims) error {
+	var errs []error
+	names := make([]string, 0, len(s.Claims))
+	for name := range s.Claims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec := s.Claims[name]
+		v, ok := c[name]
+		switch {
+		case !ok || v == nil:
+			if spec.Required {
+				errs = append(errs, &ClaimError{Claim: name, Err: ErrMissingClaim})
+			}
+		case !spec.matches(v):
+			errs = append(errs, &ClaimError{Claim: name, Err: ErrClaimType, Want: spec.describe()})
+		}
+	}
+	if !s.AllowUnknown {
+		var unknown []string
+		for name := range c {
+			if _, ok := s.Claims[name]; !ok && !contains(registeredClaims, name) {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			errs = append(errs, &ClaimError{Claim: name, Err: ErrUnknownClaim})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s ClaimSpec) describe() ClaimType {
+	if s.Type == ClaimArray && s.Items != "" {
+		return ClaimType("array of " + s.Items)
+	}
+	return s.Type
+}
+
+func (s ClaimSpec) matches(v any) bool {
+	if !typeMatches(s.Type, v) {
+		return false
+	}
+	if s.Type == ClaimArray && s.Items != "" {
+		for _, e := range v.([]any) {
+			if !typeMatches(s.Items, e) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func typeMatches(t ClaimType, v any) bool {
+	switch t {
+	case ClaimString:
+		_, ok := v.(string)
+		return ok
+	case ClaimNumber:
+		_, ok := v.(float64)
+		return ok
+	case ClaimInteger:
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case ClaimBoolean:
+		_, ok := v.(bool)
+		return ok
+	case ClaimArray:
+		_, ok := v.([]any)
+		return ok
+	case ClaimObject:
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+// ParseClaimsSchema reads a JSON Schema describing the claims object:
+// its properties, required and additionalProperties keywords are used.
+// Properties may have type string, number, integer, boolean, array (with
+// items.type) or object.
+func ParseClaimsSchema(jsonSchema []byte) (*ClaimsSchema, error) {
+	var doc struct {
+		Type       string `json:"type"`
+		Properties map[string]struct {
+			Type        ClaimType `json:"type"`
+			Description string    `json:"description"`
+			Items       *struct {
+				Type ClaimType `json:"type"`
+			} `json:"items"`
+		} `json:"properties"`
+		Required             []string `json:"required"`
+		AdditionalProperties *bool    `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(jsonSchema, &doc); err != nil {
+		return nil, fmt.Errorf("authvital: claims schema: %w", err)
+	}
+	if doc.Type != "" && doc.Type != "object" {
+		return nil, fmt.Errorf("authvital: claims schema must describe an object, not %s", doc.Type)
+	}
+	s := &ClaimsSchema{
+		Claims:       map[string]ClaimSpec{},
+		AllowUnknown: doc.AdditionalProperties == nil || *doc.AdditionalProperties,
+	}
+	for name, p := range doc.Properties {
+		spec := ClaimSpec{Type: p.Type, Description: p.Description}
+		if !validClaimType(p.Type) {
+			return nil, fmt.Errorf("authvital: claims schema: %s has unsupported type %q", name, p.Type)
+		}
+		if p.Items != nil {
+			spec.Items = p.Items.Type
+		}
+		s.Claims[name] = spec
+	}
+	for _, name := range doc.Required {
+		spec, ok := s.Claims[name]
+		if !ok {
+			return nil, fmt.Errorf("authvital: claims schema: required claim %s is not a property", name)
+		}
+		spec.Required = true
+		s.Claims[name] = spec
+	}
+	return s, nil
+}
+
+func validClaimType(t ClaimType) bool {
+	switch t {
+	case ClaimString, ClaimNumber, ClaimInteger, ClaimBoolean, ClaimArray, ClaimObject:
+		return true
+	}
+	return false
+}
+
+// ClaimsSchemaFor derives a schema from a struct's exported fields and
+// their json tags. Fields are required unless tagged omitempty or of
+// pointer type. Unknown claims are rejected.
+func ClaimsSchemaFor(v any) (*ClaimsSchema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("authvital: ClaimsSchemaFor needs a struct, not %v", t)
+	}
+	s := &ClaimsSchema{Claims: map[string]ClaimSpec{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		ft := f.Type
+		spec := ClaimSpec{Required: !strings.Contains(","+opts+",", ",omitempty,") && ft.Kind() != reflect.Pointer}
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		spec.Type = goClaimType(ft)
+		if spec.Type == ClaimArray && ft.Kind() != reflect.Map {
+			spec.Items = goClaimType(ft.Elem())
+		}
+		s.Claims[name] = spec
+	}
+	return s, nil
+}
+
+func goClaimType(t reflect.Type) ClaimType {
+	switch t.Kind() {
+	case reflect.String:
+		return ClaimString
+	case reflect.Bool:
+		return ClaimBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ClaimInteger
+	case reflect.Float32, reflect.Float64:
+		return ClaimNumber
+	case reflect.Slice, reflect.Array:
+		return ClaimArray
+	}
+	return ClaimObject
+}
+
+// WithClaimsSchema makes Verify and VerifyStandard reject tokens whose
+// claims do not match s, with *ClaimError values.
+func WithClaimsSchema(s *ClaimsSchema) VerifierOption {
+	return func(v *Verifier) { v.schema = s }
+}
@@ -0,0 +1,86 @@
+package authvital
+
+import (
+	"crypto/rand"
+	"io"
+	"time"
+)
+
+// Clock tells the time. Tests can supply a fake clock to control token
+// expiry and cache lifetimes.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the real clock.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// WithClock makes the client use clock for token expiry and caching.
+// Components built outside the client, such as ClientCredentials or a
+// StateStore, take the same clock from Client.Clock.
+func WithClock(clock Clock) Option {
+	return func(c *Client) { c.clock = clock }
+}
+
+// WithRandReader makes the client read randomness for PKCE verifiers,
+// state, nonces and JWT IDs from r instead of crypto/rand. Use it only in
+// tests.
+func WithRandReader(r io.Reader) Option {
+	return func(c *Client) { c.rand = r }
+}
+
+// Clock returns the client's clock, SystemClock unless WithClock was
+// given.
+func (c *Client) Clock() Clock {
+	if c.clock == nil {
+		return SystemClock{}
+	}
+	return c.clock
+}
+
+// Rand returns the client's source of randomness, crypto/rand unless
+// WithRandReader was given.
+func (c *Client) Rand() io.Reader {
+	return randReader(c.rand)
+}
+
+// now returns the time on c, or the system time when c is nil.
+func now(c Clock) time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
+
+// randReader returns r, or crypto/rand when r is nil.
+func randReader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+	return r
+}
+
+// WithVerifierClock makes the Verifier check token times against c.
+// Clock skew compensation, if enabled, is applied on top.
+func WithVerifierClock(c Clock) VerifierOption {
+	return func(v *Verifier) { v.clock = c }
+}
+
+// GenerateCodeVerifierFrom is GenerateCodeVerifier reading from r, for
+// deterministic tests.
+func GenerateCodeVerifierFrom(r io.Reader) (string, error) {
+	return randomString(r, 32)
+}
+
+// GenerateStateFrom is GenerateState reading from r.
+func GenerateStateFrom(r io.Reader) (string, error) {
+	return randomString(r, 32)
+}
+
+// GenerateNonceFrom is GenerateNonce reading from r.
+func GenerateNonceFrom(r io.Reader) (string, error) {
+	return randomString(r, 32)
+}
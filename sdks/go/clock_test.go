@@ -0,0 +1,49 @@
+package authvital
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+)
+
+type stepClock struct{ t time.Time }
+
+func (c *stepClock) Now() time.Time { return c.t }
+
+func TestMemoryCacheUsesClock(t *testing.T) {
+	clock := &stepClock{t: time.Unix(1700000000, 0)}
+	c := NewMemoryCache(10)
+	c.Clock = clock
+	c.Set(context.Background(), "k", []byte("v"), time.Minute)
+	if _, ok := c.Get(context.Background(), "k"); !ok {
+		t.Fatal("entry missing before expiry")
+	}
+	clock.t = clock.t.Add(2 * time.Minute)
+	if _, ok := c.Get(context.Background(), "k"); ok {
+		t.Fatal("entry returned after the clock passed its expiry")
+	}
+}
+
+func TestEncryptJWEUsesRand(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := bytes.Repeat([]byte{7}, 4096)
+	// RSA-OAEP is deterministic given the same random source, so two
+	// encryptions with the same reader must match exactly.
+	a, err := EncryptJWE([]byte("payload"), &key.PublicKey, &JWEOptions{Rand: bytes.NewReader(seed)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := EncryptJWE([]byte("payload"), &key.PublicKey, &JWEOptions{Rand: bytes.NewReader(seed)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Fatal("EncryptJWE did not draw its randomness from JWEOptions.Rand")
+	}
+}
@@ -0,0 +1,173 @@
+// Command authvital-claimsgen generates typed accessors for the custom
+// claims described by a JSON Schema, for use with go:generate:
+//
+//	//go:generate go run github.com/authvital/authvital/sdks/go/cmd/authvital-claimsgen -schema order-claims.json -type OrderClaims
+//
+// The output declares
+//
+//	type OrderClaims struct{ authvital.Claims }
+//	var OrderClaimsSchema *authvital.ClaimsSchema
+//
+// with one method per claim, named after it in Go style. Pass the schema
+// variable to authvital.WithClaimsSchema so tokens that break the contract
+// are rejected before the accessors are used.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "", "JSON Schema `file` describing the claims")
+	typeName := flag.String("type", "CustomClaims", "name of the generated type")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	out := flag.String("o", "", "output `file`; defaults to <type>_claims.go in lower case")
+	flag.Parse()
+	if *schemaPath == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	raw, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	schema, err := authvital.ParseClaimsSchema(raw)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(*pkg, *typeName, *schemaPath, schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		*out = strings.ToLower(*typeName) + "_claims.go"
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(pkg, typeName, source string, s *authvital.ClaimsSchema) ([]byte, error) {
+	names := make([]string, 0, len(s.Claims))
+	for name := range s.Claims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by authvital-claimsgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import authvital %q\n\n", "github.com/authvital/authvital/sdks/go")
+	fmt.Fprintf(&b, "// %s gives typed access to the claims in %s.\n", typeName, source)
+	fmt.Fprintf(&b, "type %s struct{ authvital.Claims }\n\n", typeName)
+	fmt.Fprintf(&b, "// %sSchema is the contract %s was generated from.\n", typeName, typeName)
+	fmt.Fprintf(&b, "var %sSchema = &authvital.ClaimsSchema{\n\tAllowUnknown: %t,\n\tClaims: map[string]authvital.ClaimSpec{\n", typeName, s.AllowUnknown)
+	for _, name := range names {
+		spec := s.Claims[name]
+		fmt.Fprintf(&b, "\t\t%q: {Type: %q, Required: %t", name, spec.Type, spec.Required)
+		if spec.Items != "" {
+			fmt.Fprintf(&b, ", Items: %q", spec.Items)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("\t},\n}\n")
+
+	seen := map[string]string{}
+	for _, name := range names {
+		spec := s.Claims[name]
+		method := goName(name)
+		if prev, ok := seen[method]; ok {
+			return nil, fmt.Errorf("claims %s and %s both map to method %s", prev, name, method)
+		}
+		seen[method] = name
+		b.WriteString("\n")
+		if spec.Description != "" {
+			fmt.Fprintf(&b, "// %s returns the %s claim: %s\n", method, name, spec.Description)
+		} else {
+			fmt.Fprintf(&b, "// %s returns the %s claim.\n", method, name)
+		}
+		writeAccessor(&b, typeName, method, name, spec)
+	}
+	return format.Source(b.Bytes())
+}
+
+func writeAccessor(b *bytes.Buffer, typeName, method, name string, spec authvital.ClaimSpec) {
+	recv := fmt.Sprintf("func (c %s) %s()", typeName, method)
+	switch spec.Type {
+	case authvital.ClaimString:
+		fmt.Fprintf(b, "%s string {\n\tv, _ := c.Claims[%q].(string)\n\treturn v\n}\n", recv, name)
+	case authvital.ClaimBoolean:
+		fmt.Fprintf(b, "%s bool {\n\tv, _ := c.Claims[%q].(bool)\n\treturn v\n}\n", recv, name)
+	case authvital.ClaimNumber:
+		fmt.Fprintf(b, "%s float64 {\n\tv, _ := c.Claims[%q].(float64)\n\treturn v\n}\n", recv, name)
+	case authvital.ClaimInteger:
+		fmt.Fprintf(b, "%s int64 {\n\tv, _ := c.Claims[%q].(float64)\n\treturn int64(v)\n}\n", recv, name)
+	case authvital.ClaimObject:
+		fmt.Fprintf(b, "%s map[string]any {\n\tv, _ := c.Claims[%q].(map[string]any)\n\treturn v\n}\n", recv, name)
+	case authvital.ClaimArray:
+		// elem is the accessor's element type and assert the JSON type it
+		// is decoded from.
+		elem, assert := "any", "any"
+		switch spec.Items {
+		case authvital.ClaimString:
+			elem, assert = "string", "string"
+		case authvital.ClaimBoolean:
+			elem, assert = "bool", "bool"
+		case authvital.ClaimNumber:
+			elem, assert = "float64", "float64"
+		case authvital.ClaimInteger:
+			elem, assert = "int64", "float64"
+		case authvital.ClaimObject:
+			elem, assert = "map[string]any", "map[string]any"
+		}
+		conv := "x"
+		if elem != assert {
+			conv = elem + "(x)"
+		}
+		fmt.Fprintf(b, "%s []%s {\n\tv, _ := c.Claims[%q].([]any)\n\tout := make([]%s, 0, len(v))\n"+
+			"\tfor _, e := range v {\n\t\tif x, ok := e.(%s); ok {\n\t\t\tout = append(out, %s)\n\t\t}\n\t}\n\treturn out\n}\n",
+			recv, elem, name, elem, assert, conv)
+	}
+}
+
+// goName converts a claim name such as "plan_tier" or "https://x/roles"
+// to an exported Go identifier.
+func goName(claim string) string {
+	if i := strings.LastIndexAny(claim, "/:"); i >= 0 {
+		claim = claim[i+1:]
+	}
+	var b strings.Builder
+	upper := true
+	for _, r := range claim {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	for _, r := range [][2]string{{"Id", "ID"}, {"Ids", "IDs"}, {"Url", "URL"}, {"Urls", "URLs"}, {"Api", "API"}} {
+		if strings.HasSuffix(name, r[0]) {
+			name = strings.TrimSuffix(name, r[0]) + r[1]
+			break
+		}
+	}
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Claim" + name
+	}
+	return name
+}
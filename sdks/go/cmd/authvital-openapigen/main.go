@@ -0,0 +1,601 @@
+// Command authvital-openapigen generates SDK bindings from AuthVital's
+// OpenAPI document. It is run through go generate in the SDK package:
+//
+//	AUTHVITAL_OPENAPI_SPEC=https://auth.example.com/api/openapi.json go generate
+//
+// Models become structs in the SDK's style, and operations become methods
+// on the service of their first tag, such as UsersService for "Users".
+// Types and methods already declared by hand are left alone, so the
+// output only fills in what the hand-written bindings lack. Services that
+// are new to the SDK still need a field on Client; they are listed on
+// standard error.
+//
+// Only JSON OpenAPI 3 documents are read.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type document struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas    map[string]*schema    `json:"schemas"`
+		Parameters map[string]*parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	AllOf                []*schema          `json:"allOf"`
+}
+
+type parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type mediaTypes map[string]struct {
+	Schema *schema `json:"schema"`
+}
+
+type operation struct {
+	OperationID string       `json:"operationId"`
+	Summary     string       `json:"summary"`
+	Tags        []string     `json:"tags"`
+	Deprecated  bool         `json:"deprecated"`
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *struct {
+		Content mediaTypes `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content mediaTypes `json:"content"`
+	} `json:"responses"`
+}
+
+var httpMethods = []string{"get", "post", "put", "patch", "delete"}
+
+func main() {
+	spec := flag.String("spec", os.Getenv("AUTHVITAL_OPENAPI_SPEC"), "OpenAPI document `file or URL`; defaults to $AUTHVITAL_OPENAPI_SPEC")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file")
+	out := flag.String("o", "openapi_gen.go", "output `file`, in the package directory")
+	tags := flag.String("tags", "", "comma-separated tags to generate; defaults to all")
+	optional := flag.Bool("optional", false, "do nothing when no spec is given, so go generate ./... works without one")
+	flag.Parse()
+	if *spec == "" && *optional {
+		fmt.Fprintln(os.Stderr, "authvital-openapigen: AUTHVITAL_OPENAPI_SPEC is not set; skipping")
+		return
+	}
+	if *spec == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	raw, err := load(*spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		log.Fatalf("%s: %v", *spec, err)
+	}
+	existing, err := declared(filepath.Dir(*out), filepath.Base(*out))
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := &generator{doc: &doc, existing: existing, imports: map[string]bool{}, generated: map[string]bool{}}
+	if *tags != "" {
+		g.tags = map[string]bool{}
+		for _, t := range strings.Split(*tags, ",") {
+			g.tags[strings.TrimSpace(t)] = true
+		}
+	}
+	src, err := g.generate(*pkg, *spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	for _, svc := range g.newServices {
+		fmt.Fprintf(os.Stderr, "authvital-openapigen: new service %s needs a field on Client\n", svc)
+	}
+}
+
+func load(spec string) ([]byte, error) {
+	if !strings.HasPrefix(spec, "https://") && !strings.HasPrefix(spec, "http://") {
+		return os.ReadFile(spec)
+	}
+	resp, err := http.Get(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", spec, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// declared returns the type names and "Type.Method" names declared in the
+// package in dir, ignoring the generated file itself.
+func declared(dir, generated string) (map[string]bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if filepath.Base(path) == generated || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range f.Decls {
+			switch d := d.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						names[ts.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					continue
+				}
+				recv := d.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if id, ok := recv.(*ast.Ident); ok {
+					names[id.Name+"."+d.Name.Name] = true
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+type generator struct {
+	doc         *document
+	existing    map[string]bool
+	tags        map[string]bool
+	imports     map[string]bool
+	generated   map[string]bool
+	newServices []string
+}
+
+type method struct {
+	service, name string
+	src           string
+}
+
+func (g *generator) generate(pkg, source string) ([]byte, error) {
+	var types bytes.Buffer
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := g.doc.Components.Schemas[name]
+		if g.existing[typeName(name)] || s.Type != "object" && len(s.Properties) == 0 && len(s.AllOf) == 0 {
+			continue
+		}
+		g.writeStruct(&types, typeName(name), s)
+	}
+
+	var methods []method
+	paths := make([]string, 0, len(g.doc.Paths))
+	for p := range g.doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for _, verb := range httpMethods {
+			raw, ok := g.doc.Paths[p][verb]
+			if !ok {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("%s %s: %v", strings.ToUpper(verb), p, err)
+			}
+			if len(op.Tags) == 0 || g.tags != nil && !g.tags[op.Tags[0]] {
+				continue
+			}
+			m, err := g.method(verb, p, &op)
+			if err != nil {
+				return nil, err
+			}
+			if m != nil {
+				methods = append(methods, *m)
+			}
+		}
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		if methods[i].service != methods[j].service {
+			return methods[i].service < methods[j].service
+		}
+		return methods[i].name < methods[j].name
+	})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by authvital-openapigen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if len(methods) > 0 {
+		g.imports["context"] = true
+	}
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, strconv.Quote(imp))
+		}
+		sort.Strings(imports)
+		fmt.Fprintf(&b, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	b.Write(types.Bytes())
+	service := ""
+	for _, m := range methods {
+		if m.service != service {
+			service = m.service
+			if !g.existing[service] {
+				g.newServices = append(g.newServices, service)
+				fmt.Fprintf(&b, "// %s was generated from the OpenAPI document.\ntype %s struct{}\n\n", service, service)
+			}
+		}
+		b.WriteString(m.src)
+	}
+	return format.Source(b.Bytes())
+}
+
+func (g *generator) writeStruct(b *bytes.Buffer, name string, s *schema) {
+	props, required := map[string]*schema{}, map[string]bool{}
+	for _, part := range append([]*schema{s}, s.AllOf...) {
+		part = g.resolve(part)
+		for k, v := range part.Properties {
+			props[k] = v
+		}
+		for _, r := range part.Required {
+			required[r] = true
+		}
+	}
+	writeDoc(b, name, s.Description)
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := props[k]
+		if p.Description != "" {
+			writeComment(b, "\t", p.Description)
+		}
+		tag := k
+		if !required[k] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(b, "\t%s %s `json:%q`\n", goName(k), g.goType(p), tag)
+	}
+	b.WriteString("}\n\n")
+}
+
+func (g *generator) resolve(s *schema) *schema {
+	if s.Ref == "" {
+		return s
+	}
+	if r := g.doc.Components.Schemas[refName(s.Ref)]; r != nil {
+		return r
+	}
+	return s
+}
+
+func (g *generator) goType(s *schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return typeName(refName(s.Ref))
+	}
+	if len(s.AllOf) == 1 {
+		return g.goType(s.AllOf[0])
+	}
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		case "byte", "binary":
+			return "[]byte"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(s.Items)
+	case "object":
+		var extra schema
+		if len(s.AdditionalProperties) > 0 && json.Unmarshal(s.AdditionalProperties, &extra) == nil && (extra.Type != "" || extra.Ref != "") {
+			return "map[string]" + g.goType(&extra)
+		}
+		if len(s.Properties) == 0 {
+			return "map[string]any"
+		}
+	}
+	g.imports["encoding/json"] = true
+	return "json.RawMessage"
+}
+
+// method renders one operation, or returns nil when the service already
+// has a method of that name.
+func (g *generator) method(verb, path string, op *operation) (*method, error) {
+	service := goName(op.Tags[0]) + "Service"
+	name := methodName(verb, path, op.OperationID)
+	if g.existing[service+"."+name] {
+		return nil, nil
+	}
+	if g.generated[service+"."+name] {
+		// Two operations in one service derived the same name.
+		name = methodName(verb, path, "")
+	}
+	g.generated[service+"."+name] = true
+	var args, pathParams []string
+	var query []*parameter
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			r := g.doc.Components.Parameters[refName(p.Ref)]
+			if r == nil {
+				return nil, fmt.Errorf("%s %s: unresolved parameter %s", strings.ToUpper(verb), path, p.Ref)
+			}
+			p = r
+		}
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p.Name)
+		case "query":
+			query = append(query, p)
+		}
+	}
+	// Path parameters are taken in the order they appear in the path.
+	sort.SliceStable(pathParams, func(i, j int) bool {
+		return strings.Index(path, "{"+pathParams[i]+"}") < strings.Index(path, "{"+pathParams[j]+"}")
+	})
+	for _, p := range pathParams {
+		args = append(args, argName(p)+" string")
+	}
+
+	var b bytes.Buffer
+	if op.RequestBody != nil {
+		if s := jsonSchema(op.RequestBody.Content); s != nil {
+			args = append(args, "body "+pointer(g.goType(s)))
+		}
+	}
+	if len(query) > 0 {
+		opts := strings.TrimSuffix(service, "Service") + name + "Options"
+		if !g.existing[opts] {
+			fmt.Fprintf(&b, "// %s are the query parameters of %s.%s.\ntype %s struct {\n", opts, service, name, opts)
+			for _, q := range query {
+				if q.Description != "" {
+					writeComment(&b, "\t", q.Description)
+				}
+				fmt.Fprintf(&b, "\t%s %s\n", goName(q.Name), g.goType(q.Schema))
+			}
+			b.WriteString("}\n\n")
+		}
+		args = append(args, "opts *"+opts)
+	}
+
+	result := ""
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			if s := jsonSchema(op.Responses[code].Content); s != nil {
+				result = pointer(g.goType(s))
+			}
+			break
+		}
+	}
+
+	fmt.Fprintf(&b, "// %s calls %s %s.\n", name, strings.ToUpper(verb), path)
+	if op.Summary != "" {
+		b.WriteString("//\n")
+		writeComment(&b, "", op.Summary)
+	}
+	if op.Deprecated {
+		b.WriteString("//\n// Deprecated: the endpoint is deprecated in the API.\n")
+	}
+	fmt.Fprintf(&b, "func (s *%s) %s(ctx context.Context", service, name)
+	for _, a := range args {
+		b.WriteString(", " + a)
+	}
+	if result != "" {
+		fmt.Fprintf(&b, ") (%s, error) {\n\treturn nil, ErrNotImplemented\n}\n\n", result)
+	} else {
+		b.WriteString(") error {\n\treturn ErrNotImplemented\n}\n\n")
+	}
+	return &method{service: service, name: name, src: b.String()}, nil
+}
+
+func jsonSchema(c mediaTypes) *schema {
+	if m, ok := c["application/json"]; ok {
+		return m.Schema
+	}
+	return nil
+}
+
+// pointer returns the argument or result type for t: structs are passed
+// by pointer, slices and maps as they are.
+func pointer(t string) string {
+	if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "any" {
+		return t
+	}
+	return "*" + t
+}
+
+// nestVerbs maps NestJS's default handler names to the SDK's method names.
+var nestVerbs = map[string]string{
+	"findAll": "List",
+	"findOne": "Get",
+	"create":  "Create",
+	"update":  "Update",
+	"remove":  "Delete",
+}
+
+// methodName derives a method name from an operation ID such as
+// "UsersController_findAll", or from the verb and path without one.
+func methodName(verb, path, id string) string {
+	if i := strings.LastIndexByte(id, '_'); i >= 0 {
+		id = id[i+1:]
+	}
+	if v, ok := nestVerbs[id]; ok {
+		return v
+	}
+	if id != "" {
+		return goName(id)
+	}
+	name := goName(verb)
+	for _, seg := range strings.Split(path, "/") {
+		if seg != "" && !strings.HasPrefix(seg, "{") {
+			name += goName(seg)
+		}
+	}
+	return name
+}
+
+// typeName names the model for a schema. NestJS request DTOs such as
+// CreateUserDto become CreateUserParams, as in the hand-written bindings.
+func typeName(schema string) string {
+	name := goName(schema)
+	if strings.HasSuffix(name, "Dto") {
+		name = strings.TrimSuffix(name, "Dto") + "Params"
+	}
+	return name
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndexByte(ref, '/')+1:]
+}
+
+// goName converts a schema, property or tag name to an exported Go
+// identifier with the SDK's initialisms.
+func goName(s string) string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])):
+			flush()
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	var b strings.Builder
+	for _, w := range words {
+		switch u := strings.ToUpper(w); u {
+		case "ID", "IDS", "URL", "URI", "API", "IP", "MFA", "SSO", "OIDC", "SAML", "JSON", "HTTP", "JWT", "TOTP":
+			if u == "IDS" {
+				u = "IDs"
+			}
+			b.WriteString(u)
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+func argName(s string) string {
+	n := goName(s)
+	if strings.HasSuffix(n, "ID") && n == strings.ToUpper(n) {
+		return "id"
+	}
+	return lowerFirst(n)
+}
+
+func lowerFirst(s string) string {
+	if strings.HasPrefix(s, "IDs") {
+		return "ids" + s[3:]
+	}
+	// Lower a leading initialism as a whole: "URLPath" becomes "urlPath".
+	n := 0
+	for n < len(s) && unicode.IsUpper(rune(s[n])) {
+		n++
+	}
+	if n > 1 && n < len(s) {
+		n--
+	}
+	if n == 0 {
+		return s
+	}
+	return strings.ToLower(s[:n]) + s[n:]
+}
+
+func writeDoc(b *bytes.Buffer, name, desc string) {
+	fmt.Fprintf(b, "// %s was generated from the OpenAPI document.\n", name)
+	if desc != "" {
+		b.WriteString("//\n")
+		writeComment(b, "", desc)
+	}
+}
+
+func writeComment(b *bytes.Buffer, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,105 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ConfigResource is a kind of configuration that can be watched.
+type ConfigResource string
+
+const (
+	ConfigRoles           ConfigResource = "roles"
+	ConfigPolicies        ConfigResource = "policies"
+	ConfigConnections     ConfigResource = "connections"
+	ConfigApplications    ConfigResource = "applications"
+	ConfigNetworkPolicies ConfigResource = "network_policies"
+)
+
+// ConfigChange describes one configuration change.
+type ConfigChange struct {
+	Resource ConfigResource `json:"resource"`
+	// ID is the changed object, e.g. a role slug.
+	ID string `json:"id"`
+	// Action is "created", "updated", or "deleted".
+	Action    string    `json:"action"`
+	Version   string    `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type configChangeBatch struct {
+	Changes []ConfigChange `json:"changes"`
+	// Cursor resumes the watch after the last change.
+	Cursor string `json:"cursor"`
+}
+
+// ConfigService watches configuration for changes.
+type ConfigService struct{}
+
+// poll long-polls for changes after cursor, returning an empty batch when
+// the server's wait times out.
+func (s *ConfigService) poll(ctx context.Context, resources []ConfigResource, cursor string) (*configChangeBatch, error) {
+	return nil, ErrNotImplemented
+}
+
+// Watch calls fn for every change to resources, or to all resources when
+// none are given, until ctx is done or fn returns an error. Changes are
+// delivered in order. Connection failures, server errors and rate limits
+// are retried with backoff and the watch resumes where it left off, so fn
+// sees no gaps.
+//
+// Watch returns ctx.Err(), fn's error, or the first error it does not
+// retry.
+func (s *ConfigService) Watch(ctx context.Context, fn func(ConfigChange) error, resources ...ConfigResource) error {
+	var cursor string
+	backoff := time.Second
+	for {
+		batch, err := s.poll(ctx, resources, cursor)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && !transient(err):
+			return err
+		case err != nil:
+			t := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+			if backoff *= 2; backoff > time.Minute {
+				backoff = time.Minute
+			}
+			continue
+		}
+		backoff = time.Second
+		for _, c := range batch.Changes {
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+		if batch.Cursor != "" {
+			cursor = batch.Cursor
+		}
+	}
+}
+
+// transient reports whether err may succeed on retry: a failed or dropped
+// connection, a server error or a rate limit.
+func transient(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		}
+		return apiErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,38 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestTransient(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), true},
+		{&APIError{StatusCode: 503}, true},
+		{&APIError{StatusCode: 429}, true},
+		{&APIError{StatusCode: 401}, false},
+		{&APIError{StatusCode: 404}, false},
+		{ErrNotImplemented, false},
+		{errors.New("authvital: invalid cursor"), false},
+	} {
+		if got := transient(tc.err); got != tc.want {
+			t.Errorf("transient(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWatchReturnsPermanentErrors(t *testing.T) {
+	var s ConfigService
+	err := s.Watch(context.Background(), func(ConfigChange) error { return nil })
+	if !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("Watch = %v, want the poll error returned", err)
+	}
+}
@@ -0,0 +1,147 @@
+package authvital
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JITTarget is what a JIT provisioning rule writes to.
+type JITTarget string
+
+const (
+	// JITTargetField copies the IdP attribute into a user profile field.
+	JITTargetField JITTarget = "field"
+	// JITTargetRole assigns a role when the attribute matches.
+	JITTargetRole JITTarget = "role"
+	// JITTargetGroup adds the user to a group when the attribute matches.
+	JITTargetGroup JITTarget = "group"
+)
+
+// JITRule maps one IdP attribute onto the provisioned user.
+type JITRule struct {
+	// Attribute is the IdP attribute (SAML attribute or OIDC claim) name.
+	Attribute string    `json:"attribute"`
+	Target    JITTarget `json:"target"`
+	// Field is the user field written by JITTargetField rules, e.g. "givenName".
+	Field string `json:"field,omitempty"`
+	// Equals restricts role and group rules to users whose attribute has
+	// this value, compared case-insensitively. Empty matches any value.
+	Equals string `json:"equals,omitempty"`
+	Role   string `json:"role,omitempty"`
+	Group  string `json:"group,omitempty"`
+	// Expression, when set, computes the rule's values with a mapping
+	// expression instead of reading Attribute; see CompileMapping.
+	Expression string `json:"expression,omitempty"`
+}
+
+// values returns the rule's input values from attrs. Invalid expressions
+// yield none; Validate reports them.
+func (r *JITRule) values(attrs map[string][]string) []string {
+	if r.Expression == "" {
+		return attrs[r.Attribute]
+	}
+	m, err := CompileMapping(r.Expression)
+	if err != nil {
+		return nil
+	}
+	return m.Eval(attrs)
+}
+
+// JITProvisioning configures just-in-time user creation for SSO sign-ins.
+type JITProvisioning struct {
+	Enabled bool `json:"enabled"`
+	// DefaultRoles are assigned to every provisioned user.
+	DefaultRoles []string `json:"defaultRoles,omitempty"`
+	// UpdateOnLogin reapplies the rules on every sign-in, not just the first.
+	UpdateOnLogin bool      `json:"updateOnLogin"`
+	Rules         []JITRule `json:"rules"`
+}
+
+// JITResult is the outcome of applying JIT rules to a set of attributes.
+type JITResult struct {
+	Fields map[string]string
+	Roles  []string
+	Groups []string
+}
+
+// Validate compiles the rules' mapping expressions, so that mistakes are
+// caught before the configuration is saved.
+func (p *JITProvisioning) Validate() error {
+	for i, r := range p.Rules {
+		if r.Expression == "" {
+			continue
+		}
+		if _, err := CompileMapping(r.Expression); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// DryRun applies the rules to attrs, the attributes an IdP would assert,
+// without contacting AuthVital. The server applies the same rules, so this
+// can be used to test a configuration before saving it. For multi-valued
+// field attributes the first value is used.
+func (p *JITProvisioning) DryRun(attrs map[string][]string) *JITResult {
+	res := &JITResult{Fields: map[string]string{}}
+	roles := map[string]bool{}
+	groups := map[string]bool{}
+	for _, r := range p.DefaultRoles {
+		roles[r] = true
+	}
+	for _, rule := range p.Rules {
+		values := rule.values(attrs)
+		if len(values) == 0 {
+			continue
+		}
+		switch rule.Target {
+		case JITTargetField:
+			res.Fields[rule.Field] = values[0]
+		case JITTargetRole, JITTargetGroup:
+			if rule.Equals != "" && !containsFold(values, rule.Equals) {
+				continue
+			}
+			if rule.Target == JITTargetRole {
+				roles[rule.Role] = true
+			} else {
+				groups[rule.Group] = true
+			}
+		}
+	}
+	res.Roles = sortedKeys(roles)
+	res.Groups = sortedKeys(groups)
+	return res
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ConnectionsService manages enterprise SSO connections.
+type ConnectionsService struct{}
+
+// GetJITProvisioning retrieves the JIT provisioning configuration of a connection.
+func (s *ConnectionsService) GetJITProvisioning(ctx context.Context, connectionID string) (*JITProvisioning, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetJITProvisioning replaces the JIT provisioning configuration of a connection.
+func (s *ConnectionsService) SetJITProvisioning(ctx context.Context, connectionID string, cfg *JITProvisioning) (*JITProvisioning, error) {
+	return nil, ErrNotImplemented
+}
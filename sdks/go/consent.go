@@ -0,0 +1,104 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ConsentDocument identifies a versioned legal document.
+type ConsentDocument string
+
+const (
+	ConsentTermsOfService ConsentDocument = "terms_of_service"
+	ConsentPrivacyPolicy  ConsentDocument = "privacy_policy"
+)
+
+// ConsentRecord records a user accepting a document version.
+type ConsentRecord struct {
+	ID         string          `json:"id"`
+	UserID     string          `json:"userId"`
+	Document   ConsentDocument `json:"document"`
+	Version    string          `json:"version"`
+	AcceptedAt time.Time       `json:"acceptedAt"`
+	IP         string          `json:"ip,omitempty"`
+	UserAgent  string          `json:"userAgent,omitempty"`
+}
+
+// ConsentRecordListOptions filters ConsentRecords.List.
+type ConsentRecordListOptions struct {
+	ListOptions
+	UserID   string
+	Document ConsentDocument
+	Version  string
+}
+
+// ConsentRecordsService records and queries document acceptance.
+type ConsentRecordsService struct{}
+
+// Record stores a user's acceptance. AcceptedAt defaults to now.
+func (s *ConsentRecordsService) Record(ctx context.Context, rec *ConsentRecord) (*ConsentRecord, error) {
+	return nil, ErrNotImplemented
+}
+
+// List lists consent records, newest first.
+func (s *ConsentRecordsService) List(ctx context.Context, opts *ConsentRecordListOptions) (*List[ConsentRecord], error) {
+	return nil, ErrNotImplemented
+}
+
+// CurrentVersion returns the version of document users must accept.
+func (s *ConsentRecordsService) CurrentVersion(ctx context.Context, document ConsentDocument) (string, error) {
+	return "", ErrNotImplemented
+}
+
+// HasAccepted reports whether a user has accepted version of document.
+func (s *ConsentRecordsService) HasAccepted(ctx context.Context, userID string, document ConsentDocument, version string) (bool, error) {
+	list, err := s.List(ctx, &ConsentRecordListOptions{
+		ListOptions: ListOptions{Limit: 1},
+		UserID:      userID,
+		Document:    document,
+		Version:     version,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(list.Items) > 0, nil
+}
+
+// RequireConsent returns middleware that rejects users who have not
+// accepted the current version of document with 403 Forbidden and a JSON
+// body naming the document and version, so the frontend can prompt for
+// acceptance. It must run after Verifier.Middleware.
+func (s *ConsentRecordsService) RequireConsent(document ConsentDocument) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				http.Error(w, errNoClaims.Error(), http.StatusInternalServerError)
+				return
+			}
+			version, err := s.CurrentVersion(r.Context(), document)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			accepted, err := s.HasAccepted(r.Context(), claims.Subject(), document, version)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if !accepted {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{
+					"error":    "consent_required",
+					"document": string(document),
+					"version":  version,
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventSource supplies events in order, resuming after a cursor.
+// EventsService is the AuthVital implementation; tests can provide their
+// own.
+type EventSource interface {
+	// Poll returns up to limit events after cursor, oldest first. It may
+	// wait for new events and return an empty page when none arrive.
+	// NextCursor is the position after the last event returned.
+	Poll(ctx context.Context, cursor string, limit int) (*List[Event], error)
+}
+
+// EventsService reads the event log webhooks are delivered from, for
+// consumers that pull instead of receiving pushes.
+type EventsService struct{}
+
+// Poll implements EventSource. An empty cursor starts at the oldest
+// retained event.
+func (s *EventsService) Poll(ctx context.Context, cursor string, limit int) (*List[Event], error) {
+	return nil, ErrNotImplemented
+}
+
+// CheckpointStore persists a consumer's position. Implementations must be
+// durable for the consumer to resume after a restart.
+type CheckpointStore interface {
+	// Load returns the saved cursor, or "" when there is none.
+	Load(ctx context.Context, name string) (string, error)
+	Save(ctx context.Context, name, cursor string) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore in process memory, for tests
+// and consumers that can replay from the start.
+type MemoryCheckpointStore struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+// Load implements CheckpointStore.
+func (m *MemoryCheckpointStore) Load(ctx context.Context, name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cursors[name], nil
+}
+
+// Save implements CheckpointStore.
+func (m *MemoryCheckpointStore) Save(ctx context.Context, name, cursor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cursors == nil {
+		m.cursors = map[string]string{}
+	}
+	m.cursors[name] = cursor
+	return nil
+}
+
+// EventHandler processes one event. Delivery is at least once, so
+// handlers must be idempotent; Event.ID identifies redeliveries.
+type EventHandler func(ctx context.Context, e *Event) error
+
+// Consumer defaults.
+const (
+	DefaultConsumerBatchSize   = 100
+	DefaultConsumerMaxAttempts = 5
+)
+
+// Consumer reads events from Source and dispatches them to handlers by
+// type, with at-least-once semantics: the checkpoint only advances past a
+// page once every event in it was handled or dead-lettered. A crash
+// between handling and checkpointing redelivers the page.
+type Consumer struct {
+	// Name identifies the consumer's checkpoint.
+	Name        string
+	Source      EventSource
+	Checkpoints CheckpointStore
+	// BatchSize defaults to DefaultConsumerBatchSize.
+	BatchSize int
+	// MaxAttempts is how often a failing handler is called for one event
+	// before it is dead-lettered. Defaults to DefaultConsumerMaxAttempts.
+	MaxAttempts int
+	// RetryDelay is the wait before the first retry, doubling for each
+	// later one. Defaults to one second.
+	RetryDelay time.Duration
+	// DeadLetter receives events whose handler kept failing, with the last
+	// error. If it is nil or returns an error, Run stops without advancing
+	// the checkpoint, so the event is retried on the next run.
+	DeadLetter func(ctx context.Context, e *Event, err error) error
+
+	handlers map[EventType]EventHandler
+	fallback EventHandler
+}
+
+// Handle registers h for events of type t, replacing any previous
+// handler. Events with no handler are skipped.
+func (c *Consumer) Handle(t EventType, h EventHandler) {
+	if c.handlers == nil {
+		c.handlers = map[EventType]EventHandler{}
+	}
+	c.handlers[t] = h
+}
+
+// HandleOther registers h for events with no handler of their own.
+func (c *Consumer) HandleOther(h EventHandler) {
+	c.fallback = h
+}
+
+// Run consumes events until ctx is done or a page cannot be completed.
+// Source errors, and empty pages returned without waiting, are retried
+// with backoff; a nil page counts as empty. A page of events that does
+// not advance the cursor stops Run. Run returns ctx.Err(), or the error
+// that stopped it.
+func (c *Consumer) Run(ctx context.Context) error {
+	cursor, err := c.Checkpoints.Load(ctx, c.Name)
+	if err != nil {
+		return fmt.Errorf("authvital: load checkpoint: %w", err)
+	}
+	limit := c.BatchSize
+	if limit <= 0 {
+		limit = DefaultConsumerBatchSize
+	}
+	backoff := time.Second
+	for {
+		start := time.Now()
+		page, err := c.Source.Poll(ctx, cursor, limit)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, ErrNotImplemented):
+			return err
+		case err != nil:
+			if err := sleepCtx(ctx, backoff); err != nil {
+				return err
+			}
+			if backoff *= 2; backoff > time.Minute {
+				backoff = time.Minute
+			}
+			continue
+		case page == nil:
+			page = &List[Event]{}
+		}
+		for i := range page.Items {
+			if err := c.dispatch(ctx, &page.Items[i]); err != nil {
+				return err
+			}
+		}
+		advanced := page.NextCursor != "" && page.NextCursor != cursor
+		if len(page.Items) > 0 && !advanced {
+			// Polling again would redeliver the page forever.
+			return fmt.Errorf("authvital: event source did not advance past cursor %q", cursor)
+		}
+		if advanced {
+			if err := c.Checkpoints.Save(ctx, c.Name, page.NextCursor); err != nil {
+				return fmt.Errorf("authvital: save checkpoint: %w", err)
+			}
+			cursor = page.NextCursor
+		}
+		if len(page.Items) > 0 || time.Since(start) >= time.Second {
+			// Long-polling sources already waited for events.
+			backoff = time.Second
+			continue
+		}
+		// Back off on empty pages from sources that do not wait.
+		if err := sleepCtx(ctx, backoff); err != nil {
+			return err
+		}
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// dispatch handles e, retrying and dead-lettering it on failure.
+func (c *Consumer) dispatch(ctx context.Context, e *Event) error {
+	h := c.handlers[e.Type]
+	if h == nil {
+		h = c.fallback
+	}
+	if h == nil {
+		return nil
+	}
+	attempts := c.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultConsumerMaxAttempts
+	}
+	delay := c.RetryDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			if err := sleepCtx(ctx, delay); err != nil {
+				return err
+			}
+			delay *= 2
+		}
+		if err = h(ctx, e); err == nil {
+			return nil
+		}
+	}
+	if c.DeadLetter == nil {
+		return fmt.Errorf("authvital: event %s failed %d times: %w", e.ID, attempts, err)
+	}
+	if dlErr := c.DeadLetter(ctx, e, err); dlErr != nil {
+		return fmt.Errorf("authvital: dead-letter event %s: %w", e.ID, dlErr)
+	}
+	return nil
+}
+
+// sleepCtx waits for d or until ctx is done, returning ctx.Err() then.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
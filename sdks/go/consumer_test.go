@@ -0,0 +1,143 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pages is an EventSource serving fixed pages, then cancelling the run.
+type pages struct {
+	pages  []*List[Event]
+	err    []error
+	polls  int
+	cancel context.CancelFunc
+}
+
+func (p *pages) Poll(ctx context.Context, cursor string, limit int) (*List[Event], error) {
+	i := p.polls
+	p.polls++
+	if i >= len(p.pages) {
+		p.cancel()
+		return nil, ctx.Err()
+	}
+	var err error
+	if i < len(p.err) {
+		err = p.err[i]
+	}
+	return p.pages[i], err
+}
+
+func page(cursor string, ids ...string) *List[Event] {
+	l := &List[Event]{NextCursor: cursor}
+	for _, id := range ids {
+		l.Items = append(l.Items, Event{ID: id, Type: "user.created"})
+	}
+	return l
+}
+
+func runConsumer(t *testing.T, c *Consumer, src *pages) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	src.cancel = cancel
+	c.Source = src
+	if c.Checkpoints == nil {
+		c.Checkpoints = &MemoryCheckpointStore{}
+	}
+	return c.Run(ctx)
+}
+
+func TestConsumerDeliversAndCheckpoints(t *testing.T) {
+	var got []string
+	store := &MemoryCheckpointStore{}
+	c := &Consumer{Name: "test", Checkpoints: store}
+	c.Handle("user.created", func(ctx context.Context, e *Event) error {
+		got = append(got, e.ID)
+		return nil
+	})
+	src := &pages{pages: []*List[Event]{page("c1", "e1", "e2"), nil, page("c2", "e3")}}
+	if err := runConsumer(t, c, src); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v", err)
+	}
+	if strings.Join(got, ",") != "e1,e2,e3" {
+		t.Errorf("handled %v", got)
+	}
+	if cur, _ := store.Load(context.Background(), "test"); cur != "c2" {
+		t.Errorf("checkpoint %q, want c2", cur)
+	}
+}
+
+func TestConsumerRejectsStuckCursor(t *testing.T) {
+	c := &Consumer{Name: "test"}
+	src := &pages{pages: []*List[Event]{page("c1", "e1"), page("c1", "e2")}}
+	if err := runConsumer(t, c, src); err == nil || !strings.Contains(err.Error(), "did not advance") {
+		t.Fatalf("Run = %v", err)
+	}
+	src = &pages{pages: []*List[Event]{page("", "e1")}}
+	if err := runConsumer(t, c, src); err == nil || !strings.Contains(err.Error(), "did not advance") {
+		t.Fatalf("Run with empty cursor = %v", err)
+	}
+}
+
+func TestConsumerDeadLetter(t *testing.T) {
+	var dead []string
+	c := &Consumer{
+		Name: "test", MaxAttempts: 2, RetryDelay: time.Millisecond,
+		DeadLetter: func(ctx context.Context, e *Event, err error) error {
+			dead = append(dead, e.ID+": "+err.Error())
+			return nil
+		},
+	}
+	calls := 0
+	c.HandleOther(func(ctx context.Context, e *Event) error {
+		calls++
+		return fmt.Errorf("attempt %d", calls)
+	})
+	src := &pages{pages: []*List[Event]{page("c1", "e1")}}
+	if err := runConsumer(t, c, src); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v", err)
+	}
+	if calls != 2 || len(dead) != 1 || dead[0] != "e1: attempt 2" {
+		t.Errorf("calls %d, dead-lettered %q", calls, dead)
+	}
+
+	// Without a dead-letter handler the run stops before the checkpoint.
+	store := &MemoryCheckpointStore{}
+	c = &Consumer{Name: "test", MaxAttempts: 1, Checkpoints: store}
+	c.HandleOther(func(ctx context.Context, e *Event) error { return errors.New("down") })
+	src = &pages{pages: []*List[Event]{page("c1", "e1")}}
+	if err := runConsumer(t, c, src); err == nil || errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v", err)
+	}
+	if cur, _ := store.Load(context.Background(), "test"); cur != "" {
+		t.Errorf("checkpoint advanced to %q", cur)
+	}
+}
+
+// slowSource returns empty pages after waiting, as long-polling sources do.
+type slowSource struct{ polls int }
+
+func (s *slowSource) Poll(ctx context.Context, cursor string, limit int) (*List[Event], error) {
+	s.polls++
+	if err := sleepCtx(ctx, time.Second); err != nil {
+		return nil, err
+	}
+	return &List[Event]{NextCursor: strconv.Itoa(s.polls)}, nil
+}
+
+func TestConsumerLongPollSkipsBackoff(t *testing.T) {
+	src := &slowSource{}
+	c := &Consumer{Name: "test", Source: src, Checkpoints: &MemoryCheckpointStore{}}
+	ctx, cancel := context.WithTimeout(context.Background(), 3500*time.Millisecond)
+	defer cancel()
+	c.Run(ctx)
+	// Backing off would allow only two polls.
+	if src.polls < 3 {
+		t.Errorf("%d polls in 3.5s", src.polls)
+	}
+}
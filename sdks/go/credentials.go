@@ -0,0 +1,131 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Credentials authenticate the client application.
+type Credentials struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// CredentialProvider supplies the current credentials, so secrets can be
+// rotated without restarting the process.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// WithCredentialProvider takes credentials from p instead of WithClientID
+// and WithClientSecret. When they change, cached tokens are discarded.
+func WithCredentialProvider(p CredentialProvider) Option {
+	return func(c *Client) { c.credentials = p }
+}
+
+// SetCredentials replaces the client's credentials at runtime and
+// discards tokens obtained with the old ones.
+func (c *Client) SetCredentials(creds Credentials) error {
+	return ErrNotImplemented
+}
+
+// errNoCredentials is returned when a Load function returns neither
+// credentials nor an error.
+var errNoCredentials = errors.New("authvital: credential provider returned no credentials")
+
+// ReloadingCredentials is a CredentialProvider calling Load at most once
+// per Interval. One caller runs Load while the others keep getting the
+// current credentials. If Load fails, the last good credentials are kept
+// and Load is retried with exponential backoff, up to Interval.
+type ReloadingCredentials struct {
+	Load func(ctx context.Context) (*Credentials, error)
+	// Interval defaults to one minute.
+	Interval time.Duration
+	// Clock schedules reloads; it defaults to the system clock.
+	Clock Clock
+
+	mu       sync.Mutex
+	current  *Credentials
+	err      error
+	next     time.Time
+	failures int
+	// loading is closed when the Load in flight finishes.
+	loading chan struct{}
+}
+
+// Credentials implements CredentialProvider.
+func (r *ReloadingCredentials) Credentials(ctx context.Context) (*Credentials, error) {
+	for {
+		r.mu.Lock()
+		if now(r.Clock).Before(r.next) || r.loading != nil && r.current != nil {
+			creds, err := r.current, r.err
+			r.mu.Unlock()
+			if creds != nil {
+				return creds, nil
+			}
+			return nil, err
+		}
+		if wait := r.loading; wait != nil {
+			// Nothing to serve yet; wait for the first load.
+			r.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		done := make(chan struct{})
+		r.loading = done
+		r.mu.Unlock()
+		return r.load(ctx, done)
+	}
+}
+
+func (r *ReloadingCredentials) load(ctx context.Context, done chan struct{}) (*Credentials, error) {
+	creds, err := r.Load(ctx)
+	if err == nil && creds == nil {
+		err = errNoCredentials
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defer close(done)
+	r.loading = nil
+	interval := r.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if err != nil {
+		r.failures++
+		r.err = err
+		r.next = now(r.Clock).Add(min(time.Second<<min(r.failures-1, 16), interval))
+		if r.current != nil {
+			return r.current, nil
+		}
+		return nil, err
+	}
+	r.current, r.err, r.failures = creds, nil, 0
+	r.next = now(r.Clock).Add(interval)
+	return creds, nil
+}
+
+// CredentialsFile returns a Load function for ReloadingCredentials reading
+// a JSON file with clientId and clientSecret, such as a mounted
+// Kubernetes secret.
+func CredentialsFile(path string) func(ctx context.Context) (*Credentials, error) {
+	return func(ctx context.Context) (*Credentials, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var c Credentials
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, err
+		}
+		return &c, nil
+	}
+}
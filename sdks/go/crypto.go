@@ -0,0 +1,58 @@
+package authvital
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// errSealed is returned when a sealed value fails authentication.
+var errSealed = errors.New("authvital: invalid or tampered value")
+
+// sealer encrypts small values such as cookies with AES-256-GCM under a key
+// derived from an application secret.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+func newSealer(secret []byte) (*sealer, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("authvital: secret must be at least 32 bytes")
+	}
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// seal encrypts plaintext, binding it to aad, and returns it base64url-encoded.
+// Nonces are read from r, or crypto/rand if r is nil.
+func (s *sealer) seal(r io.Reader, plaintext, aad []byte) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(plaintext)+s.aead.Overhead())
+	if _, err := io.ReadFull(randReader(r), nonce); err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, aad)), nil
+}
+
+// open reverses seal.
+func (s *sealer) open(value string, aad []byte) ([]byte, error) {
+	raw, err := b64.DecodeString(value)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return nil, errSealed
+	}
+	n := s.aead.NonceSize()
+	plain, err := s.aead.Open(nil, raw[:n], raw[n:], aad)
+	if err != nil {
+		return nil, errSealed
+	}
+	return plain, nil
+}
@@ -0,0 +1,163 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidDelegation is returned by Verify for a token whose act claim
+// is malformed or nested deeper than WithMaxDelegationDepth allows.
+var ErrInvalidDelegation = errors.New("authvital: invalid delegation chain")
+
+// Token types for token exchange (RFC 8693).
+const (
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeIDToken     = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// DefaultMaxDelegationDepth bounds act claim nesting.
+const DefaultMaxDelegationDepth = 5
+
+// Actor is a party acting on behalf of a token's subject, from an act claim.
+type Actor struct {
+	Subject  string `json:"sub"`
+	Issuer   string `json:"iss,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// DelegationChain returns the actors of a delegated token, current actor
+// first and the earliest last. It is empty when the subject acts for
+// itself. Chains are validated by Verify.
+func (c Claims) DelegationChain() []Actor {
+	var chain []Actor
+	act, _ := c["act"].(map[string]any)
+	for act != nil {
+		a := Claims(act)
+		chain = append(chain, Actor{Subject: a.str("sub"), Issuer: a.str("iss"), ClientID: a.str("client_id")})
+		act, _ = act["act"].(map[string]any)
+	}
+	return chain
+}
+
+// DelegationString describes the chain for audit logs, such as
+// "svc-a on behalf of svc-b on behalf of user-123".
+func (c Claims) DelegationString() string {
+	parts := []string{}
+	for _, a := range c.DelegationChain() {
+		parts = append(parts, a.Subject)
+	}
+	return strings.Join(append(parts, c.Subject()), " on behalf of ")
+}
+
+// ActClaim builds a nested act claim from chain, current actor first, for
+// tokens or assertions the application mints itself.
+func ActClaim(chain ...Actor) map[string]any {
+	var act map[string]any
+	for i := len(chain) - 1; i >= 0; i-- {
+		a := map[string]any{"sub": chain[i].Subject}
+		if chain[i].Issuer != "" {
+			a["iss"] = chain[i].Issuer
+		}
+		if chain[i].ClientID != "" {
+			a["client_id"] = chain[i].ClientID
+		}
+		if act != nil {
+			a["act"] = act
+		}
+		act = a
+	}
+	return act
+}
+
+// WithMaxDelegationDepth limits how many actors a token's act claim may
+// nest. Zero uses DefaultMaxDelegationDepth; use 1 to allow only direct
+// delegation.
+func WithMaxDelegationDepth(n int) VerifierOption {
+	return func(v *Verifier) { v.maxDelegation = n }
+}
+
+// validateDelegation checks that the act claim, if present, is a chain of
+// objects each naming a subject.
+func (v *Verifier) validateDelegation(raw any) error {
+	max := v.maxDelegation
+	if max <= 0 {
+		max = DefaultMaxDelegationDepth
+	}
+	ok := raw != nil
+	for depth := 0; ok; depth++ {
+		act, isObj := raw.(map[string]any)
+		if !isObj {
+			return fmt.Errorf("%w: act is not an object", ErrInvalidDelegation)
+		}
+		if s, _ := act["sub"].(string); s == "" {
+			return fmt.Errorf("%w: actor has no subject", ErrInvalidDelegation)
+		}
+		if depth >= max {
+			return fmt.Errorf("%w: deeper than %d", ErrInvalidDelegation, max)
+		}
+		raw, ok = act["act"]
+	}
+	return nil
+}
+
+// TokenExchangeParams are the parameters of a token exchange (RFC 8693).
+type TokenExchangeParams struct {
+	ClientID     string
+	ClientSecret string
+	// SubjectToken is the token of the party being acted for.
+	SubjectToken string
+	// SubjectTokenType defaults to TokenTypeAccessToken.
+	SubjectTokenType string
+	// ActorToken, for delegation, is the token of the party acting. The
+	// issued token's act claim names it, nested over any existing chain.
+	ActorToken     string
+	ActorTokenType string
+	Audience       string
+	Resource       []string
+	Scope          string
+	// Clock sets the token's Expiry; it defaults to the system clock.
+	Clock Clock
+}
+
+// ExchangeToken performs a token exchange at host's token endpoint. hc may
+// be nil to use http.DefaultClient.
+func ExchangeToken(ctx context.Context, hc *http.Client, host string, p *TokenExchangeParams) (*Token, error) {
+	if p.SubjectToken == "" {
+		return nil, errors.New("authvital: subject token is required")
+	}
+	form := url.Values{
+		"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"client_id":          {p.ClientID},
+		"subject_token":      {p.SubjectToken},
+		"subject_token_type": {p.SubjectTokenType},
+	}
+	if p.SubjectTokenType == "" {
+		form.Set("subject_token_type", TokenTypeAccessToken)
+	}
+	if p.ClientSecret != "" {
+		form.Set("client_secret", p.ClientSecret)
+	}
+	if p.ActorToken != "" {
+		form.Set("actor_token", p.ActorToken)
+		typ := p.ActorTokenType
+		if typ == "" {
+			typ = TokenTypeAccessToken
+		}
+		form.Set("actor_token_type", typ)
+	}
+	if p.Audience != "" {
+		form.Set("audience", p.Audience)
+	}
+	if err := addResources(form, p.Resource); err != nil {
+		return nil, err
+	}
+	if p.Scope != "" {
+		form.Set("scope", p.Scope)
+	}
+	return requestToken(ctx, hc, host, form, p.Clock)
+}
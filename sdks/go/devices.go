@@ -0,0 +1,43 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// Device is a browser or app a user has signed in from.
+type Device struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"userId"`
+	Name        string     `json:"name"`
+	UserAgent   string     `json:"userAgent"`
+	LastIP      string     `json:"lastIp"`
+	Trusted     bool       `json:"trusted"`
+	FirstSeenAt time.Time  `json:"firstSeenAt"`
+	LastSeenAt  time.Time  `json:"lastSeenAt"`
+	TrustUntil  *time.Time `json:"trustUntil,omitempty"`
+}
+
+// TrustOptions configures device trust.
+type TrustOptions struct {
+	// Duration limits how long the device skips MFA. Zero uses the tenant default.
+	Duration time.Duration
+}
+
+// DevicesService manages users' known devices.
+type DevicesService struct{}
+
+// List lists the known devices of a user.
+func (s *DevicesService) List(ctx context.Context, userID string, opts *ListOptions) (*List[Device], error) {
+	return nil, ErrNotImplemented
+}
+
+// Trust marks a device as trusted so sign-ins from it skip MFA.
+func (s *DevicesService) Trust(ctx context.Context, userID, deviceID string, opts *TrustOptions) (*Device, error) {
+	return nil, ErrNotImplemented
+}
+
+// RevokeTrust removes trust from a device; the next sign-in from it requires MFA.
+func (s *DevicesService) RevokeTrust(ctx context.Context, userID, deviceID string) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,94 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// SyncRunStatus is the state of a directory sync run.
+type SyncRunStatus string
+
+const (
+	SyncRunPending   SyncRunStatus = "pending"
+	SyncRunRunning   SyncRunStatus = "running"
+	SyncRunSucceeded SyncRunStatus = "succeeded"
+	// SyncRunPartial means the run finished but some records failed.
+	SyncRunPartial SyncRunStatus = "partial"
+	SyncRunFailed  SyncRunStatus = "failed"
+)
+
+// SyncRun is one pass of a directory (SCIM or LDAP) sync.
+type SyncRun struct {
+	ID           string        `json:"id"`
+	DirectoryID  string        `json:"directoryId"`
+	Status       SyncRunStatus `json:"status"`
+	Trigger      string        `json:"trigger"`
+	Created      int           `json:"created"`
+	Updated      int           `json:"updated"`
+	Deactivated  int           `json:"deactivated"`
+	Failed       int           `json:"failed"`
+	StartedAt    time.Time     `json:"startedAt"`
+	FinishedAt   *time.Time    `json:"finishedAt,omitempty"`
+	ErrorMessage string        `json:"errorMessage,omitempty"`
+}
+
+// SyncRecordError describes a directory record that could not be applied.
+type SyncRecordError struct {
+	// ExternalID is the record's ID in the customer's directory.
+	ExternalID string `json:"externalId"`
+	// Kind is "user" or "group".
+	Kind    string `json:"kind"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Payload is the record as received, for reproducing the failure.
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+// Directory is a directory sync connection.
+type Directory struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenantId"`
+	Type          string     `json:"type"`
+	Paused        bool       `json:"paused"`
+	LastSyncRunID string     `json:"lastSyncRunId,omitempty"`
+	LastSyncedAt  *time.Time `json:"lastSyncedAt,omitempty"`
+}
+
+// DirectorySyncService inspects and controls directory sync.
+type DirectorySyncService struct{}
+
+// ListDirectories lists directory connections of a tenant.
+func (s *DirectorySyncService) ListDirectories(ctx context.Context, tenantID string) ([]Directory, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListRuns lists sync runs of a directory, newest first.
+func (s *DirectorySyncService) ListRuns(ctx context.Context, directoryID string, opts *ListOptions) (*List[SyncRun], error) {
+	return nil, ErrNotImplemented
+}
+
+// GetRun retrieves a sync run.
+func (s *DirectorySyncService) GetRun(ctx context.Context, directoryID, runID string) (*SyncRun, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListRunErrors lists the records that failed in a sync run.
+func (s *DirectorySyncService) ListRunErrors(ctx context.Context, directoryID, runID string, opts *ListOptions) (*List[SyncRecordError], error) {
+	return nil, ErrNotImplemented
+}
+
+// Trigger starts a manual sync run.
+func (s *DirectorySyncService) Trigger(ctx context.Context, directoryID string) (*SyncRun, error) {
+	return nil, ErrNotImplemented
+}
+
+// Pause stops applying changes from a directory. Incoming SCIM requests are
+// rejected until Resume is called.
+func (s *DirectorySyncService) Pause(ctx context.Context, directoryID string) error {
+	return ErrNotImplemented
+}
+
+// Resume resumes a paused directory.
+func (s *DirectorySyncService) Resume(ctx context.Context, directoryID string) error {
+	return ErrNotImplemented
+}
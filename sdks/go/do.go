@@ -0,0 +1,16 @@
+package authvital
+
+import "context"
+
+// Do calls an API endpoint the SDK has no typed binding for yet. path is
+// relative to the API base, such as "/api/tenants/:id/widgets". body, if
+// not nil, is sent as JSON, and a successful JSON response is decoded into
+// out, if not nil.
+//
+// Do uses the client's authentication, retries and error mapping, so
+// failures are the same typed errors as for other calls, such as
+// *InsufficientScopeError or *APIError. Combine it with WithRawResponse
+// to read headers or fields that out does not declare.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any, opts ...CallOption) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,151 @@
+package authvital
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// DryRunHeader is set on responses synthesized in dry-run mode.
+const DryRunHeader = "X-AuthVital-Dry-Run"
+
+// DryRunCall describes a mutating call skipped in dry-run mode.
+type DryRunCall struct {
+	Method string
+	// Route is the path template, e.g. "/api/users/{id}".
+	Route string
+	Path  string
+	// Body is the request body that would have been sent.
+	Body []byte
+}
+
+func (c DryRunCall) String() string {
+	s := c.Method + " " + c.Path
+	if len(c.Body) > 0 {
+		s += " " + string(c.Body)
+	}
+	return s
+}
+
+// WithDryRun makes the client skip mutating calls: they are checked,
+// passed to log and answered with a synthesized result, while reads still
+// reach the server. Use it to preview a bulk admin script against a
+// production tenant. See DryRunTransport.
+func WithDryRun(log func(DryRunCall)) Option {
+	return func(c *Client) {}
+}
+
+// DryRunTransport is an http.RoundTripper that intercepts mutating
+// management API calls and performs all other requests. GET, HEAD and
+// OPTIONS requests, OAuth and discovery endpoints (/oauth/ and
+// /.well-known/), and POSTs that only read, such as searches, batch checks
+// and previews, reach the server. An intercepted request whose body is not
+// valid JSON fails as it would on the server; otherwise it is answered
+// without contacting the server:
+//
+//   - POST echoes the body with a placeholder "id", status 201
+//   - PUT and PATCH echo the body, status 200
+//   - DELETE returns 204
+//
+// Synthesized responses carry DryRunHeader. Results that depend on the
+// server, such as generated secrets, are absent.
+type DryRunTransport struct {
+	Base http.RoundTripper
+	// OnCall, when set, is called for every intercepted request.
+	OnCall func(DryRunCall)
+
+	n atomic.Int64
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !dryRunIntercepts(req) {
+		base := t.Base
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return base.RoundTrip(req)
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	call := DryRunCall{Method: req.Method, Route: routeTemplate(req.URL.Path), Path: req.URL.Path, Body: body}
+	if len(bytes.TrimSpace(body)) > 0 && !json.Valid(body) {
+		return t.respond(req, http.StatusBadRequest, []byte(`{"code":"invalid_request","message":"dry run: request body is not valid JSON"}`)), nil
+	}
+	if t.OnCall != nil {
+		t.OnCall(call)
+	}
+	switch req.Method {
+	case http.MethodPost:
+		id := "dryrun_" + strconv.FormatInt(t.n.Add(1), 10)
+		return t.respond(req, http.StatusCreated, withPlaceholderID(body, id)), nil
+	case http.MethodDelete:
+		return t.respond(req, http.StatusNoContent, nil), nil
+	}
+	return t.respond(req, http.StatusOK, body), nil
+}
+
+// dryRunReads are the last path segments of POST routes that do not
+// change anything.
+var dryRunReads = []string{"search", "check", "batch-check", "list-objects", "preview", "evaluate", "introspect"}
+
+// dryRunIntercepts reports whether req is a mutating management call.
+func dryRunIntercepts(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	path := req.URL.Path
+	if strings.Contains(path, "/oauth/") || strings.Contains(path, "/.well-known/") {
+		return false
+	}
+	last := path[strings.LastIndexByte(strings.TrimSuffix(path, "/"), '/')+1:]
+	return req.Method != http.MethodPost || !contains(dryRunReads, strings.TrimSuffix(last, "/"))
+}
+
+func (t *DryRunTransport) respond(req *http.Request, code int, body []byte) *http.Response {
+	h := http.Header{}
+	h.Set(DryRunHeader, "true")
+	if len(body) > 0 {
+		h.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// withPlaceholderID adds an "id" to a JSON object body that has none.
+func withPlaceholderID(body []byte, id string) []byte {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(body, &obj) != nil {
+		return body
+	}
+	if _, ok := obj["id"]; !ok {
+		obj["id"], _ = json.Marshal(id)
+	}
+	out, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package authvital
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDryRunTransport(t *testing.T) {
+	var reached []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = append(reached, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	var logged []string
+	tr := &DryRunTransport{OnCall: func(c DryRunCall) { logged = append(logged, c.Method+" "+c.Path) }}
+	tests := []struct {
+		method, path, body string
+		reaches            bool
+		status             int
+	}{
+		{"GET", "/api/users", "", true, 200},
+		{"POST", "/oauth/token", "grant_type=client_credentials", true, 200},
+		{"POST", "/auth/oauth/introspect", "token=x", true, 200},
+		{"GET", "/.well-known/openid-configuration", "", true, 200},
+		{"POST", "/api/users/search", `{"query":"email:*"}`, true, 200},
+		{"POST", "/api/authorize/batch-check", `{"checks":[]}`, true, 200},
+		{"POST", "/api/users", `{"email":"a@example.com"}`, false, 201},
+		{"PATCH", "/api/users/usr_123", `{"name":"A"}`, false, 200},
+		{"DELETE", "/api/users/usr_123", "", false, 204},
+		{"POST", "/api/users/usr_123/disable", `not json`, false, 400},
+	}
+	for _, tt := range tests {
+		reached = nil
+		req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		resp.Body.Close()
+		if got := len(reached) == 1; got != tt.reaches {
+			t.Errorf("%s %s reached server = %v, want %v", tt.method, tt.path, got, tt.reaches)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, resp.StatusCode, tt.status)
+		}
+		if !tt.reaches && tt.status < 400 && resp.Header.Get(DryRunHeader) == "" {
+			t.Errorf("%s %s: no %s header", tt.method, tt.path, DryRunHeader)
+		}
+	}
+	if len(logged) != 3 {
+		t.Errorf("logged %q, want the 3 valid intercepted calls", logged)
+	}
+}
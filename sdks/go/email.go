@@ -0,0 +1,117 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidEmail is returned for strings that are not an email address.
+var ErrInvalidEmail = errors.New("authvital: invalid email address")
+
+// dotInsensitiveDomains ignore dots in the local part and are aliases of
+// the first domain.
+var dotInsensitiveDomains = []string{"gmail.com", "googlemail.com"}
+
+// EmailCanonicalization configures how email addresses are reduced to a
+// canonical form. Accounts whose addresses share a canonical form collide:
+// signing up with one is rejected, and looking one up finds the other. The
+// domain is always compared case-insensitively.
+type EmailCanonicalization struct {
+	// CaseFold compares local parts case-insensitively. Almost all
+	// providers treat them that way, though RFC 5321 does not require it.
+	CaseFold bool `json:"caseFold"`
+	// IgnoreDots drops dots from Gmail local parts and treats
+	// googlemail.com as gmail.com, as Gmail does.
+	IgnoreDots bool `json:"ignoreDots"`
+	// StripPlusTags drops a "+tag" suffix from local parts, so
+	// "jane+news@example.com" collides with "jane@example.com".
+	StripPlusTags bool `json:"stripPlusTags"`
+}
+
+// Canonicalize returns the canonical form of email. A nil c only folds the
+// domain's case.
+func (c *EmailCanonicalization) Canonicalize(email string) (string, error) {
+	local, domain, err := splitEmail(email)
+	if err != nil {
+		return "", err
+	}
+	domain = strings.ToLower(domain)
+	if c == nil {
+		return local + "@" + domain, nil
+	}
+	if c.StripPlusTags {
+		if l, _, ok := strings.Cut(local, "+"); ok && l != "" {
+			local = l
+		}
+	}
+	if c.IgnoreDots && contains(dotInsensitiveDomains, domain) {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = dotInsensitiveDomains[0]
+	}
+	if c.CaseFold {
+		local = strings.ToLower(local)
+	}
+	return local + "@" + domain, nil
+}
+
+// splitEmail splits an address into its local part and domain, rejecting
+// display names and other forms users should not be typing.
+func splitEmail(email string) (local, domain string, err error) {
+	email = strings.TrimSpace(email)
+	i := strings.LastIndexByte(email, '@')
+	if i <= 0 || i == len(email)-1 || strings.ContainsAny(email, " \t\r\n<>,;") {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidEmail, email)
+	}
+	local, domain = email[:i], email[i+1:]
+	if strings.Contains(local, "@") && !strings.HasPrefix(local, `"`) || !strings.Contains(domain, ".") {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidEmail, email)
+	}
+	return local, domain, nil
+}
+
+// EmailQuery returns a search query matching users whose address
+// canonicalizes like email under c, which should be the tenant's settings.
+func EmailQuery(email string, c *EmailCanonicalization) (Query, error) {
+	canonical, err := c.Canonicalize(email)
+	if err != nil {
+		return "", err
+	}
+	return Field("canonicalEmail").Eq(canonical), nil
+}
+
+// EmailCollision reports whether an address would collide with an
+// existing account.
+type EmailCollision struct {
+	Email     string `json:"email"`
+	Canonical string `json:"canonical"`
+	Collides  bool   `json:"collides"`
+	// UserID is the existing account when Collides is true.
+	UserID string `json:"userId,omitempty"`
+}
+
+// EmailCanonicalizationService configures email canonicalization. The
+// server applies the settings on sign-up, user creation, imports and
+// email lookups.
+type EmailCanonicalizationService struct{}
+
+// Get retrieves the canonicalization settings.
+func (s *EmailCanonicalizationService) Get(ctx context.Context) (*EmailCanonicalization, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces the canonicalization settings. Existing accounts that
+// collide under the new settings are kept; Check finds them.
+func (s *EmailCanonicalizationService) Update(ctx context.Context, settings *EmailCanonicalization) (*EmailCanonicalization, error) {
+	return nil, ErrNotImplemented
+}
+
+// Check reports whether email would collide with an existing account,
+// for example before creating a user or changing an address.
+func (s *EmailCanonicalizationService) Check(ctx context.Context, email string) (*EmailCollision, error) {
+	if _, _, err := splitEmail(email); err != nil {
+		return nil, err
+	}
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,125 @@
+package authvital
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SubjectType is the kind of principal an entitlement is attached to.
+type SubjectType string
+
+const (
+	SubjectUser         SubjectType = "user"
+	SubjectOrganization SubjectType = "organization"
+)
+
+// Subject is a user or organization.
+type Subject struct {
+	Type SubjectType `json:"type"`
+	ID   string      `json:"id"`
+}
+
+// UserSubject returns the Subject of a user.
+func UserSubject(id string) Subject { return Subject{Type: SubjectUser, ID: id} }
+
+// OrgSubject returns the Subject of an organization.
+func OrgSubject(id string) Subject { return Subject{Type: SubjectOrganization, ID: id} }
+
+// Entitlement grants a subject a feature, optionally with a limit.
+type Entitlement struct {
+	Feature string  `json:"feature"`
+	Subject Subject `json:"subject"`
+	// Plan is the billing plan the entitlement comes from, if any.
+	Plan string `json:"plan,omitempty"`
+	// Limit is a numeric allowance such as API calls per month. Nil is unlimited.
+	Limit     *int64     `json:"limit,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// DefaultEntitlementCacheTTL is how long HasEntitlement caches a subject's
+// entitlements.
+const DefaultEntitlementCacheTTL = time.Minute
+
+// EntitlementsService attaches plan and feature entitlements to users and
+// organizations.
+type EntitlementsService struct {
+	// CacheTTL overrides DefaultEntitlementCacheTTL. Negative disables caching.
+	CacheTTL time.Duration
+	// Clock expires cached results; it defaults to the system clock.
+	Clock Clock
+
+	mu    sync.Mutex
+	cache map[Subject]entitlementCacheEntry
+}
+
+type entitlementCacheEntry struct {
+	features map[string]bool
+	expires  time.Time
+}
+
+// List lists the entitlements of a subject. Organization entitlements are
+// included for users.
+func (s *EntitlementsService) List(ctx context.Context, subject Subject) ([]Entitlement, error) {
+	return nil, ErrNotImplemented
+}
+
+// Grant attaches an entitlement.
+func (s *EntitlementsService) Grant(ctx context.Context, e *Entitlement) (*Entitlement, error) {
+	return nil, ErrNotImplemented
+}
+
+// Revoke removes a subject's entitlement to feature.
+func (s *EntitlementsService) Revoke(ctx context.Context, subject Subject, feature string) error {
+	return ErrNotImplemented
+}
+
+// SetPlan replaces a subject's plan-derived entitlements with those of plan.
+func (s *EntitlementsService) SetPlan(ctx context.Context, subject Subject, plan string) error {
+	return ErrNotImplemented
+}
+
+// HasEntitlement reports whether subject is entitled to feature. Results
+// are cached per subject for CacheTTL; call Invalidate after changing them.
+func (s *EntitlementsService) HasEntitlement(ctx context.Context, subject Subject, feature string) (bool, error) {
+	ttl := s.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultEntitlementCacheTTL
+	}
+	now := now(s.Clock)
+	if ttl > 0 {
+		s.mu.Lock()
+		e, ok := s.cache[subject]
+		s.mu.Unlock()
+		if ok && now.Before(e.expires) {
+			return e.features[feature], nil
+		}
+	}
+
+	list, err := s.List(ctx, subject)
+	if err != nil {
+		return false, err
+	}
+	features := make(map[string]bool, len(list))
+	for _, e := range list {
+		if e.ExpiresAt == nil || now.Before(*e.ExpiresAt) {
+			features[e.Feature] = true
+		}
+	}
+	if ttl > 0 {
+		s.mu.Lock()
+		if s.cache == nil {
+			s.cache = map[Subject]entitlementCacheEntry{}
+		}
+		s.cache[subject] = entitlementCacheEntry{features: features, expires: now.Add(ttl)}
+		s.mu.Unlock()
+	}
+	return features[feature], nil
+}
+
+// Invalidate drops cached entitlements of subject.
+func (s *EntitlementsService) Invalidate(subject Subject) {
+	s.mu.Lock()
+	delete(s.cache, subject)
+	s.mu.Unlock()
+}
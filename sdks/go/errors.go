@@ -0,0 +1,7 @@
+package authvital
+
+import "errors"
+
+// ErrPasswordBreached is returned when a password appears in a known breach
+// corpus and was rejected on password set or sign-in.
+var ErrPasswordBreached = errors.New("authvital: password found in a known data breach")
@@ -0,0 +1,291 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithHosts configures a primary AuthVital host and secondaries for
+// self-hosted HA deployments. Requests go to the primary while it is
+// healthy and fail over in order otherwise; see FailoverTransport.
+func WithHosts(primary string, secondaries ...string) Option {
+	return func(c *Client) {}
+}
+
+// DefaultFailoverCooldown is how long a failed host is skipped before it is
+// tried again.
+const DefaultFailoverCooldown = 30 * time.Second
+
+// FailoverEvent describes a switch between hosts.
+type FailoverEvent struct {
+	From string
+	To   string
+	// Err is the failure that triggered the switch. It is nil when
+	// returning to a recovered, higher-priority host.
+	Err error
+}
+
+// FailoverStats is a snapshot of FailoverTransport counters for metrics.
+type FailoverStats struct {
+	// Active is the host requests currently go to.
+	Active string
+	// Failovers counts switches away from a failing host.
+	Failovers uint64
+	// Recoveries counts switches back to a higher-priority host.
+	Recoveries uint64
+	// Unhealthy lists hosts currently being skipped.
+	Unhealthy []string
+}
+
+// FailoverTransport is an http.RoundTripper that sends each request to the
+// highest-priority healthy host, rewriting its scheme, host and path
+// prefix. Requests are built against the primary host. Requests that fail
+// with a network error or 502, 503, or 504 are retried on the next host
+// when they are idempotent (GET, HEAD, OPTIONS and PUT, or any request with
+// an Idempotency-Key header) and their body can be replayed; other
+// requests may already have run, so the first host's result is returned.
+//
+// Failover is sticky to the primary: as soon as a higher-priority host is
+// healthy again, traffic returns to it.
+type FailoverTransport struct {
+	hosts []*url.URL
+	base  http.RoundTripper
+	// Cooldown defaults to DefaultFailoverCooldown.
+	Cooldown time.Duration
+	// OnFailover, if set, is called on every switch between hosts.
+	OnFailover func(FailoverEvent)
+	// Clock times cooldowns; it defaults to the system clock.
+	Clock Clock
+
+	mu         sync.Mutex
+	downUntil  []time.Time
+	active     int
+	failovers  uint64
+	recoveries uint64
+}
+
+// NewFailoverTransport returns a FailoverTransport over hosts, primary
+// first. base defaults to http.DefaultTransport.
+func NewFailoverTransport(hosts []string, base http.RoundTripper) (*FailoverTransport, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("authvital: failover needs at least one host")
+	}
+	t := &FailoverTransport{base: base, downUntil: make([]time.Time, len(hosts))}
+	if t.base == nil {
+		t.base = http.DefaultTransport
+	}
+	for _, h := range hosts {
+		u, err := url.Parse(h)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("authvital: invalid host %q", h)
+		}
+		t.hosts = append(t.hosts, u)
+	}
+	return t, nil
+}
+
+func (t *FailoverTransport) cooldown() time.Duration {
+	if t.Cooldown > 0 {
+		return t.Cooldown
+	}
+	return DefaultFailoverCooldown
+}
+
+// candidates returns host indexes to try, healthy hosts in priority order
+// followed by unhealthy ones as a last resort.
+func (t *FailoverTransport) candidates() []int {
+	now := now(t.Clock)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	healthy := make([]int, 0, len(t.hosts))
+	var down []int
+	for i := range t.hosts {
+		if now.Before(t.downUntil[i]) {
+			down = append(down, i)
+		} else {
+			healthy = append(healthy, i)
+		}
+	}
+	return append(healthy, down...)
+}
+
+func (t *FailoverTransport) markDown(i int) {
+	t.mu.Lock()
+	t.downUntil[i] = now(t.Clock).Add(t.cooldown())
+	t.mu.Unlock()
+}
+
+// use records that host i served a request, reporting a switch if it
+// differs from the previously active host.
+func (t *FailoverTransport) use(i int, cause error) {
+	t.mu.Lock()
+	from := t.active
+	if from == i {
+		t.mu.Unlock()
+		return
+	}
+	t.active = i
+	if i < from {
+		t.recoveries++
+		cause = nil
+	} else {
+		t.failovers++
+	}
+	cb := t.OnFailover
+	t.mu.Unlock()
+	if cb != nil {
+		cb(FailoverEvent{From: t.hosts[from].String(), To: t.hosts[i].String(), Err: cause})
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	candidates := t.candidates()
+	if !idempotent(req) || req.Body != nil && req.GetBody == nil {
+		// Retrying could run the request twice, or the body was consumed
+		// by the first attempt and cannot be resent.
+		candidates = candidates[:1]
+	}
+	for n, i := range candidates {
+		r := req.Clone(req.Context())
+		t.rewrite(r, t.hosts[i])
+		if n > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			t.use(i, lastErr)
+			return resp, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("authvital: %s returned %s", t.hosts[i].Host, resp.Status)
+			if n == len(candidates)-1 {
+				// No host left to try; hand the response to the caller.
+				t.markDown(i)
+				return resp, nil
+			}
+			resp.Body.Close()
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		t.markDown(i)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// rewrite points r, built against the primary host, at h.
+func (t *FailoverTransport) rewrite(r *http.Request, h *url.URL) {
+	primary := t.hosts[0]
+	r.URL.Scheme, r.URL.Host, r.Host = h.Scheme, h.Host, ""
+	if h == primary {
+		return
+	}
+	r.URL.Path = joinPrefix(h.Path, primary.Path, r.URL.Path)
+	if r.URL.RawPath != "" {
+		r.URL.RawPath = joinPrefix(h.EscapedPath(), primary.EscapedPath(), r.URL.RawPath)
+	}
+}
+
+// joinPrefix replaces the path prefix from in path with to.
+func joinPrefix(to, from, path string) string {
+	if rest, ok := strings.CutPrefix(path, strings.TrimSuffix(from, "/")); ok && (rest == "" || rest[0] == '/') {
+		path = rest
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return strings.TrimSuffix(to, "/") + path
+}
+
+// idempotent reports whether req can safely be sent again after a failure
+// that may have happened after the server ran it.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// CheckHealth probes every host's /api/health endpoint and updates their
+// health, so a recovered primary is used again without waiting for its
+// cooldown to expire.
+func (t *FailoverTransport) CheckHealth(ctx context.Context) {
+	client := &http.Client{Transport: t.base, Timeout: 5 * time.Second}
+	for i, h := range t.hosts {
+		err := probeHealth(ctx, client, h.JoinPath("/api/health").String())
+		t.mu.Lock()
+		if err == nil {
+			t.downUntil[i] = time.Time{}
+		} else {
+			t.downUntil[i] = now(t.Clock).Add(t.cooldown())
+		}
+		t.mu.Unlock()
+	}
+}
+
+// RunHealthChecks calls CheckHealth every interval until ctx is done.
+func (t *FailoverTransport) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		t.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+func probeHealth(ctx context.Context, client *http.Client, u string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Status string `json:"status"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&body) != nil || body.Status != "ok" {
+		return fmt.Errorf("authvital: %s is unhealthy", u)
+	}
+	return nil
+}
+
+// Stats returns a snapshot of the transport's counters.
+func (t *FailoverTransport) Stats() FailoverStats {
+	now := now(t.Clock)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := FailoverStats{Active: t.hosts[t.active].String(), Failovers: t.failovers, Recoveries: t.recoveries}
+	for i, until := range t.downUntil {
+		if now.Before(until) {
+			s.Unhealthy = append(s.Unhealthy, t.hosts[i].String())
+		}
+	}
+	return s
+}
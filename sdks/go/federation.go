@@ -0,0 +1,234 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCIToken is returned when the CI environment provides no OIDC token.
+var ErrNoCIToken = errors.New("authvital: no CI OIDC token available")
+
+// CIProvider identifies a CI system issuing OIDC tokens to its jobs.
+type CIProvider string
+
+const (
+	// CIGitHubActions requires `permissions: id-token: write` on the job.
+	CIGitHubActions CIProvider = "github_actions"
+	// CIGitLab requires an id_tokens entry on the job named by
+	// GitLabTokenVariable, with the AuthVital host as its aud.
+	CIGitLab CIProvider = "gitlab"
+)
+
+// GitLabTokenVariable is the environment variable CIToken reads GitLab's ID
+// token from.
+const GitLabTokenVariable = "AUTHVITAL_ID_TOKEN"
+
+// Issuer returns the provider's default OIDC issuer.
+func (p CIProvider) Issuer() string {
+	switch p {
+	case CIGitHubActions:
+		return "https://token.actions.githubusercontent.com"
+	case CIGitLab:
+		return "https://gitlab.com"
+	}
+	return ""
+}
+
+// DetectCIProvider returns the CI system the process runs in, or "" when
+// it is not a supported one.
+func DetectCIProvider() CIProvider {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return CIGitHubActions
+	case os.Getenv("GITLAB_CI") == "true":
+		return CIGitLab
+	}
+	return ""
+}
+
+// FederationConditions restrict which CI jobs a trust accepts. Empty
+// fields match anything; Branches and Environments accept path.Match
+// patterns such as "release/*".
+type FederationConditions struct {
+	// Repository is "owner/repo" on GitHub and the project path on GitLab.
+	Repository   string   `json:"repository"`
+	Branches     []string `json:"branches,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+}
+
+// Match reports whether a CI token's claims satisfy the conditions. The
+// server evaluates them on every exchange; Match lets a pipeline fail
+// early with a clear message.
+func (f *FederationConditions) Match(p CIProvider, c Claims) bool {
+	repo, branch := c.str("repository"), strings.TrimPrefix(c.str("ref"), "refs/heads/")
+	if p == CIGitLab {
+		repo = c.str("project_path")
+		if c.str("ref_type") != "branch" {
+			branch = ""
+		}
+	} else if !strings.HasPrefix(c.str("ref"), "refs/heads/") {
+		branch = ""
+	}
+	if f.Repository != "" && !strings.EqualFold(f.Repository, repo) {
+		return false
+	}
+	return globMatch(f.Branches, branch) && globMatch(f.Environments, c.str("environment"))
+}
+
+func globMatch(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if s == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FederatedTrust lets CI jobs matching Conditions act as a service
+// account without a stored key.
+type FederatedTrust struct {
+	ID       string     `json:"id"`
+	Provider CIProvider `json:"provider"`
+	// Issuer defaults to Provider.Issuer; set it for self-hosted GitLab or
+	// GitHub Enterprise.
+	Issuer     string               `json:"issuer,omitempty"`
+	Conditions FederationConditions `json:"conditions"`
+	CreatedAt  time.Time            `json:"createdAt"`
+}
+
+// AddTrust lets CI jobs exchange their OIDC tokens for the service
+// account's tokens. Conditions.Repository is required.
+func (s *ServiceAccountsService) AddTrust(ctx context.Context, orgID, id string, trust *FederatedTrust) (*FederatedTrust, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListTrusts lists a service account's federated trusts.
+func (s *ServiceAccountsService) ListTrusts(ctx context.Context, orgID, id string) ([]FederatedTrust, error) {
+	return nil, ErrNotImplemented
+}
+
+// DeleteTrust removes a federated trust.
+func (s *ServiceAccountsService) DeleteTrust(ctx context.Context, orgID, id, trustID string) error {
+	return ErrNotImplemented
+}
+
+// CIToken returns the job's OIDC token for audience. On GitHub Actions it
+// is requested from the runner; on GitLab it is read from
+// GitLabTokenVariable, whose audience is fixed in the pipeline definition.
+func CIToken(ctx context.Context, hc *http.Client, p CIProvider, audience string) (string, error) {
+	switch p {
+	case CIGitHubActions:
+		return githubIDToken(ctx, hc, audience)
+	case CIGitLab:
+		if tok := os.Getenv(GitLabTokenVariable); tok != "" {
+			return tok, nil
+		}
+		return "", fmt.Errorf("%w: %s is not set", ErrNoCIToken, GitLabTokenVariable)
+	}
+	return "", fmt.Errorf("%w: unsupported CI provider %q", ErrNoCIToken, p)
+}
+
+func githubIDToken(ctx context.Context, hc *http.Client, audience string) (string, error) {
+	reqURL, reqToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"), os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if reqURL == "" || reqToken == "" {
+		return "", fmt.Errorf("%w: the job lacks the id-token: write permission", ErrNoCIToken)
+	}
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("audience", audience)
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+reqToken)
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: GitHub returned %s", ErrNoCIToken, resp.Status)
+	}
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.Value, nil
+}
+
+// FederatedTokenSource is a TokenSource for CI jobs: it exchanges the
+// job's OIDC token for a service account token, so pipelines need no
+// stored secret.
+type FederatedTokenSource struct {
+	Host             string
+	ServiceAccountID string
+	// Provider defaults to DetectCIProvider.
+	Provider CIProvider
+	// CIAudience is the audience requested for the CI token. It defaults
+	// to Host and must match the trust's expectations.
+	CIAudience string
+	Scope      string
+	HTTPClient *http.Client
+	// Clock sets and checks token expiry; it defaults to the system clock.
+	Clock Clock
+
+	mu  sync.Mutex
+	tok *Token
+}
+
+// Token implements TokenSource.
+func (s *FederatedTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tok != nil && s.tok.Expiry.Sub(now(s.Clock)) > tokenRefreshMargin {
+		return s.tok, nil
+	}
+	p := s.Provider
+	if p == "" {
+		p = DetectCIProvider()
+	}
+	aud := s.CIAudience
+	if aud == "" {
+		aud = s.Host
+	}
+	ciToken, err := CIToken(ctx, s.HTTPClient, p, aud)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := ExchangeToken(ctx, s.HTTPClient, s.Host, &TokenExchangeParams{
+		ClientID:         s.ServiceAccountID,
+		SubjectToken:     ciToken,
+		SubjectTokenType: TokenTypeJWT,
+		Scope:            s.Scope,
+		Clock:            s.Clock,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.tok = tok
+	return tok, nil
+}
@@ -0,0 +1,186 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrFlowExpired is returned when submitting to a flow that has expired;
+// start a new one.
+var ErrFlowExpired = errors.New("authvital: authentication flow expired")
+
+// FlowNodeType is a step of a headless authentication flow.
+type FlowNodeType string
+
+const (
+	// NodeIdentifier asks for an email address or username.
+	NodeIdentifier FlowNodeType = "identifier"
+	// NodePassword asks for the password of the identified account.
+	NodePassword FlowNodeType = "password"
+	// NodeMFA asks for a second factor.
+	NodeMFA FlowNodeType = "mfa"
+	// NodeRedirect sends the user to an external identity provider, for
+	// accounts in an organization using SSO.
+	NodeRedirect FlowNodeType = "redirect"
+	// NodeDone means the user is authenticated; call Finish.
+	NodeDone FlowNodeType = "done"
+)
+
+// FlowNodeError is returned when submitting input the flow's current node
+// does not accept, such as a password while it asks for an identifier. It
+// is detected before any request is made.
+type FlowNodeError struct {
+	Want, Got FlowNodeType
+}
+
+func (e *FlowNodeError) Error() string {
+	return fmt.Sprintf("authvital: flow is at the %s step, not %s", e.Got, e.Want)
+}
+
+// FlowMessage is feedback to show on the current node, such as a wrong
+// password.
+type FlowMessage struct {
+	// Field is the input the message refers to, or empty for the node.
+	Field string `json:"field,omitempty"`
+	// Code is machine-readable, e.g. "invalid_credentials"; use it to
+	// localize Text.
+	Code string `json:"code"`
+	Text string `json:"text"`
+}
+
+// FlowNode is the current step of a flow. The field matching Type is set.
+type FlowNode struct {
+	Type     FlowNodeType  `json:"type"`
+	Messages []FlowMessage `json:"messages,omitempty"`
+	// Identifier is set for NodePassword and later nodes, for display.
+	Identifier string `json:"identifier,omitempty"`
+	// Factors are offered by NodeMFA.
+	Factors []Factor `json:"factors,omitempty"`
+	// RedirectURL is where NodeRedirect sends the user.
+	RedirectURL string `json:"redirectUrl,omitempty"`
+	// BotChallenge is set when the node's submission must include a bot
+	// protection token from this provider.
+	BotChallenge *ChallengeSiteKey `json:"botChallenge,omitempty"`
+}
+
+// FlowSession is a headless authentication flow in progress. It carries
+// no secrets and can be kept in the custom UI's session between requests.
+type FlowSession struct {
+	ID        string    `json:"id"`
+	Flow      Flow      `json:"flow"`
+	Node      FlowNode  `json:"node"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (f *FlowSession) expect(t FlowNodeType) error {
+	if f.Node.Type != t {
+		return &FlowNodeError{Want: t, Got: f.Node.Type}
+	}
+	if !f.ExpiresAt.IsZero() && time.Now().After(f.ExpiresAt) {
+		return ErrFlowExpired
+	}
+	return nil
+}
+
+// FlowStartOptions configure Flows.Start. The authorization parameters
+// match those of the hosted login page's authorize request.
+type FlowStartOptions struct {
+	ClientID      string
+	RedirectURI   string
+	Scope         string
+	State         string
+	CodeChallenge string
+	// Context describes the end user's client for risk evaluation.
+	Context *RiskContext
+}
+
+// FlowSubmitOptions accompany a submission.
+type FlowSubmitOptions struct {
+	// BotToken answers FlowNode.BotChallenge.
+	BotToken string
+}
+
+// FlowResult completes a flow like a hosted login callback: exchange
+// Code for tokens with the PKCE verifier, as after a redirect.
+type FlowResult struct {
+	Code  string `json:"code"`
+	State string `json:"state,omitempty"`
+	// RedirectURL is RedirectURI with the code and state, for UIs that
+	// finish with a redirect.
+	RedirectURL string `json:"redirectUrl"`
+}
+
+// FlowsService is the headless authentication API, for building fully
+// custom login pages. A flow is a state machine of typed nodes: each
+// Submit method accepts only its node's input and returns the next node,
+// or the same node with Messages when the input was rejected.
+type FlowsService struct{}
+
+// Start begins a flow. Only FlowSignIn is supported headlessly so far.
+func (s *FlowsService) Start(ctx context.Context, flow Flow, opts *FlowStartOptions) (*FlowSession, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get returns a flow's current node, to resume it.
+func (s *FlowsService) Get(ctx context.Context, id string) (*FlowSession, error) {
+	return nil, ErrNotImplemented
+}
+
+// SubmitIdentifier answers NodeIdentifier. The next node is the same for
+// existing and unknown accounts, so the flow does not reveal which exist.
+func (s *FlowsService) SubmitIdentifier(ctx context.Context, f *FlowSession, identifier string, opts *FlowSubmitOptions) (*FlowSession, error) {
+	if err := f.expect(NodeIdentifier); err != nil {
+		return nil, err
+	}
+	return nil, ErrNotImplemented
+}
+
+// SubmitPassword answers NodePassword.
+func (s *FlowsService) SubmitPassword(ctx context.Context, f *FlowSession, password string, opts *FlowSubmitOptions) (*FlowSession, error) {
+	if err := f.expect(NodePassword); err != nil {
+		return nil, err
+	}
+	return nil, ErrNotImplemented
+}
+
+// SelectFactor chooses one of NodeMFA's factors, sending its code or
+// prompt if it has one.
+func (s *FlowsService) SelectFactor(ctx context.Context, f *FlowSession, factorID string) (*FlowSession, error) {
+	if err := f.expect(NodeMFA); err != nil {
+		return nil, err
+	}
+	if !f.offers(factorID) {
+		return nil, ErrUnknownFactor
+	}
+	return nil, ErrNotImplemented
+}
+
+// SubmitMFA answers NodeMFA with a code for factorID.
+func (s *FlowsService) SubmitMFA(ctx context.Context, f *FlowSession, factorID, code string) (*FlowSession, error) {
+	if err := f.expect(NodeMFA); err != nil {
+		return nil, err
+	}
+	if !f.offers(factorID) {
+		return nil, ErrUnknownFactor
+	}
+	return nil, ErrNotImplemented
+}
+
+// Finish ends a flow at NodeDone and returns its authorization code.
+func (s *FlowsService) Finish(ctx context.Context, f *FlowSession) (*FlowResult, error) {
+	if err := f.expect(NodeDone); err != nil {
+		return nil, err
+	}
+	return nil, ErrNotImplemented
+}
+
+func (f *FlowSession) offers(factorID string) bool {
+	for _, fa := range f.Node.Factors {
+		if fa.ID == factorID {
+			return true
+		}
+	}
+	return false
+}
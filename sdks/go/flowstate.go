@@ -0,0 +1,257 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrFlowStateNotFound is returned by a StateStore when no pending
+// authorization matches the callback's state, or it has expired.
+var ErrFlowStateNotFound = errors.New("authvital: authorization state not found or expired")
+
+// FlowState is what must survive between redirecting to AuthVital and
+// handling the callback.
+type FlowState struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce,omitempty"`
+	CodeVerifier string `json:"codeVerifier"`
+	RedirectURI  string `json:"redirectUri,omitempty"`
+	// ReturnTo is the application URL to send the user to after sign-in.
+	ReturnTo  string    `json:"returnTo,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// StateStore persists FlowState between the authorize redirect and the
+// callback. Implementations must make Consume single-use.
+//
+// Stores are given the request and response so cookie-based stores can keep
+// state client-side; server-side stores can ignore them. Either way, any
+// instance of a multi-instance deployment must be able to Consume state
+// saved by another.
+type StateStore interface {
+	Save(w http.ResponseWriter, r *http.Request, st *FlowState) error
+	Consume(w http.ResponseWriter, r *http.Request, state string) (*FlowState, error)
+}
+
+// DefaultFlowStateTTL bounds how long a user may take to sign in.
+const DefaultFlowStateTTL = 10 * time.Minute
+
+// CookieStateStore keeps FlowState in an encrypted, HttpOnly cookie per
+// pending authorization. It needs no shared storage and is the default.
+type CookieStateStore struct {
+	sealer *sealer
+	// TTL defaults to DefaultFlowStateTTL.
+	TTL time.Duration
+	// Path scopes the cookie. Defaults to "/".
+	Path string
+	// Insecure omits the Secure attribute, for local HTTP development only.
+	Insecure bool
+	// Clock checks the TTL; it defaults to the system clock.
+	Clock Clock
+	// Rand supplies encryption nonces; it defaults to crypto/rand.
+	Rand io.Reader
+}
+
+// NewCookieStateStore returns a CookieStateStore encrypting with a key
+// derived from secret, which must be at least 32 bytes and shared by all
+// instances.
+func NewCookieStateStore(secret []byte) (*CookieStateStore, error) {
+	s, err := newSealer(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieStateStore{sealer: s}, nil
+}
+
+func flowCookieName(state string) string {
+	// State is random base64url, so a prefix is safe in a cookie name and
+	// keeps concurrent sign-ins in different tabs apart.
+	if len(state) > 16 {
+		state = state[:16]
+	}
+	return "authvital_flow_" + state
+}
+
+func (s *CookieStateStore) ttl() time.Duration {
+	if s.TTL > 0 {
+		return s.TTL
+	}
+	return DefaultFlowStateTTL
+}
+
+func (s *CookieStateStore) path() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	return "/"
+}
+
+// Save implements StateStore.
+func (s *CookieStateStore) Save(w http.ResponseWriter, r *http.Request, st *FlowState) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	v, err := s.sealer.seal(s.Rand, b, []byte(st.State))
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     flowCookieName(st.State),
+		Value:    v,
+		Path:     s.path(),
+		MaxAge:   int(s.ttl() / time.Second),
+		HttpOnly: true,
+		Secure:   !s.Insecure,
+		// Lax lets the cookie accompany the top-level redirect back from AuthVital.
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// Consume implements StateStore.
+func (s *CookieStateStore) Consume(w http.ResponseWriter, r *http.Request, state string) (*FlowState, error) {
+	name := flowCookieName(state)
+	c, err := r.Cookie(name)
+	if err != nil {
+		return nil, ErrFlowStateNotFound
+	}
+	http.SetCookie(w, &http.Cookie{Name: name, Path: s.path(), MaxAge: -1, HttpOnly: true, Secure: !s.Insecure})
+
+	b, err := s.sealer.open(c.Value, []byte(state))
+	if err != nil {
+		return nil, ErrFlowStateNotFound
+	}
+	var st FlowState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, ErrFlowStateNotFound
+	}
+	if st.State != state || now(s.Clock).Sub(st.CreatedAt) > s.ttl() {
+		return nil, ErrFlowStateNotFound
+	}
+	return &st, nil
+}
+
+// KeyValueStore is the minimal storage a server-side StateStore needs. It
+// maps directly onto Redis (SET EX / GETDEL), DynamoDB (PutItem with a TTL
+// attribute / DeleteItem with ReturnValues=ALL_OLD), or a SQL table.
+type KeyValueStore interface {
+	// Set stores value under key, expiring after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// GetDel atomically returns and deletes the value under key. It returns
+	// a nil value and no error when the key does not exist.
+	GetDel(ctx context.Context, key string) ([]byte, error)
+}
+
+// KVStateStore keeps FlowState server-side in a KeyValueStore.
+type KVStateStore struct {
+	Store KeyValueStore
+	// Prefix namespaces keys. Defaults to "authvital:flow:".
+	Prefix string
+	// TTL defaults to DefaultFlowStateTTL.
+	TTL time.Duration
+}
+
+func (s *KVStateStore) key(state string) string {
+	if s.Prefix != "" {
+		return s.Prefix + state
+	}
+	return "authvital:flow:" + state
+}
+
+// Save implements StateStore.
+func (s *KVStateStore) Save(w http.ResponseWriter, r *http.Request, st *FlowState) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultFlowStateTTL
+	}
+	return s.Store.Set(r.Context(), s.key(st.State), b, ttl)
+}
+
+// Consume implements StateStore.
+func (s *KVStateStore) Consume(w http.ResponseWriter, r *http.Request, state string) (*FlowState, error) {
+	b, err := s.Store.GetDel(r.Context(), s.key(state))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, ErrFlowStateNotFound
+	}
+	var st FlowState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// StartFlow fills in fresh state, nonce, and PKCE values on p, saves them
+// to store, and returns the authorize URL to redirect the user to. returnTo
+// is carried through to ResumeFlow.
+func StartFlow(w http.ResponseWriter, r *http.Request, store StateStore, host string, p *AuthorizeParams, returnTo string) (string, error) {
+	st := &FlowState{RedirectURI: p.RedirectURI, ReturnTo: returnTo, CreatedAt: now(p.Clock)}
+	var err error
+	if st.State, err = GenerateStateFrom(randReader(p.Rand)); err != nil {
+		return "", err
+	}
+	if st.Nonce, err = GenerateNonceFrom(randReader(p.Rand)); err != nil {
+		return "", err
+	}
+	if st.CodeVerifier, err = GenerateCodeVerifierFrom(randReader(p.Rand)); err != nil {
+		return "", err
+	}
+	params := *p
+	params.State, params.Nonce, params.CodeChallenge = st.State, st.Nonce, CodeChallenge(st.CodeVerifier)
+	u, err := BuildAuthorizeURL(host, &params)
+	if err != nil {
+		return "", err
+	}
+	if err := store.Save(w, r, st); err != nil {
+		return "", err
+	}
+	return u, nil
+}
+
+// ResumeFlow handles the callback request of a flow begun with StartFlow.
+// It consumes the saved FlowState and returns it with the authorization code
+// to exchange. The state is checked and consumed before an error from
+// AuthVital is reported, so only the flow's own callback can fail it.
+func ResumeFlow(w http.ResponseWriter, r *http.Request, store StateStore) (*FlowState, string, error) {
+	q := r.URL.Query()
+	state := q.Get("state")
+	if state == "" {
+		return nil, "", errors.New("authvital: callback is missing state")
+	}
+	st, err := store.Consume(w, r, state)
+	if err != nil {
+		return nil, "", err
+	}
+	if e := q.Get("error"); e != "" {
+		return nil, "", &CallbackError{Code: e, Description: q.Get("error_description")}
+	}
+	code := q.Get("code")
+	if code == "" {
+		return nil, "", errors.New("authvital: callback is missing code")
+	}
+	return st, code, nil
+}
+
+// CallbackError is an error returned by AuthVital to the redirect URI.
+type CallbackError struct {
+	Code        string
+	Description string
+}
+
+func (e *CallbackError) Error() string {
+	if e.Description != "" {
+		return "authvital: " + e.Code + ": " + e.Description
+	}
+	return "authvital: " + e.Code
+}
@@ -0,0 +1,57 @@
+package authvital
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mapStateStore is a single-use StateStore in memory.
+type mapStateStore map[string]*FlowState
+
+func (m mapStateStore) Save(w http.ResponseWriter, r *http.Request, st *FlowState) error {
+	m[st.State] = st
+	return nil
+}
+
+func (m mapStateStore) Consume(w http.ResponseWriter, r *http.Request, state string) (*FlowState, error) {
+	st, ok := m[state]
+	if !ok {
+		return nil, ErrFlowStateNotFound
+	}
+	delete(m, state)
+	return st, nil
+}
+
+func TestResumeFlow(t *testing.T) {
+	resume := func(store StateStore, query string) (*FlowState, string, error) {
+		r := httptest.NewRequest("GET", "/callback?"+query, nil)
+		return ResumeFlow(httptest.NewRecorder(), r, store)
+	}
+
+	store := mapStateStore{"s1": {State: "s1"}}
+	// An error for an unknown state is not reported as a callback error.
+	if _, _, err := resume(store, "state=other&error=access_denied"); !errors.Is(err, ErrFlowStateNotFound) {
+		t.Errorf("forged error: %v, want ErrFlowStateNotFound", err)
+	}
+	if _, _, err := resume(store, "error=access_denied"); err == nil || errors.As(err, new(*CallbackError)) {
+		t.Errorf("error without state: %v", err)
+	}
+	var cbErr *CallbackError
+	if _, _, err := resume(store, "state=s1&error=access_denied&error_description=no"); !errors.As(err, &cbErr) || cbErr.Code != "access_denied" {
+		t.Errorf("callback error: %v", err)
+	}
+	if _, ok := store["s1"]; ok {
+		t.Error("state not consumed by a failed callback")
+	}
+
+	store["s2"] = &FlowState{State: "s2", CodeVerifier: "v"}
+	st, code, err := resume(store, "state=s2&code=c")
+	if err != nil || code != "c" || st.CodeVerifier != "v" {
+		t.Fatalf("ResumeFlow = %+v, %q, %v", st, code, err)
+	}
+	if _, _, err := resume(store, "state=s2&code=c"); !errors.Is(err, ErrFlowStateNotFound) {
+		t.Errorf("replayed state: %v", err)
+	}
+}
@@ -0,0 +1,6 @@
+package authvital
+
+// Bindings for endpoints without hand-written ones are generated from the
+// OpenAPI document named by $AUTHVITAL_OPENAPI_SPEC; without it, go
+// generate skips them and leaves openapi_gen.go as it is.
+//go:generate go run ./cmd/authvital-openapigen -optional -o openapi_gen.go
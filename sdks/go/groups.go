@@ -0,0 +1,51 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// Group is a named set of an organization's members.
+type Group struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// GroupSpec is a group's desired state for BulkSync.
+type GroupSpec struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Members identify users by the BulkSync match field.
+	Members []string `json:"members"`
+}
+
+// GroupsService manages an organization's groups.
+type GroupsService struct{}
+
+// List lists the organization's groups.
+func (s *GroupsService) List(ctx context.Context, orgID string, opts *ListOptions) (*List[Group], error) {
+	return nil, ErrNotImplemented
+}
+
+// Create creates a group. If g.Slug is empty it is derived from the name
+// with RoleSlug.
+func (s *GroupsService) Create(ctx context.Context, orgID string, g *Group) (*Group, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete deletes a group. Its members stay in the organization.
+func (s *GroupsService) Delete(ctx context.Context, orgID, slug string) error {
+	return ErrNotImplemented
+}
+
+// BulkSync creates or renames each group in specs and replaces its
+// members. Members are matched on matchField: "email", or a profile field
+// such as the one set by UserSync.ExternalIDField; unknown members are
+// reported in Errors. Groups not in specs are left alone.
+func (s *GroupsService) BulkSync(ctx context.Context, orgID string, specs []GroupSpec, matchField string) (*BulkResult, error) {
+	return nil, ErrNotImplemented
+}
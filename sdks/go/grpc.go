@@ -0,0 +1,375 @@
+package authvital
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GRPCPackage is the protobuf package of AuthVital's management API.
+const GRPCPackage = "authvital.management.v1"
+
+// WithGRPC sends management calls to AuthVital's gRPC API at target, such
+// as "https://grpc.auth.example.com", instead of JSON over HTTP/1.1. The
+// services and their methods are unchanged; only the transport differs.
+// It suits internal callers with high request rates, which benefit from
+// multiplexing calls over one HTTP/2 connection. See GRPCTransport.
+func WithGRPC(target string) Option {
+	return func(c *Client) {}
+}
+
+// GRPCTransport is an http.RoundTripper that turns the SDK's REST requests
+// into unary gRPC calls and their results back into REST responses, so
+// the services and error types work unchanged.
+//
+// The method is chosen by GRPCMethodName unless Methods overrides it. The
+// request message is the JSON body, with path IDs and query parameters
+// added as fields: the last ID is "id" and earlier ones are named after
+// their resource, such as "organizationId". Messages use gRPC's JSON
+// codec (application/grpc+json), so no generated protobuf code is needed.
+//
+// gRPC needs HTTP/2, which net/http only negotiates over TLS, so Target
+// must be an https URL.
+type GRPCTransport struct {
+	// Target is the gRPC endpoint's base URL.
+	Target string
+	// Base defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Methods overrides the gRPC method for routes, keyed by
+	// "<HTTP method> <route>" with the route as in RequestInfo, e.g.
+	// "POST /api/users/:id/deactivate".
+	Methods map[string]string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *GRPCTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(t.Target)
+	if err != nil || target.Scheme != "https" || target.Host == "" {
+		return nil, fmt.Errorf("authvital: gRPC target must be an https URL, got %q", t.Target)
+	}
+	route := routeTemplate(req.URL.Path)
+	method, ok := t.Methods[req.Method+" "+route]
+	if !ok {
+		method = GRPCMethodName(req.Method, req.URL.Path)
+	}
+	if method == "" {
+		return nil, fmt.Errorf("authvital: no gRPC method for %s %s", req.Method, route)
+	}
+	msg, err := grpcRequestMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(msg)))
+	copy(frame[5:], msg)
+	u := *target
+	u.Path = strings.TrimSuffix(u.Path, "/") + method
+	greq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, u.String(), bytes.NewReader(frame))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Header {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Type", "Content-Length", "Accept", "Accept-Encoding":
+		default:
+			greq.Header[k] = v
+		}
+	}
+	greq.Header.Set("Content-Type", "application/grpc+json")
+	greq.Header.Set("TE", "trailers")
+	if deadline, ok := req.Context().Deadline(); ok {
+		greq.Header.Set("Grpc-Timeout", grpcTimeout(time.Until(deadline)))
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(greq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		return nil, fmt.Errorf("authvital: gRPC target %s did not negotiate HTTP/2", target.Host)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Errors without a message may be sent as headers only.
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if resp.StatusCode != http.StatusOK && status == "" {
+		return grpcResponse(req, resp, resp.StatusCode, []byte(`{"code":"unavailable","message":"gRPC endpoint returned `+resp.Status+`"}`)), nil
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		// A call that ends without a valid status did not succeed.
+		code, message = 2, "gRPC response has no valid grpc-status"
+	}
+	if code != 0 {
+		message, _ = url.PathUnescape(message)
+		e, _ := json.Marshal(map[string]string{"code": grpcErrorCode(code), "message": message})
+		return grpcResponse(req, resp, grpcHTTPStatus(code), e), nil
+	}
+	if len(body) < 5 {
+		return grpcResponse(req, resp, http.StatusNoContent, nil), nil
+	}
+	if body[0] != 0 {
+		return nil, errors.New("authvital: compressed gRPC responses are not supported")
+	}
+	n := binary.BigEndian.Uint32(body[1:5])
+	if int64(n) > int64(len(body)-5) {
+		return nil, errors.New("authvital: truncated gRPC response")
+	}
+	httpCode := http.StatusOK
+	if req.Method == http.MethodPost {
+		httpCode = http.StatusCreated
+	}
+	return grpcResponse(req, resp, httpCode, body[5:5+n]), nil
+}
+
+// grpcRequestMessage builds the request message from the body, path IDs
+// and query parameters.
+func grpcRequestMessage(req *http.Request) ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if body = bytes.TrimSpace(body); len(body) > 0 {
+			if body[0] == '[' {
+				fields["items"] = body
+			} else if err := json.Unmarshal(body, &fields); err != nil {
+				return nil, fmt.Errorf("authvital: gRPC request body: %w", err)
+			}
+		}
+	}
+	_, ids := grpcPath(req.URL.Path)
+	for i, id := range ids {
+		name := "id"
+		if i < len(ids)-1 {
+			name = id.field
+		}
+		fields[name], _ = json.Marshal(id.value)
+	}
+	for k, v := range req.URL.Query() {
+		if len(v) == 1 {
+			fields[k], _ = json.Marshal(v[0])
+		} else {
+			fields[k], _ = json.Marshal(v)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+type grpcPathID struct {
+	field, value string
+}
+
+// grpcPath splits a REST path below /api into its resource words and IDs.
+func grpcPath(p string) (words []string, ids []grpcPathID) {
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "/"), "api/")
+	for _, seg := range strings.Split(p, "/") {
+		switch {
+		case seg == "":
+		case looksLikeID([]byte(seg)):
+			field := "id"
+			if len(words) > 0 {
+				field = lowerCamel(singular(words[len(words)-1])) + "Id"
+			}
+			ids = append(ids, grpcPathID{field: field, value: seg})
+		default:
+			words = append(words, seg)
+		}
+	}
+	return words, ids
+}
+
+// GRPCMethodName returns the gRPC method for a REST call, following the
+// standard method names of the management API: the service is named after
+// the top-level resource, and
+//
+//	GET    /api/users              /authvital.management.v1.UsersService/ListUsers
+//	GET    /api/users/{id}         .../UsersService/GetUser
+//	POST   /api/users              .../UsersService/CreateUser
+//	PATCH  /api/users/{id}         .../UsersService/UpdateUser
+//	DELETE /api/users/{id}         .../UsersService/DeleteUser
+//	POST   /api/users/{id}/disable .../UsersService/DisableUser
+//
+// Nested collections such as /api/organizations/{id}/members map to
+// ListMembers and friends on the top-level service. It returns "" for
+// paths with no resource.
+func GRPCMethodName(method, path string) string {
+	words, ids := grpcPath(path)
+	if len(words) == 0 {
+		return ""
+	}
+	service := upperCamel(words[0]) + "Service"
+	last := words[len(words)-1]
+	endsWithID := len(ids) > 0 && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/"+ids[len(ids)-1].value)
+	var rpc string
+	switch {
+	case endsWithID && method == http.MethodGet:
+		rpc = "Get" + upperCamel(singular(last))
+	case endsWithID && (method == http.MethodPut || method == http.MethodPatch):
+		rpc = "Update" + upperCamel(singular(last))
+	case endsWithID && method == http.MethodDelete:
+		rpc = "Delete" + upperCamel(singular(last))
+	case len(ids) > 0 && len(words) > 1 && !strings.HasSuffix(last, "s"):
+		// A custom method on a resource: POST /api/users/{id}/disable.
+		rpc = upperCamel(last) + upperCamel(singular(words[len(words)-2]))
+	case method == http.MethodGet:
+		rpc = "List" + upperCamel(last)
+	case method == http.MethodPost:
+		rpc = "Create" + upperCamel(singular(last))
+	case method == http.MethodDelete:
+		rpc = "Delete" + upperCamel(last)
+	default:
+		rpc = "Update" + upperCamel(last)
+	}
+	return "/" + GRPCPackage + "." + service + "/" + rpc
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// upperCamel converts a route word such as "api-keys" to "ApiKeys".
+func upperCamel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func lowerCamel(s string) string {
+	s = upperCamel(s)
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// grpcErrorCode maps a gRPC status code to the API's error code.
+func grpcErrorCode(code int) string {
+	switch code {
+	case 3:
+		return "invalid_request"
+	case 5:
+		return "not_found"
+	case 6:
+		return "conflict"
+	case 7:
+		return "forbidden"
+	case 8:
+		return "rate_limited"
+	case 9:
+		return "precondition_failed"
+	case 12:
+		return "not_implemented"
+	case 14:
+		return "unavailable"
+	case 16:
+		return "unauthorized"
+	case 4:
+		return "timeout"
+	}
+	return "internal_error"
+}
+
+// grpcHTTPStatus maps a gRPC status code to the HTTP status the REST API
+// would return.
+func grpcHTTPStatus(code int) int {
+	switch code {
+	case 3, 11:
+		return http.StatusBadRequest
+	case 16:
+		return http.StatusUnauthorized
+	case 7:
+		return http.StatusForbidden
+	case 5:
+		return http.StatusNotFound
+	case 6, 10:
+		return http.StatusConflict
+	case 9:
+		return http.StatusPreconditionFailed
+	case 8:
+		return http.StatusTooManyRequests
+	case 12:
+		return http.StatusNotImplemented
+	case 14:
+		return http.StatusServiceUnavailable
+	case 4:
+		return http.StatusGatewayTimeout
+	case 1:
+		return 499
+	}
+	return http.StatusInternalServerError
+}
+
+func grpcResponse(req *http.Request, g *http.Response, code int, body []byte) *http.Response {
+	h := http.Header{}
+	for _, k := range []string{"X-Request-Id", "Www-Authenticate", "Retry-After"} {
+		if v := g.Header.Get(k); v != "" {
+			h.Set(k, v)
+		}
+	}
+	if len(body) > 0 {
+		h.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// grpcTimeout formats d as a Grpc-Timeout value, which allows at most eight
+// digits: milliseconds when they fit, otherwise the finest of seconds,
+// minutes or hours that does, rounding up.
+func grpcTimeout(d time.Duration) string {
+	const maxValue = 99999999
+	for _, u := range []struct {
+		unit time.Duration
+		name string
+	}{{time.Millisecond, "m"}, {time.Second, "S"}, {time.Minute, "M"}, {time.Hour, "H"}} {
+		n := d / u.unit
+		if d%u.unit > 0 {
+			n++
+		}
+		if n = max(n, 1); n <= maxValue {
+			return strconv.FormatInt(int64(n), 10) + u.name
+		}
+	}
+	return strconv.Itoa(maxValue) + "H"
+}
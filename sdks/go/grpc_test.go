@@ -0,0 +1,27 @@
+package authvital
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGRPCTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Second, "1m"},
+		{0, "1m"},
+		{time.Microsecond, "1m"},
+		{1500 * time.Millisecond, "1500m"},
+		{99999999 * time.Millisecond, "99999999m"},
+		{100000000 * time.Millisecond, "100000S"},
+		{99999999*time.Second + 1, "1666667M"},
+		{100000000 * time.Minute, "1666667H"},
+		{time.Duration(1<<63 - 1), "2562048H"},
+	} {
+		if got := grpcTimeout(tc.d); got != tc.want {
+			t.Errorf("grpcTimeout(%v) = %s, want %s", tc.d, got, tc.want)
+		}
+	}
+}
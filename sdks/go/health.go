@@ -0,0 +1,264 @@
+package authvital
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CheckStatus is the outcome of one health check.
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	// CheckSkip means the check did not run, because it was not configured
+	// or an earlier check it depends on failed.
+	CheckSkip CheckStatus = "skip"
+)
+
+// CheckResult is the outcome of one health check.
+type CheckResult struct {
+	Name     string        `json:"name"`
+	Status   CheckStatus   `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// HealthReport is the result of a connectivity probe.
+type HealthReport struct {
+	Host   string        `json:"host"`
+	Checks []CheckResult `json:"checks"`
+	// ClockSkew is the local clock minus the server's, measured from the
+	// Date header. It is zero if it could not be measured.
+	ClockSkew time.Duration `json:"clockSkew"`
+}
+
+// OK reports whether no check failed.
+func (r *HealthReport) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status == CheckFail {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the report as one line per check, as printed by
+// `authvital doctor`.
+func (r *HealthReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Checks {
+		fmt.Fprintf(&b, "%-4s  %-12s %s\n", c.Status, c.Name, c.Detail)
+	}
+	return b.String()
+}
+
+// DiagnoseOptions configures Diagnose.
+type DiagnoseOptions struct {
+	// ClientID and ClientSecret, if set, are checked with a
+	// client_credentials grant.
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client
+	// MaxClockSkew is the skew above which the clock check warns.
+	// Defaults to 30 seconds.
+	MaxClockSkew time.Duration
+}
+
+// HealthCheck probes the client's AuthVital host; see Diagnose.
+func (c *Client) HealthCheck(ctx context.Context) (*HealthReport, error) {
+	return nil, ErrNotImplemented
+}
+
+// Diagnose probes an AuthVital host for the misconfigurations that most
+// often break SDK startup: DNS, TLS, issuer discovery, service health,
+// client credentials, and clock skew. It never returns an error; failures
+// are reported as checks, so the report can back a readiness probe.
+func Diagnose(ctx context.Context, host string, opts *DiagnoseOptions) *HealthReport {
+	var o DiagnoseOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if o.MaxClockSkew <= 0 {
+		o.MaxClockSkew = 30 * time.Second
+	}
+	host = strings.TrimRight(host, "/")
+	rep := &HealthReport{Host: host}
+	run := func(name string, check func() (CheckStatus, string)) CheckStatus {
+		start := time.Now()
+		status, detail := check()
+		rep.Checks = append(rep.Checks, CheckResult{Name: name, Status: status, Detail: detail, Duration: time.Since(start)})
+		return status
+	}
+	skip := func(name, why string) {
+		rep.Checks = append(rep.Checks, CheckResult{Name: name, Status: CheckSkip, Detail: why})
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || u.Host == "" {
+		run("config", func() (CheckStatus, string) { return CheckFail, fmt.Sprintf("invalid host %q", host) })
+		return rep
+	}
+
+	dns := run("dns", func() (CheckStatus, string) {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, u.Hostname())
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		return CheckPass, strings.Join(addrs, ", ")
+	})
+	if dns == CheckFail {
+		for _, n := range []string{"tls", "discovery", "health", "credentials", "clock"} {
+			skip(n, "dns failed")
+		}
+		return rep
+	}
+
+	if u.Scheme == "https" {
+		run("tls", func() (CheckStatus, string) { return checkTLS(ctx, u) })
+	} else {
+		run("tls", func() (CheckStatus, string) { return CheckWarn, "host does not use https" })
+	}
+
+	var serverDate time.Time
+	var tokenEndpoint string
+	disc := run("discovery", func() (CheckStatus, string) {
+		resp, err := getJSON(ctx, o.HTTPClient, host+"/.well-known/openid-configuration")
+		if err != nil {
+			return CheckFail, err.Error()
+		}
+		serverDate, _ = http.ParseTime(resp.header.Get("Date"))
+		var doc struct {
+			Issuer        string `json:"issuer"`
+			TokenEndpoint string `json:"token_endpoint"`
+		}
+		if err := json.Unmarshal(resp.body, &doc); err != nil {
+			return CheckFail, "invalid discovery document: " + err.Error()
+		}
+		tokenEndpoint = doc.TokenEndpoint
+		if tokenEndpoint == "" {
+			tokenEndpoint = host + "/oauth/token"
+		}
+		if strings.TrimRight(doc.Issuer, "/") != host {
+			return CheckWarn, fmt.Sprintf("issuer is %q; tokens will not verify against %q", doc.Issuer, host)
+		}
+		return CheckPass, "issuer " + doc.Issuer
+	})
+
+	run("health", func() (CheckStatus, string) {
+		if err := probeHealth(ctx, o.HTTPClient, host+"/api/health"); err != nil {
+			return CheckFail, err.Error()
+		}
+		return CheckPass, ""
+	})
+
+	switch {
+	case o.ClientID == "" || o.ClientSecret == "":
+		skip("credentials", "no client credentials configured")
+	case disc == CheckFail:
+		skip("credentials", "discovery failed")
+	default:
+		run("credentials", func() (CheckStatus, string) {
+			return checkCredentials(ctx, o.HTTPClient, tokenEndpoint, o.ClientID, o.ClientSecret)
+		})
+	}
+
+	if serverDate.IsZero() {
+		skip("clock", "server sent no Date header")
+	} else {
+		// Date has one-second resolution, so skew below that is noise.
+		rep.ClockSkew = time.Since(serverDate).Truncate(time.Second)
+		run("clock", func() (CheckStatus, string) {
+			skew := rep.ClockSkew
+			if skew < 0 {
+				skew = -skew
+			}
+			if skew > o.MaxClockSkew {
+				return CheckWarn, fmt.Sprintf("local clock differs from server by %s", rep.ClockSkew)
+			}
+			return CheckPass, fmt.Sprintf("skew %s", rep.ClockSkew)
+		})
+	}
+	return rep
+}
+
+func checkTLS(ctx context.Context, u *url.URL) (CheckStatus, string) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "443")
+	}
+	d := tls.Dialer{Config: &tls.Config{ServerName: u.Hostname()}}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+	defer conn.Close()
+	cert := conn.(*tls.Conn).ConnectionState().PeerCertificates[0]
+	left := time.Until(cert.NotAfter)
+	if left < 14*24*time.Hour {
+		return CheckWarn, fmt.Sprintf("certificate expires %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	return CheckPass, fmt.Sprintf("certificate valid until %s", cert.NotAfter.Format(time.RFC3339))
+}
+
+func checkCredentials(ctx context.Context, hc *http.Client, endpoint, id, secret string) (CheckStatus, string) {
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {id}, "client_secret": {secret}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		if e.Error != "" {
+			return CheckFail, strings.TrimSpace(e.Error + " " + e.Description)
+		}
+		return CheckFail, "token endpoint returned " + resp.Status
+	}
+	return CheckPass, "client " + id + " authenticated"
+}
+
+type jsonResponse struct {
+	header http.Header
+	body   json.RawMessage
+}
+
+func getJSON(ctx context.Context, hc *http.Client, u string) (*jsonResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return &jsonResponse{header: resp.Header, body: body}, nil
+}
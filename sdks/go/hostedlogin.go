@@ -0,0 +1,103 @@
+package authvital
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// Prompt is an OIDC prompt value.
+type Prompt string
+
+const (
+	// PromptNone fails instead of showing any UI, for silent sign-in checks.
+	PromptNone Prompt = "none"
+	// PromptLogin forces re-authentication even with a valid session.
+	PromptLogin Prompt = "login"
+	// PromptConsent shows the consent screen again.
+	PromptConsent Prompt = "consent"
+	// PromptSelectAccount shows the account chooser.
+	PromptSelectAccount Prompt = "select_account"
+)
+
+// ScreenHint selects the first screen of the hosted login page.
+type ScreenHint string
+
+const (
+	ScreenLogin  ScreenHint = "login"
+	ScreenSignup ScreenHint = "signup"
+)
+
+// LoginOptions customize the hosted login page. Set them on
+// AuthorizeParams.Login instead of hand-building query parameters. To
+// preselect an organization, set AuthorizeParams.TenantID or
+// TenantSubdomain.
+type LoginOptions struct {
+	Prompt []Prompt
+	// LoginHint pre-fills the email field.
+	LoginHint  string
+	ScreenHint ScreenHint
+	// UILocales are BCP 47 language tags in order of preference, such as
+	// "fr-CA" and "fr".
+	UILocales []string
+	// Invitation is an invitation ticket to accept during sign-in.
+	Invitation string
+}
+
+func (o *LoginOptions) validate() error {
+	if len(o.Prompt) > 1 {
+		for _, p := range o.Prompt {
+			if p == PromptNone {
+				return errors.New("authvital: prompt none cannot be combined with other prompts")
+			}
+		}
+	}
+	for _, tag := range o.UILocales {
+		if !validLanguageTag(tag) {
+			return errors.New("authvital: invalid UI locale " + tag)
+		}
+	}
+	return nil
+}
+
+func (o *LoginOptions) apply(q url.Values) {
+	if len(o.Prompt) > 0 {
+		ps := make([]string, len(o.Prompt))
+		for i, p := range o.Prompt {
+			ps[i] = string(p)
+		}
+		q.Set("prompt", strings.Join(ps, " "))
+	}
+	if o.LoginHint != "" {
+		q.Set("login_hint", o.LoginHint)
+	}
+	if o.ScreenHint != "" {
+		q.Set("screen_hint", string(o.ScreenHint))
+	}
+	if len(o.UILocales) > 0 {
+		q.Set("ui_locales", strings.Join(o.UILocales, " "))
+	}
+	if o.Invitation != "" {
+		q.Set("invitation", o.Invitation)
+	}
+}
+
+// validLanguageTag checks the shape of a BCP 47 tag: alphanumeric subtags
+// of one to eight characters separated by hyphens, starting with letters.
+func validLanguageTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for i, sub := range strings.Split(tag, "-") {
+		if len(sub) == 0 || len(sub) > 8 {
+			return false
+		}
+		for _, c := range sub {
+			letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+			if !letter && (i == 0 || c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
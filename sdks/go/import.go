@@ -0,0 +1,64 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// MaxImportUsers is the most users one Import call accepts.
+const MaxImportUsers = 10000
+
+// PasswordHashAlgorithm is a password hash scheme accepted on import.
+// Imported users sign in with their existing password, which is rehashed
+// with AuthVital's own scheme on first sign-in.
+type PasswordHashAlgorithm string
+
+const (
+	HashBcrypt PasswordHashAlgorithm = "bcrypt"
+	// HashFirebaseScrypt is Firebase's modified scrypt; it needs the
+	// project's signer key, salt separator, rounds and memory cost in
+	// PasswordHash.Params.
+	HashFirebaseScrypt PasswordHashAlgorithm = "firebase_scrypt"
+	HashPBKDF2SHA256   PasswordHashAlgorithm = "pbkdf2_sha256"
+	HashPBKDF2SHA1     PasswordHashAlgorithm = "pbkdf2_sha1"
+	HashSHA512         PasswordHashAlgorithm = "sha512"
+	HashSHA256         PasswordHashAlgorithm = "sha256"
+	HashSHA1           PasswordHashAlgorithm = "sha1"
+	HashMD5            PasswordHashAlgorithm = "md5"
+)
+
+// PasswordHash is a password hash from another system.
+type PasswordHash struct {
+	Algorithm PasswordHashAlgorithm `json:"algorithm"`
+	// Hash is the hash as exported: a modular crypt string for bcrypt,
+	// base64 for the others.
+	Hash string `json:"hash"`
+	// Salt is base64, for algorithms that take it separately.
+	Salt string `json:"salt,omitempty"`
+	// SaltOrder is "prefix" or "suffix": where a salted digest puts the
+	// salt relative to the password. Defaults to "prefix".
+	SaltOrder string `json:"saltOrder,omitempty"`
+	// Rounds is the iteration count or work factor.
+	Rounds int               `json:"rounds,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// ImportUser is a user to import.
+type ImportUser struct {
+	// ExternalID is the user's ID in the source system, kept as
+	// profile.importedId so references can be rewritten later.
+	ExternalID    string         `json:"externalId,omitempty"`
+	Email         string         `json:"email"`
+	EmailVerified bool           `json:"emailVerified"`
+	Phone         string         `json:"phone,omitempty"`
+	Profile       map[string]any `json:"profile,omitempty"`
+	Password      *PasswordHash  `json:"password,omitempty"`
+	Blocked       bool           `json:"blocked,omitempty"`
+	CreatedAt     time.Time      `json:"createdAt,omitempty"`
+}
+
+// Import starts a job importing users. Users whose email already exists
+// are reported as job item errors and left unchanged.
+func (s *UsersService) Import(ctx context.Context, users []ImportUser) (*Job, error) {
+	return nil, ErrNotImplemented
+}
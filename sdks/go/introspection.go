@@ -0,0 +1,231 @@
+package authvital
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Introspection is a token introspection response (RFC 7662). Inactive
+// tokens have only Active set.
+type Introspection struct {
+	Active    bool        `json:"active"`
+	Subject   string      `json:"sub,omitempty"`
+	Email     string      `json:"email,omitempty"`
+	Scope     string      `json:"scope,omitempty"`
+	ClientID  string      `json:"client_id,omitempty"`
+	Audience  StringList  `json:"aud,omitempty"`
+	Issuer    string      `json:"iss,omitempty"`
+	ExpiresAt NumericDate `json:"exp,omitempty"`
+	IssuedAt  NumericDate `json:"iat,omitempty"`
+	// Tenants are the user's active memberships.
+	Tenants       []IntrospectionTenant `json:"tenants,omitempty"`
+	RolesByTenant map[string][]string   `json:"rolesByTenant,omitempty"`
+	IsMachine     bool                  `json:"isMachine,omitempty"`
+}
+
+// clone returns a deep copy of r, so cached results are not shared with
+// callers.
+func (r *Introspection) clone() *Introspection {
+	c := *r
+	c.Audience = append(StringList(nil), r.Audience...)
+	c.Tenants = append([]IntrospectionTenant(nil), r.Tenants...)
+	if r.RolesByTenant != nil {
+		c.RolesByTenant = make(map[string][]string, len(r.RolesByTenant))
+		for t, roles := range r.RolesByTenant {
+			c.RolesByTenant[t] = append([]string(nil), roles...)
+		}
+	}
+	return &c
+}
+
+// IntrospectionTenant is an organization in an introspection response.
+type IntrospectionTenant struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Introspector checks opaque or revocable tokens with AuthVital's
+// introspection endpoint, optionally caching the results.
+type Introspector struct {
+	Host         string
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client
+	// Cache, if set, serves repeated introspections of the same token.
+	Cache *IntrospectionCache
+}
+
+// Introspect returns the state of token. An invalid or expired token is
+// not an error; it returns an Introspection with Active false.
+func (i *Introspector) Introspect(ctx context.Context, token string) (*Introspection, error) {
+	if i.Cache != nil {
+		if r, ok := i.Cache.Get(token); ok {
+			return r, nil
+		}
+	}
+	hc := i.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	form := url.Values{"token": {token}, "client_id": {i.ClientID}}
+	if i.ClientSecret != "" {
+		form.Set("client_secret", i.ClientSecret)
+	}
+	endpoint := strings.TrimRight(i.Host, "/") + "/oauth/introspect"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+	var r Introspection
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("authvital: decoding introspection: %w", err)
+	}
+	if i.Cache != nil {
+		i.Cache.Set(token, &r)
+	}
+	return &r, nil
+}
+
+// IntrospectionCacheOptions configure an IntrospectionCache.
+type IntrospectionCacheOptions struct {
+	// Shards is the number of independently locked shards, rounded up to a
+	// power of two. Defaults to 32; raise it if profiles show contention.
+	Shards int
+	// TTL bounds how long an active result is reused, and so how long a
+	// revoked token may still be accepted. Defaults to 30 seconds. Results
+	// are never kept past the token's own expiry.
+	TTL time.Duration
+	// NegativeTTL is how long inactive results are reused. It blunts
+	// floods of invalid tokens. Defaults to 5 seconds.
+	NegativeTTL time.Duration
+	// MaxEntries bounds the entries per shard. Defaults to 4096.
+	MaxEntries int
+	// Clock expires entries; it defaults to the system clock.
+	Clock Clock
+}
+
+// IntrospectionCache caches introspection results in memory, keyed by a
+// SHA-256 of the token so tokens themselves are not retained.
+type IntrospectionCache struct {
+	opts   IntrospectionCacheOptions
+	mask   uint64
+	shards []introspectionShard
+}
+
+type introspectionShard struct {
+	mu      sync.Mutex
+	entries map[[32]byte]introspectionEntry
+}
+
+type introspectionEntry struct {
+	result  *Introspection
+	expires time.Time
+}
+
+// NewIntrospectionCache returns an empty IntrospectionCache.
+func NewIntrospectionCache(opts IntrospectionCacheOptions) *IntrospectionCache {
+	if opts.Shards <= 0 {
+		opts.Shards = 32
+	}
+	n := 1
+	for n < opts.Shards {
+		n <<= 1
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 30 * time.Second
+	}
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = 5 * time.Second
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 4096
+	}
+	c := &IntrospectionCache{opts: opts, mask: uint64(n - 1), shards: make([]introspectionShard, n)}
+	for i := range c.shards {
+		c.shards[i].entries = map[[32]byte]introspectionEntry{}
+	}
+	return c
+}
+
+func (c *IntrospectionCache) shard(token string) (*introspectionShard, [32]byte) {
+	key := sha256.Sum256([]byte(token))
+	return &c.shards[binary.LittleEndian.Uint64(key[:8])&c.mask], key
+}
+
+// Get returns a copy of the cached result for token, which the caller may
+// modify.
+func (c *IntrospectionCache) Get(token string) (*Introspection, bool) {
+	s, key := c.shard(token)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if now(c.opts.Clock).After(e.expires) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return e.result.clone(), true
+}
+
+// Set caches r for token.
+func (c *IntrospectionCache) Set(token string, r *Introspection) {
+	now := now(c.opts.Clock)
+	expires := now.Add(c.opts.NegativeTTL)
+	if r.Active {
+		expires = now.Add(c.opts.TTL)
+		if r.ExpiresAt != 0 && r.ExpiresAt.Time().Before(expires) {
+			expires = r.ExpiresAt.Time()
+		}
+	}
+	s, key := c.shard(token)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) >= c.opts.MaxEntries {
+		s.evictExpired(now)
+		if len(s.entries) >= c.opts.MaxEntries {
+			// Map iteration order is random, so this drops an arbitrary
+			// entry without tracking recency on the hot path.
+			for k := range s.entries {
+				delete(s.entries, k)
+				break
+			}
+		}
+	}
+	s.entries[key] = introspectionEntry{result: r.clone(), expires: expires}
+}
+
+// Delete removes token's entry, for example after revoking it.
+func (c *IntrospectionCache) Delete(token string) {
+	s, key := c.shard(token)
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+}
+
+func (s *introspectionShard) evictExpired(now time.Time) {
+	for k, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+}
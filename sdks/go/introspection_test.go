@@ -0,0 +1,35 @@
+package authvital
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntrospectionCacheReturnsCopies(t *testing.T) {
+	c := NewIntrospectionCache(IntrospectionCacheOptions{})
+	want := &Introspection{
+		Active:        true,
+		Subject:       "usr_1",
+		Audience:      StringList{"https://api.example.com"},
+		Tenants:       []IntrospectionTenant{{ID: "org_1", Slug: "acme"}},
+		RolesByTenant: map[string][]string{"org_1": {"member"}},
+	}
+	in := want.clone()
+	c.Set("tok", in)
+	in.RolesByTenant["org_1"][0] = "owner"
+
+	got, ok := c.Get("tok")
+	if !ok {
+		t.Fatal("cached result missing")
+	}
+	got.Subject = "usr_2"
+	got.Audience[0] = "https://evil.example.com"
+	got.Tenants[0].ID = "org_2"
+	got.RolesByTenant["org_1"][0] = "owner"
+	got.RolesByTenant["org_2"] = []string{"owner"}
+
+	again, _ := c.Get("tok")
+	if !reflect.DeepEqual(again, want) {
+		t.Fatalf("cached result changed through a returned pointer: %+v", again)
+	}
+}
@@ -0,0 +1,79 @@
+package authvital
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+)
+
+// RequestObjectOptions configures a JWT-secured authorization request
+// (RFC 9101).
+type RequestObjectOptions struct {
+	// Key signs the request object. Its public key must be registered on the
+	// application.
+	Key crypto.Signer
+	// Alg is the signing algorithm. Defaults from the key type.
+	Alg string
+	// KeyID is set as the kid header.
+	KeyID string
+	// Audience is the issuer URL of the AuthVital tenant.
+	Audience string
+	// Lifetime bounds how long the request object is accepted. Defaults to
+	// five minutes.
+	Lifetime time.Duration
+	// EncryptTo, when set, additionally encrypts the signed request object
+	// to this key, e.g. the tenant's published encryption key.
+	EncryptTo *rsa.PublicKey
+	// Encryption configures the JWE when EncryptTo is set.
+	Encryption *JWEOptions
+	// Clock and Rand set the request object's times and JWT ID. They
+	// default to the system clock and crypto/rand.
+	Clock Clock
+	Rand  io.Reader
+}
+
+func signRequestObject(q url.Values, o *RequestObjectOptions) (string, error) {
+	if o.Key == nil || o.Audience == "" {
+		return "", errors.New("authvital: request object requires a key and audience")
+	}
+	lifetime := o.Lifetime
+	if lifetime == 0 {
+		lifetime = 5 * time.Minute
+	}
+	now := now(o.Clock)
+	claims := make(map[string]any, len(q)+4)
+	for k, v := range q {
+		if len(v) == 1 {
+			claims[k] = v[0]
+		} else {
+			claims[k] = v
+		}
+	}
+	claims["iss"] = q.Get("client_id")
+	claims["aud"] = o.Audience
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["exp"] = now.Add(lifetime).Unix()
+	jti, err := randomString(o.Rand, 16)
+	if err != nil {
+		return "", err
+	}
+	claims["jti"] = jti
+
+	signed, err := signJWT(o.Key, o.Alg, o.KeyID, "oauth-authz-req+jwt", claims)
+	if err != nil || o.EncryptTo == nil {
+		return signed, err
+	}
+	var enc JWEOptions
+	if o.Encryption != nil {
+		enc = *o.Encryption
+	}
+	enc.ContentType = "JWT"
+	if enc.Rand == nil {
+		enc.Rand = o.Rand
+	}
+	return EncryptJWE([]byte(signed), o.EncryptTo, &enc)
+}
@@ -0,0 +1,131 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// JobStatus is the state of a long-running job.
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCanceled  JobStatus = "canceled"
+)
+
+// Done reports whether the status is terminal.
+func (s JobStatus) Done() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// Job is a long-running server-side operation such as an import, export,
+// or bulk delete.
+type Job struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	Status JobStatus `json:"status"`
+	// Progress is the completed percentage, from 0 to 100.
+	Progress   float64    `json:"progress"`
+	Processed  int64      `json:"processed"`
+	Total      int64      `json:"total"`
+	ErrorCount int64      `json:"errorCount"`
+	Message    string     `json:"message,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// Result is job-type specific output, e.g. an export download URL.
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// JobItemError reports an item a job failed to process.
+type JobItemError struct {
+	// Index is the item's position in the job input.
+	Index   int64  `json:"index"`
+	ItemID  string `json:"itemId,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// WaitOptions controls polling in WaitForJob.
+type WaitOptions struct {
+	// InitialInterval is the first poll delay. Defaults to 500ms.
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff. Defaults to 10s.
+	MaxInterval time.Duration
+	// OnProgress, if set, is called after every poll.
+	OnProgress func(*Job)
+}
+
+// WaitForJob polls get with exponential backoff until the job finishes or
+// ctx is done, and returns the final job. A job that fails or is canceled
+// is returned without error; check its Status.
+func WaitForJob(ctx context.Context, get func(context.Context) (*Job, error), opts *WaitOptions) (*Job, error) {
+	var o WaitOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = 500 * time.Millisecond
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = 10 * time.Second
+	}
+
+	interval := o.InitialInterval
+	for {
+		job, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			return nil, errors.New("authvital: job lookup returned no job")
+		}
+		if o.OnProgress != nil {
+			o.OnProgress(job)
+		}
+		if job.Status.Done() {
+			return job, nil
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return job, ctx.Err()
+		case <-t.C:
+		}
+		if interval *= 2; interval > o.MaxInterval {
+			interval = o.MaxInterval
+		}
+	}
+}
+
+// JobsService tracks long-running jobs.
+type JobsService struct{}
+
+// Get retrieves a job.
+func (s *JobsService) Get(ctx context.Context, jobID string) (*Job, error) {
+	return nil, ErrNotImplemented
+}
+
+// Wait polls a job until it finishes.
+func (s *JobsService) Wait(ctx context.Context, jobID string, opts *WaitOptions) (*Job, error) {
+	return WaitForJob(ctx, func(ctx context.Context) (*Job, error) {
+		return s.Get(ctx, jobID)
+	}, opts)
+}
+
+// Cancel requests cancellation of a job. Items already processed are not
+// rolled back.
+func (s *JobsService) Cancel(ctx context.Context, jobID string) (*Job, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListErrors lists the items a job failed to process.
+func (s *JobsService) ListErrors(ctx context.Context, jobID string, opts *ListOptions) (*List[JobItemError], error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,236 @@
+package authvital
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JWE key management algorithms supported by EncryptJWE and DecryptJWE.
+const (
+	KeyAlgRSAOAEP    = "RSA-OAEP"
+	KeyAlgRSAOAEP256 = "RSA-OAEP-256"
+)
+
+// JWE content encryption algorithms supported by EncryptJWE and DecryptJWE.
+const (
+	EncA128GCM = "A128GCM"
+	EncA192GCM = "A192GCM"
+	EncA256GCM = "A256GCM"
+)
+
+// ErrUnsupportedJWE is returned for JWEs using an algorithm this package
+// does not implement.
+var ErrUnsupportedJWE = errors.New("authvital: unsupported JWE algorithm")
+
+// WithEncryptionKey sets the private key used to decrypt encrypted ID tokens
+// and userinfo responses. The matching public key must be registered on the
+// application. The key may be held in an HSM or KMS; it must be an RSA key
+// supporting OAEP decryption. Client.DecryptionKey returns it for
+// WithDecryptionKey and UserInfoParams.
+func WithEncryptionKey(key crypto.Decrypter) Option {
+	return func(c *Client) { c.decryptionKey = key }
+}
+
+// DecryptionKey returns the key set by WithEncryptionKey, or nil.
+func (c *Client) DecryptionKey() crypto.Decrypter {
+	return c.decryptionKey
+}
+
+// WithDecryptionKey makes the Verifier accept encrypted tokens (JWEs
+// wrapping a signed JWT), decrypting them with key before verification.
+// Clients configured WithEncryptionKey pass that key to their Verifier.
+func WithDecryptionKey(key crypto.Decrypter) VerifierOption {
+	return func(v *Verifier) { v.decryptionKey = key }
+}
+
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	// Zip and Crit are only read to reject tokens using them.
+	Zip  string   `json:"zip,omitempty"`
+	Crit []string `json:"crit,omitempty"`
+}
+
+// JWEOptions configures EncryptJWE.
+type JWEOptions struct {
+	// Alg is the key management algorithm. Defaults to KeyAlgRSAOAEP256.
+	Alg string
+	// Enc is the content encryption algorithm. Defaults to EncA256GCM.
+	Enc string
+	// KeyID is set as the kid header.
+	KeyID string
+	// ContentType is set as the cty header, e.g. "JWT" for nested tokens.
+	ContentType string
+	// Rand supplies the content key, IV and OAEP padding; it defaults to
+	// crypto/rand.
+	Rand io.Reader
+}
+
+// EncryptJWE encrypts payload to key in JWE compact serialization, e.g. for
+// request objects sent to the authorization endpoint.
+func EncryptJWE(payload []byte, key *rsa.PublicKey, opts *JWEOptions) (string, error) {
+	var o JWEOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Alg == "" {
+		o.Alg = KeyAlgRSAOAEP256
+	}
+	if o.Enc == "" {
+		o.Enc = EncA256GCM
+	}
+	h, err := jweOAEPHash(o.Alg)
+	if err != nil {
+		return "", err
+	}
+	size, err := jweKeySize(o.Enc)
+	if err != nil {
+		return "", err
+	}
+
+	cek := make([]byte, size)
+	if _, err := io.ReadFull(randReader(o.Rand), cek); err != nil {
+		return "", err
+	}
+	encKey, err := rsa.EncryptOAEP(h.New(), randReader(o.Rand), key, cek, nil)
+	if err != nil {
+		return "", fmt.Errorf("authvital: encrypt JWE key: %w", err)
+	}
+
+	hdr, err := json.Marshal(jweHeader{Alg: o.Alg, Enc: o.Enc, Kid: o.KeyID, Cty: o.ContentType})
+	if err != nil {
+		return "", err
+	}
+	protected := b64.EncodeToString(hdr)
+
+	gcm, err := jweGCM(cek)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(randReader(o.Rand), iv); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nil, iv, payload, []byte(protected))
+	ct, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return strings.Join([]string{
+		protected,
+		b64.EncodeToString(encKey),
+		b64.EncodeToString(iv),
+		b64.EncodeToString(ct),
+		b64.EncodeToString(tag),
+	}, "."), nil
+}
+
+// DecryptJWE decrypts a JWE in compact serialization with key, an RSA
+// private key such as *rsa.PrivateKey or an HSM or KMS handle, and returns
+// the plaintext. For encrypted ID tokens the plaintext is the signed JWT.
+// Compressed JWEs and JWEs with critical header extensions are rejected.
+func DecryptJWE(token string, key crypto.Decrypter) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, errors.New("authvital: malformed JWE")
+	}
+	raw := make([][]byte, 5)
+	for i, p := range parts {
+		b, err := b64.DecodeString(p)
+		if err != nil {
+			return nil, fmt.Errorf("authvital: malformed JWE: %w", err)
+		}
+		raw[i] = b
+	}
+
+	var hdr jweHeader
+	if err := json.Unmarshal(raw[0], &hdr); err != nil {
+		return nil, fmt.Errorf("authvital: malformed JWE header: %w", err)
+	}
+	if hdr.Zip != "" {
+		return nil, fmt.Errorf("%w: zip %q", ErrUnsupportedJWE, hdr.Zip)
+	}
+	if hdr.Crit != nil {
+		// No JWE header extensions are understood, so any critical one
+		// must be rejected (RFC 7516, section 4.1.13).
+		return nil, fmt.Errorf("%w: critical headers %q", ErrUnsupportedJWE, hdr.Crit)
+	}
+	if _, ok := key.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("%w: %s needs an RSA key", ErrUnsupportedJWE, hdr.Alg)
+	}
+	h, err := jweOAEPHash(hdr.Alg)
+	if err != nil {
+		return nil, err
+	}
+	size, err := jweKeySize(hdr.Enc)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := key.Decrypt(rand.Reader, raw[1], &rsa.OAEPOptions{Hash: h})
+	if err != nil {
+		return nil, fmt.Errorf("authvital: decrypt JWE key: %w", err)
+	}
+	if len(cek) != size {
+		return nil, errors.New("authvital: JWE content key has wrong size")
+	}
+	gcm, err := jweGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw[2]) != gcm.NonceSize() {
+		return nil, errors.New("authvital: JWE IV has wrong size")
+	}
+	// GCM would also accept truncated tags, which are easier to forge.
+	if len(raw[4]) != gcm.Overhead() {
+		return nil, errors.New("authvital: JWE authentication tag has wrong size")
+	}
+	plain, err := gcm.Open(nil, raw[2], append(raw[3], raw[4]...), []byte(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("authvital: decrypt JWE: %w", err)
+	}
+	return plain, nil
+}
+
+var b64 = base64.RawURLEncoding
+
+func jweOAEPHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case KeyAlgRSAOAEP:
+		return crypto.SHA1, nil
+	case KeyAlgRSAOAEP256:
+		return crypto.SHA256, nil
+	}
+	return 0, fmt.Errorf("%w: alg %q", ErrUnsupportedJWE, alg)
+}
+
+func jweKeySize(enc string) (int, error) {
+	switch enc {
+	case EncA128GCM:
+		return 16, nil
+	case EncA192GCM:
+		return 24, nil
+	case EncA256GCM:
+		return 32, nil
+	}
+	return 0, fmt.Errorf("%w: enc %q", ErrUnsupportedJWE, enc)
+}
+
+func jweGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
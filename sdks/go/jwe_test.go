@@ -0,0 +1,101 @@
+package authvital
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJWERoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, alg := range []string{KeyAlgRSAOAEP, KeyAlgRSAOAEP256} {
+		for _, enc := range []string{EncA128GCM, EncA192GCM, EncA256GCM} {
+			token, err := EncryptJWE([]byte("hello"), &key.PublicKey, &JWEOptions{Alg: alg, Enc: enc})
+			if err != nil {
+				t.Fatalf("%s %s: %v", alg, enc, err)
+			}
+			plain, err := DecryptJWE(token, key)
+			if err != nil || string(plain) != "hello" {
+				t.Errorf("%s %s: DecryptJWE = %q, %v", alg, enc, plain, err)
+			}
+		}
+	}
+}
+
+func TestDecryptJWERejects(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := EncryptJWE([]byte("hello"), &key.PublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	withHeader := func(h map[string]any) string {
+		b, _ := json.Marshal(h)
+		p := append([]string{b64.EncodeToString(b)}, parts[1:]...)
+		return strings.Join(p, ".")
+	}
+	tag, _ := b64.DecodeString(parts[4])
+	truncated := strings.Join(append(parts[:4:4], b64.EncodeToString(tag[:12])), ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"truncated tag", truncated},
+		{"zip", withHeader(map[string]any{"alg": KeyAlgRSAOAEP256, "enc": EncA256GCM, "zip": "DEF"})},
+		{"crit", withHeader(map[string]any{"alg": KeyAlgRSAOAEP256, "enc": EncA256GCM, "crit": []string{"exp"}, "exp": 1})},
+		{"unknown alg", withHeader(map[string]any{"alg": "RSA1_5", "enc": EncA256GCM})},
+		{"tampered header", withHeader(map[string]any{"alg": KeyAlgRSAOAEP256, "enc": EncA256GCM, "kid": "x"})},
+		{"four parts", strings.Join(parts[:4], ".")},
+	}
+	for _, tt := range tests {
+		if _, err := DecryptJWE(tt.token, key); err == nil {
+			t.Errorf("%s: accepted", tt.name)
+		}
+	}
+}
+
+func TestFetchUserInfoEncrypted(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := EncryptJWE([]byte(`{"sub":"usr_1","email":"jane@example.com"}`), &key.PublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/userinfo" || r.Header.Get("Authorization") != "Bearer at" {
+			http.Error(w, `{"code":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwt")
+		w.Write([]byte(token))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	claims, err := FetchUserInfo(ctx, nil, srv.URL, &UserInfoParams{AccessToken: "at", DecryptionKey: key})
+	if err != nil || claims.Subject() != "usr_1" {
+		t.Fatalf("FetchUserInfo = %v, %v", claims, err)
+	}
+	if _, err := FetchUserInfo(ctx, nil, srv.URL, &UserInfoParams{AccessToken: "at"}); err == nil {
+		t.Error("encrypted userinfo decoded without a key")
+	}
+	var apiErr *APIError
+	if _, err := FetchUserInfo(ctx, nil, srv.URL, &UserInfoParams{AccessToken: "bad", DecryptionKey: key}); !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Errorf("unauthorized: %v", err)
+	}
+}
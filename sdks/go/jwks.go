@@ -0,0 +1,246 @@
+package authvital
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound is returned when a token's kid is not in the issuer's JWKS.
+var ErrKeyNotFound = errors.New("authvital: signing key not found")
+
+// JWK is a JSON Web Key as published at /.well-known/jwks.json.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey decodes the key material.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("authvital: unsupported EC curve %q", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("authvital: EC key is not on its curve")
+		}
+		return pub, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("authvital: unsupported OKP curve %q", k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("authvital: Ed25519 key has wrong size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("authvital: unsupported key type %q", k.Kty)
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of the key,
+// base64url-encoded.
+func (k *JWK) Thumbprint() (string, error) {
+	var members string
+	switch k.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	case "EC":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, k.Crv, k.X, k.Y)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, k.Crv, k.X)
+	default:
+		return "", fmt.Errorf("authvital: unsupported key type %q", k.Kty)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return b64.EncodeToString(sum[:]), nil
+}
+
+// verifyKey is a decoded JWKS entry.
+type verifyKey struct {
+	pub        crypto.PublicKey
+	alg        string
+	thumbprint string
+}
+
+// jwksMinRefresh rate-limits refetches triggered by unknown kids, so tokens
+// with garbage kids cannot be used to hammer the JWKS endpoint.
+const jwksMinRefresh = 30 * time.Second
+
+// keySet caches the keys of one JWKS endpoint.
+type keySet struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+	// onDate, if set, receives the server's Date header of each fetch.
+	onDate func(server, local time.Time)
+	clock  Clock
+
+	mu        sync.RWMutex
+	keys      map[string]verifyKey
+	fetchedAt time.Time
+
+	// fetching is the in-flight refresh, shared by concurrent callers so a
+	// burst of requests on a cold or expired set makes one fetch.
+	fetchMu  sync.Mutex
+	fetching *keyFetch
+}
+
+type keyFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func newKeySet(url string, client *http.Client, ttl time.Duration, clock Clock) *keySet {
+	return &keySet{url: url, client: client, ttl: ttl, clock: clock}
+}
+
+// key returns the key with kid, refetching the set when it has expired or
+// does not contain kid.
+func (s *keySet) key(ctx context.Context, kid string) (verifyKey, error) {
+	s.mu.RLock()
+	k, ok := s.keys[kid]
+	age := now(s.clock).Sub(s.fetchedAt)
+	fresh := age < s.ttl
+	recent := age < jwksMinRefresh
+	s.mu.RUnlock()
+	if ok && fresh {
+		return k, nil
+	}
+	if !ok && recent {
+		return verifyKey{}, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+	}
+	if err := s.refresh(ctx); err != nil {
+		if ok {
+			// Serve the stale key rather than fail while the endpoint is down.
+			return k, nil
+		}
+		return verifyKey{}, err
+	}
+	s.mu.RLock()
+	k, ok = s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return verifyKey{}, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+	}
+	return k, nil
+}
+
+// refresh fetches the set, joining a fetch already in flight.
+func (s *keySet) refresh(ctx context.Context) error {
+	s.fetchMu.Lock()
+	f := s.fetching
+	if f == nil {
+		f = &keyFetch{done: make(chan struct{})}
+		s.fetching = f
+		s.fetchMu.Unlock()
+		// The fetch outlives any one caller's cancellation, since others
+		// may be waiting on it; the client's timeout still bounds it.
+		f.err = s.fetch(context.WithoutCancel(ctx))
+		s.fetchMu.Lock()
+		s.fetching = nil
+		s.fetchMu.Unlock()
+		close(f.done)
+		return f.err
+	}
+	s.fetchMu.Unlock()
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *keySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("authvital: fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if s.onDate != nil {
+		if d, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+			s.onDate(d, now(s.clock))
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authvital: fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("authvital: decode JWKS: %w", err)
+	}
+	keys := make(map[string]verifyKey, len(set.Keys))
+	for i := range set.Keys {
+		jwk := &set.Keys[i]
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Skip keys we cannot use instead of failing the whole set.
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		thumb, _ := jwk.Thumbprint()
+		keys[jwk.Kid] = verifyKey{pub: pub, alg: jwk.Alg, thumbprint: thumb}
+	}
+	s.mu.Lock()
+	s.keys, s.fetchedAt = keys, now(s.clock)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,112 @@
+package authvital
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JWS signing algorithms used by SDK-issued JWTs.
+const (
+	SigRS256 = "RS256"
+	SigPS256 = "PS256"
+	SigES256 = "ES256"
+	SigES384 = "ES384"
+	SigEdDSA = "EdDSA"
+)
+
+type jwsHeader struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid,omitempty"`
+	Typ  string   `json:"typ,omitempty"`
+	Crit []string `json:"crit,omitempty"`
+}
+
+// defaultSigAlg picks the signing algorithm for key when none is configured.
+func defaultSigAlg(key crypto.Signer) (string, error) {
+	switch k := key.Public().(type) {
+	case *rsa.PublicKey:
+		return SigRS256, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return SigES256, nil
+		case 384:
+			return SigES384, nil
+		}
+	case ed25519.PublicKey:
+		return SigEdDSA, nil
+	}
+	return "", fmt.Errorf("authvital: unsupported signing key %T", key.Public())
+}
+
+// signJWT signs claims as a compact JWS.
+func signJWT(key crypto.Signer, alg, kid, typ string, claims any) (string, error) {
+	if alg == "" {
+		var err error
+		if alg, err = defaultSigAlg(key); err != nil {
+			return "", err
+		}
+	}
+	hdr, err := json.Marshal(jwsHeader{Alg: alg, Kid: kid, Typ: typ})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := b64.EncodeToString(hdr) + "." + b64.EncodeToString(payload)
+
+	var sig []byte
+	switch alg {
+	case SigRS256:
+		sig, err = signDigest(key, crypto.SHA256, input, nil)
+	case SigPS256:
+		sig, err = signDigest(key, crypto.SHA256, input, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256})
+	case SigES256:
+		sig, err = signECDSA(key, crypto.SHA256, 32, input)
+	case SigES384:
+		sig, err = signECDSA(key, crypto.SHA384, 48, input)
+	case SigEdDSA:
+		sig, err = key.Sign(rand.Reader, []byte(input), crypto.Hash(0))
+	default:
+		return "", fmt.Errorf("authvital: unsupported signing algorithm %q", alg)
+	}
+	if err != nil {
+		return "", fmt.Errorf("authvital: sign JWT: %w", err)
+	}
+	return input + "." + b64.EncodeToString(sig), nil
+}
+
+func signDigest(key crypto.Signer, h crypto.Hash, input string, opts crypto.SignerOpts) ([]byte, error) {
+	d := h.New()
+	d.Write([]byte(input))
+	if opts == nil {
+		opts = h
+	}
+	return key.Sign(rand.Reader, d.Sum(nil), opts)
+}
+
+// signECDSA signs input and converts the ASN.1 signature to the fixed-size
+// r||s form JWS requires.
+func signECDSA(key crypto.Signer, h crypto.Hash, size int, input string) ([]byte, error) {
+	der, err := signDigest(key, h, input, nil)
+	if err != nil {
+		return nil, err
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 2*size)
+	rs.R.FillBytes(sig[:size])
+	rs.S.FillBytes(sig[size:])
+	return sig, nil
+}
@@ -0,0 +1,293 @@
+// Package ldapsync synchronizes users and groups from LDAP or Active
+// Directory into AuthVital. It pages through the directory, maps entries
+// with configurable attribute rules, and applies the result through
+// authvital.UserSync and GroupsService.BulkSync.
+//
+// The package does not speak LDAP itself; implement Searcher with an LDAP
+// client such as github.com/go-ldap/ldap:
+//
+//	func (c *conn) Search(ctx context.Context, r *ldapsync.SearchRequest) (*ldapsync.SearchPage, error) {
+//		paging := ldap.NewControlPaging(r.PageSize)
+//		paging.SetCookie(r.Cookie)
+//		res, err := c.l.Search(ldap.NewSearchRequest(r.BaseDN, ldap.ScopeWholeSubtree,
+//			ldap.NeverDerefAliases, 0, 0, false, r.Filter, r.Attributes, []ldap.Control{paging}))
+//		...
+//	}
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	authvital "github.com/authvital/authvital/sdks/go"
+)
+
+// SearchRequest is one page of a subtree search with the paged results
+// control (RFC 2696).
+type SearchRequest struct {
+	BaseDN     string
+	Filter     string
+	Attributes []string
+	PageSize   uint32
+	// Cookie is empty for the first page and the previous page's cookie
+	// after that.
+	Cookie []byte
+}
+
+// SearchPage is a page of search results. An empty Cookie marks the
+// last page.
+type SearchPage struct {
+	Entries []Entry
+	Cookie  []byte
+}
+
+// Searcher runs paged LDAP searches.
+type Searcher interface {
+	Search(ctx context.Context, r *SearchRequest) (*SearchPage, error)
+}
+
+// Entry is a directory entry.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Values returns the values of an attribute, matching its name without
+// regard to case as LDAP does.
+func (e *Entry) Values(name string) []string {
+	if v, ok := e.Attributes[name]; ok {
+		return v
+	}
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Value returns the first value of an attribute, or "".
+func (e *Entry) Value(name string) string {
+	if v := e.Values(name); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Rule copies an LDAP attribute into a user profile field.
+type Rule struct {
+	Attribute string
+	// Field is the profile field, e.g. "department".
+	Field string
+	// Multi keeps all values as a list instead of the first one.
+	Multi bool
+}
+
+// Active Directory defaults.
+const (
+	DefaultUserFilter  = "(&(objectClass=user)(objectCategory=person))"
+	DefaultGroupFilter = "(objectClass=group)"
+	DefaultPageSize    = 500
+)
+
+// Config describes where users and groups are and how to map them.
+type Config struct {
+	Searcher Searcher
+	// UserBaseDN and GroupBaseDN are the subtrees searched. Groups are
+	// not synced when GroupBaseDN is empty.
+	UserBaseDN  string
+	GroupBaseDN string
+	// UserFilter and GroupFilter default to DefaultUserFilter and
+	// DefaultGroupFilter.
+	UserFilter  string
+	GroupFilter string
+	// PageSize defaults to DefaultPageSize.
+	PageSize uint32
+	// IDAttribute defaults to "objectGUID"; EmailAttribute to "mail".
+	// Binary objectGUID values are converted to their string form.
+	IDAttribute    string
+	EmailAttribute string
+	Rules          []Rule
+}
+
+func (c *Config) userFilter() string     { return or(c.UserFilter, DefaultUserFilter) }
+func (c *Config) groupFilter() string    { return or(c.GroupFilter, DefaultGroupFilter) }
+func (c *Config) idAttribute() string    { return or(c.IDAttribute, "objectGUID") }
+func (c *Config) emailAttribute() string { return or(c.EmailAttribute, "mail") }
+
+func or(s, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
+
+// State is the position of incremental syncs. Persist it between runs.
+type State struct {
+	// HighestUSN is the largest uSNChanged seen. It is specific to the
+	// domain controller searched; reset it when switching controllers.
+	HighestUSN int64 `json:"highestUsn"`
+}
+
+// Syncer syncs a directory into one organization.
+type Syncer struct {
+	Config
+	// OrganizationID is the organization groups are synced into.
+	OrganizationID string
+	// Users applies user changes. Its ExternalIDField names the profile
+	// field the directory ID is stored in, e.g. "ldapId". Its Scope is
+	// required for Full, which deactivates the users in Scope that the
+	// directory does not list, and should match only directory users,
+	// e.g. Field("profile.ldapId").Exists(). Users is not modified.
+	Users  *authvital.UserSync
+	Groups *authvital.GroupsService
+}
+
+// Report describes a sync run.
+type Report struct {
+	Users *authvital.SyncReport
+	// Groups is the number of groups synced.
+	Groups int
+	State  State
+}
+
+// Full syncs all users, deactivating users no longer in the directory,
+// and then all groups. It returns the State for later Incremental runs.
+// Users.Scope must be set.
+func (s *Syncer) Full(ctx context.Context) (*Report, error) {
+	return s.run(ctx, State{}, false)
+}
+
+// Incremental syncs users changed since st, without deactivating missing
+// ones; deletions are only noticed by Full, so run one periodically.
+// Groups are synced only by Full, since resolving member DNs needs every
+// user.
+func (s *Syncer) Incremental(ctx context.Context, st State) (*Report, error) {
+	return s.run(ctx, st, true)
+}
+
+func (s *Syncer) run(ctx context.Context, st State, incremental bool) (*Report, error) {
+	if s.Users.ExternalIDField == "" {
+		return nil, errors.New("ldapsync: Users.ExternalIDField must be set")
+	}
+	if !incremental && s.Users.Scope == "" {
+		// Without a scope every user in the tenant would be compared with
+		// the directory, and deactivated if it is not in it.
+		return nil, errors.New("ldapsync: Users.Scope must be set for a full sync")
+	}
+	filter := s.userFilter()
+	if incremental {
+		filter = fmt.Sprintf("(&%s(uSNChanged>=%d))", filter, st.HighestUSN+1)
+	}
+	attrs := []string{s.idAttribute(), s.emailAttribute(), "userAccountControl", "uSNChanged"}
+	for _, r := range s.Rules {
+		attrs = append(attrs, r.Attribute)
+	}
+	rep := &Report{State: st}
+	var desired []authvital.DesiredUser
+	ids := map[string]string{} // DN, lower case, to external ID
+	err := s.search(ctx, s.UserBaseDN, filter, attrs, func(e *Entry) {
+		d := s.mapUser(e)
+		if d.ExternalID == "" {
+			return
+		}
+		desired = append(desired, d)
+		ids[strings.ToLower(e.DN)] = d.ExternalID
+		if usn, err := strconv.ParseInt(e.Value("uSNChanged"), 10, 64); err == nil && usn > rep.State.HighestUSN {
+			rep.State.HighestUSN = usn
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := *s.Users
+	users.Partial = incremental
+	if rep.Users, err = users.Run(ctx, authvital.SliceUsers(desired)); err != nil {
+		return rep, err
+	}
+	if incremental || s.GroupBaseDN == "" || s.Groups == nil {
+		return rep, nil
+	}
+	var specs []authvital.GroupSpec
+	err = s.search(ctx, s.GroupBaseDN, s.groupFilter(), []string{"cn", "description", "member"}, func(e *Entry) {
+		g := authvital.GroupSpec{Name: e.Value("cn"), Slug: authvital.RoleSlug(e.Value("cn")), Description: e.Value("description")}
+		for _, dn := range e.Values("member") {
+			if id, ok := ids[strings.ToLower(dn)]; ok {
+				g.Members = append(g.Members, id)
+			}
+		}
+		specs = append(specs, g)
+	})
+	if err != nil {
+		return rep, err
+	}
+	for start := 0; start < len(specs); start += authvital.MaxBulkChanges {
+		batch := specs[start:min(start+authvital.MaxBulkChanges, len(specs))]
+		if _, err := s.Groups.BulkSync(ctx, s.OrganizationID, batch, s.Users.ExternalIDField); err != nil {
+			return rep, err
+		}
+		rep.Groups += len(batch)
+	}
+	return rep, nil
+}
+
+// search pages through a subtree search, calling fn for every entry.
+func (s *Syncer) search(ctx context.Context, base, filter string, attrs []string, fn func(*Entry)) error {
+	size := s.PageSize
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	req := &SearchRequest{BaseDN: base, Filter: filter, Attributes: attrs, PageSize: size}
+	for {
+		page, err := s.Searcher.Search(ctx, req)
+		if err != nil {
+			return fmt.Errorf("ldapsync: search %s: %w", base, err)
+		}
+		for i := range page.Entries {
+			fn(&page.Entries[i])
+		}
+		if len(page.Cookie) == 0 {
+			return nil
+		}
+		req.Cookie = page.Cookie
+	}
+}
+
+// uacDisabled is the ACCOUNTDISABLE flag of userAccountControl.
+const uacDisabled = 0x2
+
+func (s *Syncer) mapUser(e *Entry) authvital.DesiredUser {
+	id := e.Value(s.idAttribute())
+	if strings.EqualFold(s.idAttribute(), "objectGUID") && len(id) == 16 {
+		id = formatGUID([]byte(id))
+	}
+	d := authvital.DesiredUser{
+		ExternalID: id,
+		Email:      e.Value(s.emailAttribute()),
+		Profile:    map[string]any{},
+	}
+	if uac, err := strconv.ParseInt(e.Value("userAccountControl"), 10, 64); err == nil {
+		d.Disabled = uac&uacDisabled != 0
+	}
+	for _, r := range s.Rules {
+		vals := e.Values(r.Attribute)
+		switch {
+		case len(vals) == 0:
+		case r.Multi:
+			d.Profile[r.Field] = vals
+		default:
+			d.Profile[r.Field] = vals[0]
+		}
+	}
+	return d
+}
+
+// formatGUID formats a binary objectGUID, whose first three fields are
+// little-endian, in its usual string form.
+func formatGUID(b []byte) string {
+	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%x-%x",
+		b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6], b[8:10], b[10:])
+}
@@ -0,0 +1,116 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LifecycleAction is what a lifecycle policy does to the users it selects.
+type LifecycleAction string
+
+const (
+	// LifecycleDeactivate deactivates users who have not signed in for
+	// After.
+	LifecycleDeactivate LifecycleAction = "deactivate"
+	// LifecycleDelete deletes users that have been deactivated for After.
+	LifecycleDelete LifecycleAction = "delete"
+	// LifecycleRotatePassword requires users whose password is older than
+	// After to change it at their next sign-in.
+	LifecycleRotatePassword LifecycleAction = "rotate_password"
+)
+
+// LifecyclePolicy applies Action to matching users on a daily schedule.
+type LifecyclePolicy struct {
+	ID     string          `json:"id,omitempty"`
+	Name   string          `json:"name"`
+	Action LifecycleAction `json:"action"`
+	// After is the inactivity, deactivation or password age that
+	// qualifies a user; at least one day.
+	After Seconds `json:"after"`
+	// Query narrows the policy to a cohort, e.g. users in a role. Empty
+	// applies it to all users.
+	Query Query `json:"query,omitempty"`
+	// Exclude lists users the policy never touches, such as break-glass
+	// admins.
+	Exclude []string `json:"exclude,omitempty"`
+	// NotifyBefore warns users by email this long before the action.
+	NotifyBefore Seconds `json:"notifyBefore,omitempty"`
+	// Enabled policies run; new policies start disabled so they can be
+	// previewed first.
+	Enabled   bool      `json:"enabled"`
+	LastRunAt time.Time `json:"lastRunAt,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
+
+// Validate checks the policy before a request is made.
+func (p *LifecyclePolicy) Validate() error {
+	var errs []error
+	switch p.Action {
+	case LifecycleDeactivate, LifecycleDelete, LifecycleRotatePassword:
+	default:
+		errs = append(errs, fmt.Errorf("unknown action %q", p.Action))
+	}
+	if p.After < Seconds(24*time.Hour) {
+		errs = append(errs, errors.New("after must be at least one day"))
+	}
+	if p.NotifyBefore < 0 || p.NotifyBefore > 0 && p.NotifyBefore >= p.After {
+		errs = append(errs, errors.New("notifyBefore must be shorter than after"))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("authvital: lifecycle policy %q: %w", p.Name, errors.Join(errs...))
+}
+
+// LifecyclePreview lists the users a policy would act on if it ran now.
+type LifecyclePreview struct {
+	// Total counts all affected users; Users is the first page.
+	Total int64       `json:"total"`
+	Users *List[User] `json:"users"`
+}
+
+// LifecyclePoliciesService manages scheduled user lifecycle policies.
+type LifecyclePoliciesService struct{}
+
+// List lists lifecycle policies.
+func (s *LifecyclePoliciesService) List(ctx context.Context) ([]LifecyclePolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get returns a lifecycle policy.
+func (s *LifecyclePoliciesService) Get(ctx context.Context, id string) (*LifecyclePolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create adds a policy. Enabled is ignored: policies are created disabled
+// and switched on with Enable after a Preview.
+func (s *LifecyclePoliciesService) Create(ctx context.Context, p *LifecyclePolicy) (*LifecyclePolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces a policy, keeping its enabled state.
+func (s *LifecyclePoliciesService) Update(ctx context.Context, id string, p *LifecyclePolicy) (*LifecyclePolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete removes a policy.
+func (s *LifecyclePoliciesService) Delete(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// Preview returns the users the policy would act on if it ran now.
+func (s *LifecyclePoliciesService) Preview(ctx context.Context, id string, opts *ListOptions) (*LifecyclePreview, error) {
+	return nil, ErrNotImplemented
+}
+
+// Enable starts running the policy from its next daily run.
+func (s *LifecyclePoliciesService) Enable(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// Disable stops running the policy. Notified users are not acted on.
+func (s *LifecyclePoliciesService) Disable(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,195 @@
+package authvital
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry is one authentication log entry.
+type LogEntry struct {
+	ID string `json:"id"`
+	// Type is the kind of entry, e.g. "login.succeeded", "login.failed",
+	// "mfa.challenged" or "token.refreshed".
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	TenantID string    `json:"tenantId,omitempty"`
+	UserID   string    `json:"userId,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	ClientID string    `json:"clientId,omitempty"`
+	// Connection is the SSO connection or sign-in method used.
+	Connection string `json:"connection,omitempty"`
+	IPAddress  string `json:"ipAddress,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	Country    string `json:"country,omitempty"`
+	// Reason explains failures, e.g. "invalid_password" or "mfa_required".
+	Reason   string          `json:"reason,omitempty"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// Failed reports whether the entry records a failure.
+func (e *LogEntry) Failed() bool {
+	return strings.HasSuffix(e.Type, ".failed") || strings.HasSuffix(e.Type, ".blocked")
+}
+
+// LogFilter selects log entries. It is applied by the server, so a narrow
+// filter keeps a tail cheap. Empty fields match anything.
+type LogFilter struct {
+	UserID    string
+	Email     string
+	TenantID  string
+	ClientID  string
+	IPAddress string
+	// Types matches entry types exactly, or by prefix when they end in ".",
+	// e.g. "login.".
+	Types []string
+	// FailuresOnly drops successful entries.
+	FailuresOnly bool
+}
+
+func (f *LogFilter) values() url.Values {
+	v := url.Values{}
+	if f == nil {
+		return v
+	}
+	for k, s := range map[string]string{"userId": f.UserID, "email": f.Email, "tenantId": f.TenantID, "clientId": f.ClientID, "ipAddress": f.IPAddress} {
+		if s != "" {
+			v.Set(k, s)
+		}
+	}
+	if len(f.Types) > 0 {
+		v.Set("types", strings.Join(f.Types, ","))
+	}
+	if f.FailuresOnly {
+		v.Set("failuresOnly", "true")
+	}
+	return v
+}
+
+// LogTailIdleTimeout is how long Tail waits without receiving anything,
+// including the server's keepalives, before reconnecting.
+const LogTailIdleTimeout = time.Minute
+
+// LogsService reads authentication logs.
+type LogsService struct{}
+
+// stream opens the server-sent event stream of entries matching query,
+// resuming after lastID when it is set.
+func (s *LogsService) stream(ctx context.Context, query url.Values, lastID string) (io.ReadCloser, error) {
+	return nil, ErrNotImplemented
+}
+
+// Tail calls fn for log entries matching filter as they happen, until ctx
+// is done or fn returns an error. Entries are streamed as server-sent
+// events. When the stream drops or stalls, Tail reconnects with backoff
+// and resumes after the last entry it delivered, so fn sees no gaps or
+// duplicates across reconnections.
+//
+// Tail returns ctx.Err(), fn's error, or an error for an entry it cannot
+// decode.
+func (s *LogsService) Tail(ctx context.Context, filter *LogFilter, fn func(*LogEntry) error) error {
+	query := filter.values()
+	var lastID string
+	retry, backoff := time.Second, time.Second
+	for {
+		delivered, err := s.tailOnce(ctx, query, &lastID, &retry, fn)
+		var fnErr *tailStopError
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &fnErr):
+			return fnErr.err
+		case errors.Is(err, ErrNotImplemented):
+			return err
+		}
+		if delivered {
+			backoff = retry
+		}
+		if err := sleepCtx(ctx, backoff); err != nil {
+			return err
+		}
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// tailStopError marks an error that ends Tail instead of reconnecting.
+type tailStopError struct{ err error }
+
+func (e *tailStopError) Error() string { return e.err.Error() }
+
+// tailOnce reads one connection's events, reporting whether any were
+// delivered. The server's retry field sets the delay before reconnecting.
+func (s *LogsService) tailOnce(ctx context.Context, query url.Values, lastID *string, retry *time.Duration, fn func(*LogEntry) error) (delivered bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	body, err := s.stream(ctx, query, *lastID)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+	idle := time.AfterFunc(LogTailIdleTimeout, cancel)
+	defer idle.Stop()
+
+	r := bufio.NewReader(body)
+	var id, event string
+	var data strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return delivered, err
+		}
+		idle.Reset(LogTailIdleTimeout)
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			// A blank line dispatches the event.
+			if data.Len() > 0 && (event == "" || event == "log") {
+				var e LogEntry
+				if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+					return delivered, &tailStopError{fmt.Errorf("authvital: log event %s: %w", id, err)}
+				}
+				if err := fn(&e); err != nil {
+					return delivered, &tailStopError{err}
+				}
+				delivered = true
+			}
+			if id != "" {
+				*lastID = id
+			}
+			id, event = "", ""
+			data.Reset()
+			continue
+		}
+		if line[0] == ':' {
+			continue // keepalive comment
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "event":
+			event = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				*retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package authvital
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MappingError reports an invalid mapping expression.
+type MappingError struct {
+	Expr string
+	// Pos is the byte offset of the problem in Expr.
+	Pos int
+	Msg string
+}
+
+func (e *MappingError) Error() string {
+	return fmt.Sprintf("authvital: mapping expression %q at offset %d: %s", e.Expr, e.Pos, e.Msg)
+}
+
+// MappingType is the type of a mapping expression's value.
+type MappingType int
+
+const (
+	MappingString MappingType = iota
+	MappingList
+)
+
+func (t MappingType) String() string {
+	if t == MappingList {
+		return "list"
+	}
+	return "string"
+}
+
+// mappingFunc describes a built-in function: its parameter types, with
+// variadic repeating the last one, and its result.
+type mappingFunc struct {
+	params   []MappingType
+	variadic bool
+	result   MappingType
+	eval     func(args []mappingValue) mappingValue
+}
+
+type mappingValue struct {
+	s string
+	l []string
+}
+
+func strFunc(f func(string) string) mappingFunc {
+	return mappingFunc{params: []MappingType{MappingString}, result: MappingString,
+		eval: func(a []mappingValue) mappingValue { return mappingValue{s: f(a[0].s)} }}
+}
+
+// mappingFuncs are the functions available in mapping expressions. attr
+// and attrs are handled by the parser, since they take a literal name.
+var mappingFuncs = map[string]mappingFunc{
+	"upper": strFunc(strings.ToUpper),
+	"lower": strFunc(strings.ToLower),
+	"trim":  strFunc(strings.TrimSpace),
+	"domain": strFunc(func(s string) string {
+		if i := strings.LastIndexByte(s, '@'); i >= 0 {
+			return strings.ToLower(s[i+1:])
+		}
+		return ""
+	}),
+	"concat": {params: []MappingType{MappingString}, variadic: true, result: MappingString,
+		eval: func(a []mappingValue) mappingValue {
+			var b strings.Builder
+			for _, v := range a {
+				b.WriteString(v.s)
+			}
+			return mappingValue{s: b.String()}
+		}},
+	"coalesce": {params: []MappingType{MappingString}, variadic: true, result: MappingString,
+		eval: func(a []mappingValue) mappingValue {
+			for _, v := range a {
+				if v.s != "" {
+					return v
+				}
+			}
+			return mappingValue{}
+		}},
+	"replace": {params: []MappingType{MappingString, MappingString, MappingString}, result: MappingString,
+		eval: func(a []mappingValue) mappingValue {
+			return mappingValue{s: strings.ReplaceAll(a[0].s, a[1].s, a[2].s)}
+		}},
+	"split": {params: []MappingType{MappingString, MappingString}, result: MappingList,
+		eval: func(a []mappingValue) mappingValue {
+			if a[0].s == "" {
+				return mappingValue{}
+			}
+			parts := strings.Split(a[0].s, a[1].s)
+			for i := range parts {
+				parts[i] = strings.TrimSpace(parts[i])
+			}
+			return mappingValue{l: parts}
+		}},
+	"join": {params: []MappingType{MappingList, MappingString}, result: MappingString,
+		eval: func(a []mappingValue) mappingValue { return mappingValue{s: strings.Join(a[0].l, a[1].s)} }},
+	"first": {params: []MappingType{MappingList}, result: MappingString,
+		eval: func(a []mappingValue) mappingValue {
+			if len(a[0].l) == 0 {
+				return mappingValue{}
+			}
+			return mappingValue{s: a[0].l[0]}
+		}},
+}
+
+// mappingNode is a compiled expression node.
+type mappingNode struct {
+	lit  *string // string literal
+	attr string  // attr or attrs
+	fn   *mappingFunc
+	args []*mappingNode
+	typ  MappingType
+}
+
+func (n *mappingNode) eval(attrs map[string][]string) mappingValue {
+	switch {
+	case n.lit != nil:
+		return mappingValue{s: *n.lit}
+	case n.fn == nil && n.typ == MappingList:
+		return mappingValue{l: attrs[n.attr]}
+	case n.fn == nil:
+		if v := attrs[n.attr]; len(v) > 0 {
+			return mappingValue{s: v[0]}
+		}
+		return mappingValue{}
+	}
+	args := make([]mappingValue, len(n.args))
+	for i, a := range n.args {
+		args[i] = a.eval(attrs)
+	}
+	return n.fn.eval(args)
+}
+
+// MappingExpr is a compiled attribute mapping expression, such as
+//
+//	upper(attr("department"))
+//	coalesce(attr("displayName"), concat(attr("givenName"), " ", attr("sn")))
+//	split(attr("groups"), ";")
+//
+// Expressions are built from string literals, attr(name) for an
+// attribute's first value, attrs(name) for all of them, and the functions
+// upper, lower, trim, domain, concat, coalesce, replace, split, join and
+// first, with calls nested at most 32 deep. Types are checked when
+// compiling, so a compiled expression always evaluates. The server
+// evaluates the same language, so an expression can be tested with Eval
+// before it is saved.
+type MappingExpr struct {
+	src   string
+	root  *mappingNode
+	attrs []string
+}
+
+// CompileMapping parses and type-checks a mapping expression.
+func CompileMapping(expr string) (*MappingExpr, error) {
+	p := &mappingParser{src: expr}
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(expr) {
+		return nil, p.errorf("unexpected %q after expression", expr[p.pos:])
+	}
+	names := make([]string, 0, len(p.attrs))
+	for a := range p.attrs {
+		names = append(names, a)
+	}
+	sort.Strings(names)
+	return &MappingExpr{src: expr, root: root, attrs: names}, nil
+}
+
+// MustCompileMapping is like CompileMapping but panics on error. It is
+// meant for expressions in code.
+func MustCompileMapping(expr string) *MappingExpr {
+	m, err := CompileMapping(expr)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// String returns the source expression.
+func (m *MappingExpr) String() string { return m.src }
+
+// Type returns the type of the expression's value.
+func (m *MappingExpr) Type() MappingType { return m.root.typ }
+
+// Attributes returns the attribute names the expression reads.
+func (m *MappingExpr) Attributes() []string { return m.attrs }
+
+// Eval evaluates the expression against attrs. A string result is
+// returned as a one-element list, or an empty one when it is "".
+func (m *MappingExpr) Eval(attrs map[string][]string) []string {
+	v := m.root.eval(attrs)
+	if m.root.typ == MappingList {
+		return v.l
+	}
+	if v.s == "" {
+		return nil
+	}
+	return []string{v.s}
+}
+
+// EvalString evaluates the expression and returns its value as a string;
+// lists are joined with commas.
+func (m *MappingExpr) EvalString(attrs map[string][]string) string {
+	v := m.root.eval(attrs)
+	if m.root.typ == MappingList {
+		return strings.Join(v.l, ",")
+	}
+	return v.s
+}
+
+// maxMappingDepth bounds how deeply calls may nest, so an expression taken
+// from configuration cannot exhaust the stack when compiled or evaluated.
+const maxMappingDepth = 32
+
+type mappingParser struct {
+	src   string
+	pos   int
+	depth int
+	attrs map[string]bool
+}
+
+func (p *mappingParser) errorf(format string, args ...any) error {
+	return &MappingError{Expr: p.src, Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *mappingParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *mappingParser) expr() (*mappingNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("expected an expression")
+	}
+	if p.src[p.pos] == '"' {
+		s, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		return &mappingNode{lit: &s, typ: MappingString}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.src) && (p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z' || p.src[p.pos] == '_') {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:p.pos+1])
+	}
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '(' {
+		return nil, p.errorf("expected ( after %s", name)
+	}
+	p.pos++
+	if name == "attr" || name == "attrs" {
+		return p.attrCall(name)
+	}
+	if p.depth >= maxMappingDepth {
+		p.pos = start
+		return nil, p.errorf("calls nested more than %d deep", maxMappingDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	fn, ok := mappingFuncs[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown function %s", name)
+	}
+	var args []*mappingNode
+	for {
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ')' && len(args) == 0 {
+			break
+		}
+		argPos := p.pos
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		want := fn.params[min(len(args), len(fn.params)-1)]
+		if len(args) >= len(fn.params) && !fn.variadic {
+			p.pos = argPos
+			return nil, p.errorf("%s takes %s", name, plural(len(fn.params), "argument"))
+		}
+		if arg.typ != want {
+			p.pos = argPos
+			hint := ""
+			if arg.typ == MappingList {
+				hint = "; use first or join"
+			}
+			return nil, p.errorf("%s argument %d is a %s, want a %s%s", name, len(args)+1, arg.typ, want, hint)
+		}
+		args = append(args, arg)
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != ')' {
+		return nil, p.errorf("expected , or ) in call to %s", name)
+	}
+	p.pos++
+	if len(args) < len(fn.params) {
+		return nil, p.errorf("%s takes %s, got %d", name, plural(len(fn.params), "argument"), len(args))
+	}
+	return &mappingNode{fn: &fn, args: args, typ: fn.result}, nil
+}
+
+// attrCall parses the literal name argument of attr or attrs.
+func (p *mappingParser) attrCall(name string) (*mappingNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+		return nil, p.errorf("%s takes a quoted attribute name", name)
+	}
+	attr, err := p.str()
+	if err != nil {
+		return nil, err
+	}
+	if attr == "" {
+		return nil, p.errorf("empty attribute name")
+	}
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != ')' {
+		return nil, p.errorf("%s takes one argument", name)
+	}
+	p.pos++
+	if p.attrs == nil {
+		p.attrs = map[string]bool{}
+	}
+	p.attrs[attr] = true
+	n := &mappingNode{attr: attr, typ: MappingString}
+	if name == "attrs" {
+		n.typ = MappingList
+	}
+	return n, nil
+}
+
+// str parses a double-quoted string literal with Go escapes.
+func (p *mappingParser) str() (string, error) {
+	start := p.pos
+	for i := p.pos + 1; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			i++
+		case '"':
+			s, err := strconv.Unquote(p.src[start : i+1])
+			if err != nil {
+				return "", p.errorf("invalid string literal")
+			}
+			p.pos = i + 1
+			return s, nil
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
@@ -0,0 +1,117 @@
+package authvital
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMappingEval(t *testing.T) {
+	attrs := map[string][]string{
+		"mail":       {"Jane.Doe@Example.COM"},
+		"givenName":  {"Jane"},
+		"sn":         {"Doe"},
+		"department": {" engineering "},
+		"groups":     {"admins", "billing"},
+		"roles":      {"a; b ;c"},
+	}
+	for _, tc := range []struct {
+		expr  string
+		typ   MappingType
+		want  []string
+		attrs []string
+	}{
+		{`"fixed"`, MappingString, []string{"fixed"}, []string{}},
+		{`attr("givenName")`, MappingString, []string{"Jane"}, []string{"givenName"}},
+		{`attr("missing")`, MappingString, nil, []string{"missing"}},
+		{`attrs("groups")`, MappingList, []string{"admins", "billing"}, []string{"groups"}},
+		{`upper(trim(attr("department")))`, MappingString, []string{"ENGINEERING"}, []string{"department"}},
+		{`lower(attr("mail"))`, MappingString, []string{"jane.doe@example.com"}, []string{"mail"}},
+		{`domain(attr("mail"))`, MappingString, []string{"example.com"}, []string{"mail"}},
+		{`concat(attr("givenName"), " ", attr("sn"))`, MappingString, []string{"Jane Doe"}, []string{"givenName", "sn"}},
+		{`coalesce(attr("displayName"), attr("givenName"))`, MappingString, []string{"Jane"}, []string{"displayName", "givenName"}},
+		{`coalesce(attr("x"), attr("y"))`, MappingString, nil, []string{"x", "y"}},
+		{`replace(attr("sn"), "o", "0")`, MappingString, []string{"D0e"}, []string{"sn"}},
+		{`split(attr("roles"), ";")`, MappingList, []string{"a", "b", "c"}, []string{"roles"}},
+		{`split(attr("missing"), ";")`, MappingList, nil, []string{"missing"}},
+		{`join(attrs("groups"), "|")`, MappingString, []string{"admins|billing"}, []string{"groups"}},
+		{`first(attrs("groups"))`, MappingString, []string{"admins"}, []string{"groups"}},
+		{`first(attrs("missing"))`, MappingString, nil, []string{"missing"}},
+		{` upper ( "a\tb" ) `, MappingString, []string{"A\tB"}, []string{}},
+	} {
+		m, err := CompileMapping(tc.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tc.expr, err)
+			continue
+		}
+		if m.Type() != tc.typ {
+			t.Errorf("%s: type %v, want %v", tc.expr, m.Type(), tc.typ)
+		}
+		if got := m.Eval(attrs); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: Eval = %q, want %q", tc.expr, got, tc.want)
+		}
+		if got := m.Attributes(); !reflect.DeepEqual(got, tc.attrs) {
+			t.Errorf("%s: Attributes = %q, want %q", tc.expr, got, tc.attrs)
+		}
+	}
+}
+
+func TestMappingEvalString(t *testing.T) {
+	m := MustCompileMapping(`attrs("groups")`)
+	if got := m.EvalString(map[string][]string{"groups": {"a", "b"}}); got != "a,b" {
+		t.Fatalf("EvalString = %q, want a,b", got)
+	}
+}
+
+func TestMappingCompileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{``, 0, "expected an expression"},
+		{`upper`, 5, "expected ( after upper"},
+		{`nope("x")`, 0, "unknown function nope"},
+		{`upper("a", "b")`, 11, "upper takes 1 argument"},
+		{`replace("a", "b")`, 17, "replace takes 3 arguments, got 2"},
+		{`upper(attrs("g"))`, 6, "want a string; use first or join"},
+		{`join(attr("g"), ",")`, 5, "is a string, want a list"},
+		{`attr(name)`, 5, "quoted attribute name"},
+		{`attr("")`, 7, "empty attribute name"},
+		{`attr("a", "b")`, 8, "attr takes one argument"},
+		{`"unterminated`, 0, "unterminated string"},
+		{`"bad \q"`, 0, "invalid string literal"},
+		{`upper("a"`, 9, "expected , or ) in call to upper"},
+		{`"a" "b"`, 4, "after expression"},
+		{`Upper("a")`, 0, "unexpected"},
+	} {
+		_, err := CompileMapping(tc.expr)
+		var me *MappingError
+		if !errors.As(err, &me) {
+			t.Errorf("%s: err = %v, want a MappingError", tc.expr, err)
+			continue
+		}
+		if me.Pos != tc.pos || !strings.Contains(me.Msg, tc.msg) {
+			t.Errorf("%s: error at %d %q, want %d containing %q", tc.expr, me.Pos, me.Msg, tc.pos, tc.msg)
+		}
+	}
+}
+
+func TestMappingDepthLimit(t *testing.T) {
+	nest := func(n int) string {
+		return strings.Repeat("trim(", n) + `attr("a")` + strings.Repeat(")", n)
+	}
+	if _, err := CompileMapping(nest(maxMappingDepth)); err != nil {
+		t.Fatalf("depth %d: %v", maxMappingDepth, err)
+	}
+	_, err := CompileMapping(nest(maxMappingDepth + 1))
+	var me *MappingError
+	if !errors.As(err, &me) || !strings.Contains(me.Msg, "nested") {
+		t.Fatalf("depth %d: err = %v, want a nesting error", maxMappingDepth+1, err)
+	}
+	// Far deeper input must fail the same way rather than overflow.
+	if _, err := CompileMapping(nest(1 << 16)); err == nil {
+		t.Fatal("deeply nested expression compiled")
+	}
+}
@@ -0,0 +1,284 @@
+package authvital
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// End-user message codes. They match the API's error codes and
+// FlowMessage.Code.
+const (
+	MsgInvalidCredentials = "invalid_credentials"
+	MsgAccountLocked      = "account_locked"
+	MsgAccountDisabled    = "account_disabled"
+	MsgLinkExpired        = "link_expired"
+	MsgLinkUsed           = "link_used"
+	MsgEmailNotVerified   = "email_not_verified"
+	MsgMFAInvalidCode     = "mfa_invalid_code"
+	MsgMFAExpired         = "mfa_expired"
+	MsgMFAPushDenied      = "mfa_push_denied"
+	MsgPasswordBreached   = "password_breached"
+	MsgTooManyAttempts    = "too_many_attempts"
+	MsgSessionExpired     = "session_expired"
+	// MsgGeneric is used for codes without a message, so internal details
+	// never reach end users.
+	MsgGeneric = "generic"
+)
+
+// defaultMessages is the built-in translation bundle.
+var defaultMessages = map[string]map[string]string{
+	"en": {
+		MsgInvalidCredentials: "The email or password is incorrect.",
+		MsgAccountLocked:      "Your account is temporarily locked after too many attempts. Try again later or reset your password.",
+		MsgAccountDisabled:    "Your account has been disabled. Contact your administrator.",
+		MsgLinkExpired:        "This link has expired. Request a new one.",
+		MsgLinkUsed:           "This link has already been used. Request a new one.",
+		MsgEmailNotVerified:   "Verify your email address to continue. Check your inbox for the link.",
+		MsgMFAInvalidCode:     "The code is incorrect. Check it and try again.",
+		MsgMFAExpired:         "The verification took too long. Sign in again.",
+		MsgMFAPushDenied:      "The sign-in was denied on your device.",
+		MsgPasswordBreached:   "This password has appeared in a data breach. Choose a different one.",
+		MsgTooManyAttempts:    "Too many attempts. Wait a moment and try again.",
+		MsgSessionExpired:     "Your session has expired. Sign in again.",
+		MsgGeneric:            "Something went wrong. Please try again.",
+	},
+	"es": {
+		MsgInvalidCredentials: "El correo electrónico o la contraseña no son correctos.",
+		MsgAccountLocked:      "Tu cuenta está bloqueada temporalmente por demasiados intentos. Inténtalo más tarde o restablece tu contraseña.",
+		MsgAccountDisabled:    "Tu cuenta ha sido desactivada. Contacta con tu administrador.",
+		MsgLinkExpired:        "Este enlace ha caducado. Solicita uno nuevo.",
+		MsgLinkUsed:           "Este enlace ya se ha utilizado. Solicita uno nuevo.",
+		MsgEmailNotVerified:   "Verifica tu dirección de correo electrónico para continuar. Busca el enlace en tu bandeja de entrada.",
+		MsgMFAInvalidCode:     "El código no es correcto. Revísalo e inténtalo de nuevo.",
+		MsgMFAExpired:         "La verificación ha tardado demasiado. Vuelve a iniciar sesión.",
+		MsgMFAPushDenied:      "El inicio de sesión se rechazó en tu dispositivo.",
+		MsgPasswordBreached:   "Esta contraseña ha aparecido en una filtración de datos. Elige otra.",
+		MsgTooManyAttempts:    "Demasiados intentos. Espera un momento e inténtalo de nuevo.",
+		MsgSessionExpired:     "Tu sesión ha caducado. Vuelve a iniciar sesión.",
+		MsgGeneric:            "Algo salió mal. Inténtalo de nuevo.",
+	},
+	"fr": {
+		MsgInvalidCredentials: "L'adresse e-mail ou le mot de passe est incorrect.",
+		MsgAccountLocked:      "Votre compte est temporairement verrouillé après trop de tentatives. Réessayez plus tard ou réinitialisez votre mot de passe.",
+		MsgAccountDisabled:    "Votre compte a été désactivé. Contactez votre administrateur.",
+		MsgLinkExpired:        "Ce lien a expiré. Demandez-en un nouveau.",
+		MsgLinkUsed:           "Ce lien a déjà été utilisé. Demandez-en un nouveau.",
+		MsgEmailNotVerified:   "Vérifiez votre adresse e-mail pour continuer. Le lien se trouve dans votre boîte de réception.",
+		MsgMFAInvalidCode:     "Le code est incorrect. Vérifiez-le et réessayez.",
+		MsgMFAExpired:         "La vérification a pris trop de temps. Reconnectez-vous.",
+		MsgMFAPushDenied:      "La connexion a été refusée sur votre appareil.",
+		MsgPasswordBreached:   "Ce mot de passe figure dans une fuite de données. Choisissez-en un autre.",
+		MsgTooManyAttempts:    "Trop de tentatives. Patientez un instant et réessayez.",
+		MsgSessionExpired:     "Votre session a expiré. Reconnectez-vous.",
+		MsgGeneric:            "Une erreur s'est produite. Veuillez réessayer.",
+	},
+	"de": {
+		MsgInvalidCredentials: "E-Mail-Adresse oder Passwort ist falsch.",
+		MsgAccountLocked:      "Ihr Konto ist nach zu vielen Versuchen vorübergehend gesperrt. Versuchen Sie es später erneut oder setzen Sie Ihr Passwort zurück.",
+		MsgAccountDisabled:    "Ihr Konto wurde deaktiviert. Wenden Sie sich an Ihren Administrator.",
+		MsgLinkExpired:        "Dieser Link ist abgelaufen. Fordern Sie einen neuen an.",
+		MsgLinkUsed:           "Dieser Link wurde bereits verwendet. Fordern Sie einen neuen an.",
+		MsgEmailNotVerified:   "Bestätigen Sie Ihre E-Mail-Adresse, um fortzufahren. Den Link finden Sie in Ihrem Posteingang.",
+		MsgMFAInvalidCode:     "Der Code ist falsch. Prüfen Sie ihn und versuchen Sie es erneut.",
+		MsgMFAExpired:         "Die Bestätigung hat zu lange gedauert. Melden Sie sich erneut an.",
+		MsgMFAPushDenied:      "Die Anmeldung wurde auf Ihrem Gerät abgelehnt.",
+		MsgPasswordBreached:   "Dieses Passwort ist in einem Datenleck aufgetaucht. Wählen Sie ein anderes.",
+		MsgTooManyAttempts:    "Zu viele Versuche. Warten Sie einen Moment und versuchen Sie es erneut.",
+		MsgSessionExpired:     "Ihre Sitzung ist abgelaufen. Melden Sie sich erneut an.",
+		MsgGeneric:            "Etwas ist schiefgelaufen. Bitte versuchen Sie es erneut.",
+	},
+}
+
+// MessageCatalog maps message codes to localized, end-user-safe text, so
+// UIs need no error-mapping tables of their own. The zero value uses the
+// built-in bundle; DefaultMessages is shared.
+type MessageCatalog struct {
+	// Override, when set, is consulted first for every lookup. Returning
+	// false falls back to the bundles.
+	Override func(code, lang string) (string, bool)
+
+	mu      sync.RWMutex
+	bundles map[string]map[string]string
+}
+
+// DefaultMessages is the catalog used by Message and MessageFor.
+var DefaultMessages = &MessageCatalog{}
+
+// Add merges a translation bundle for lang, a BCP 47 tag such as "pt-BR",
+// replacing built-in messages with the same codes.
+func (c *MessageCatalog) Add(lang string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.bundles == nil {
+		c.bundles = map[string]map[string]string{}
+	}
+	lang = strings.ToLower(lang)
+	b := c.bundles[lang]
+	if b == nil {
+		b = map[string]string{}
+		c.bundles[lang] = b
+	}
+	for k, v := range messages {
+		b[k] = v
+	}
+}
+
+// Languages returns the languages with a bundle, sorted.
+func (c *MessageCatalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	seen := map[string]bool{}
+	for l := range defaultMessages {
+		seen[l] = true
+	}
+	for l := range c.bundles {
+		seen[l] = true
+	}
+	out := make([]string, 0, len(seen))
+	for l := range seen {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Message returns the text for code in the first of langs that has it,
+// trying each tag and then its base language ("pt-BR", then "pt"), then
+// English. Unknown codes get the MsgGeneric text.
+func (c *MessageCatalog) Message(code string, langs ...string) string {
+	for _, try := range []string{code, MsgGeneric} {
+		for _, lang := range append(candidates(langs), "en") {
+			if s, ok := c.lookup(try, lang); ok {
+				return s
+			}
+		}
+	}
+	return defaultMessages["en"][MsgGeneric]
+}
+
+// MessageFor returns the text for err; see Message and MessageCode.
+func (c *MessageCatalog) MessageFor(err error, langs ...string) string {
+	return c.Message(MessageCode(err), langs...)
+}
+
+func (c *MessageCatalog) lookup(code, lang string) (string, bool) {
+	if c.Override != nil {
+		if s, ok := c.Override(code, lang); ok {
+			return s, true
+		}
+	}
+	c.mu.RLock()
+	s, ok := c.bundles[lang][code]
+	c.mu.RUnlock()
+	if ok {
+		return s, true
+	}
+	s, ok = defaultMessages[lang][code]
+	return s, ok
+}
+
+// candidates expands tags with their base languages, lower-cased.
+func candidates(langs []string) []string {
+	out := make([]string, 0, 2*len(langs))
+	for _, l := range langs {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if l == "" {
+			continue
+		}
+		out = append(out, l)
+		if base, _, ok := strings.Cut(l, "-"); ok {
+			out = append(out, base)
+		}
+	}
+	return out
+}
+
+// Message returns DefaultMessages.Message(code, langs...).
+func Message(code string, langs ...string) string {
+	return DefaultMessages.Message(code, langs...)
+}
+
+// MessageFor returns DefaultMessages.MessageFor(err, langs...).
+func MessageFor(err error, langs ...string) string {
+	return DefaultMessages.MessageFor(err, langs...)
+}
+
+// MessageCode returns the message code for an SDK error: the API error
+// code, or a code for the SDK's own errors. Errors without one get
+// MsgGeneric.
+func MessageCode(err error) string {
+	var api *APIError
+	var rate *QuotaExceededError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrChallengeExpired), errors.Is(err, ErrFlowExpired):
+		return MsgMFAExpired
+	case errors.Is(err, ErrPushDenied):
+		return MsgMFAPushDenied
+	case errors.Is(err, ErrTokenConsumed):
+		return MsgLinkUsed
+	case errors.Is(err, ErrOneTimeExpired):
+		return MsgLinkExpired
+	case errors.Is(err, ErrPasswordBreached):
+		return MsgPasswordBreached
+	case errors.Is(err, ErrTokenExpired):
+		return MsgSessionExpired
+	case errors.As(err, &rate):
+		return MsgTooManyAttempts
+	case errors.As(err, &api):
+		if api.Code == "rate_limited" || api.StatusCode == 429 {
+			return MsgTooManyAttempts
+		}
+		return api.Code
+	}
+	return MsgGeneric
+}
+
+// AcceptLanguages parses an Accept-Language header into tags by
+// preference, for passing to Message.
+func AcceptLanguages(header string) []string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{lang, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.lang
+	}
+	return out
+}
+
+// Localize returns DefaultMessages' text for m.Code, falling back to the
+// server's Text when the catalog has no message for it.
+func (m FlowMessage) Localize(langs ...string) string {
+	for _, lang := range append(candidates(langs), "en") {
+		if s, ok := DefaultMessages.lookup(m.Code, lang); ok {
+			return s
+		}
+	}
+	if m.Text != "" {
+		return m.Text
+	}
+	return DefaultMessages.Message(MsgGeneric, langs...)
+}
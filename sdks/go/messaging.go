@@ -0,0 +1,161 @@
+package authvital
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MessageChannel is a delivery channel for OTP and verification messages.
+type MessageChannel string
+
+const (
+	ChannelEmail MessageChannel = "email"
+	ChannelSMS   MessageChannel = "sms"
+)
+
+// MessagingProviderType identifies a delivery provider.
+type MessagingProviderType string
+
+const (
+	ProviderSMTP   MessagingProviderType = "smtp"
+	ProviderSES    MessagingProviderType = "ses"
+	ProviderTwilio MessagingProviderType = "twilio"
+)
+
+// Channel returns the channel a provider type delivers to.
+func (t MessagingProviderType) Channel() MessageChannel {
+	if t == ProviderTwilio {
+		return ChannelSMS
+	}
+	return ChannelEmail
+}
+
+// MessagingProvider configures how one channel's messages are delivered.
+// Exactly the settings matching Type are used. Secrets are write-only and
+// empty when read back.
+type MessagingProvider struct {
+	Type MessagingProviderType `json:"type"`
+	// From is the sender address or phone number.
+	From   string          `json:"from"`
+	SMTP   *SMTPSettings   `json:"smtp,omitempty"`
+	SES    *SESSettings    `json:"ses,omitempty"`
+	Twilio *TwilioSettings `json:"twilio,omitempty"`
+}
+
+// SMTPSettings configure ProviderSMTP.
+type SMTPSettings struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// TLS selects implicit TLS instead of STARTTLS.
+	TLS bool `json:"tls"`
+}
+
+// SESSettings configure ProviderSES.
+type SESSettings struct {
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	// RoleARN, instead of access keys, is assumed by AuthVital to send.
+	RoleARN string `json:"roleArn,omitempty"`
+}
+
+// TwilioSettings configure ProviderTwilio.
+type TwilioSettings struct {
+	AccountSID string `json:"accountSid"`
+	AuthToken  string `json:"authToken,omitempty"`
+	// MessagingServiceSID, when set, is used instead of From.
+	MessagingServiceSID string `json:"messagingServiceSid,omitempty"`
+}
+
+// Validate checks that the settings for p.Type are present before a
+// request is made.
+func (p *MessagingProvider) Validate() error {
+	missing := false
+	switch p.Type {
+	case ProviderSMTP:
+		missing = p.SMTP == nil || p.SMTP.Host == ""
+	case ProviderSES:
+		missing = p.SES == nil || p.SES.Region == ""
+	case ProviderTwilio:
+		missing = p.Twilio == nil || p.Twilio.AccountSID == "" ||
+			p.From == "" && p.Twilio.MessagingServiceSID == ""
+	default:
+		return fmt.Errorf("authvital: unknown messaging provider %q", p.Type)
+	}
+	if missing {
+		return fmt.Errorf("authvital: incomplete %s provider settings", p.Type)
+	}
+	return nil
+}
+
+// DeliveryState is the state of a sent message.
+type DeliveryState string
+
+const (
+	DeliveryQueued    DeliveryState = "queued"
+	DeliverySent      DeliveryState = "sent"
+	DeliveryDelivered DeliveryState = "delivered"
+	DeliveryBounced   DeliveryState = "bounced"
+	DeliveryFailed    DeliveryState = "failed"
+)
+
+// Delivery is the status of one message.
+type Delivery struct {
+	ID        string         `json:"id"`
+	Channel   MessageChannel `json:"channel"`
+	Recipient string         `json:"recipient"`
+	// Kind is the message template, e.g. "otp" or "email_verification".
+	Kind  string        `json:"kind"`
+	State DeliveryState `json:"state"`
+	// Error is the provider's reason for bounced and failed messages.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DeliveryFilter narrows ListDeliveries. Zero fields match all.
+type DeliveryFilter struct {
+	Channel   MessageChannel
+	Recipient string
+	State     DeliveryState
+	Since     time.Time
+}
+
+// MessagingService configures message delivery providers and reports on
+// deliveries.
+type MessagingService struct{}
+
+// GetProvider returns the provider configured for channel.
+func (s *MessagingService) GetProvider(ctx context.Context, channel MessageChannel) (*MessagingProvider, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetProvider configures the provider for p.Type's channel, replacing the
+// current one. It is validated with Validate first.
+func (s *MessagingService) SetProvider(ctx context.Context, p *MessagingProvider) (*MessagingProvider, error) {
+	return nil, ErrNotImplemented
+}
+
+// ResetProvider reverts channel to AuthVital's built-in delivery.
+func (s *MessagingService) ResetProvider(ctx context.Context, channel MessageChannel) error {
+	return ErrNotImplemented
+}
+
+// SendTest sends a test message to recipient through the configured
+// provider and returns its delivery, so provisioning can verify settings.
+func (s *MessagingService) SendTest(ctx context.Context, channel MessageChannel, recipient string) (*Delivery, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetDelivery returns the status of a message.
+func (s *MessagingService) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListDeliveries lists recent deliveries, newest first.
+func (s *MessagingService) ListDeliveries(ctx context.Context, filter *DeliveryFilter, opts *ListOptions) (*List[Delivery], error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,289 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrChallengeExpired is returned once an MFA challenge's token has
+	// expired; the user must sign in again.
+	ErrChallengeExpired = errors.New("authvital: MFA challenge expired")
+	// ErrChallengeCompleted is returned when using a challenge that has
+	// already produced tokens.
+	ErrChallengeCompleted = errors.New("authvital: MFA challenge already completed")
+	// ErrNoFactorSelected is returned by Complete before SelectFactor when
+	// the challenge offers more than one factor.
+	ErrNoFactorSelected = errors.New("authvital: no MFA factor selected")
+	// ErrPushDenied is returned when the user denies a push prompt.
+	ErrPushDenied = errors.New("authvital: push MFA prompt denied")
+	// ErrUnknownFactor is returned by SelectFactor for a factor the
+	// challenge does not offer.
+	ErrUnknownFactor = errors.New("authvital: MFA factor not offered by this challenge")
+	// ErrNotPushFactor is returned by WaitForApproval when the selected
+	// factor is not a push factor.
+	ErrNotPushFactor = errors.New("authvital: selected MFA factor is not push")
+)
+
+// FactorType is a kind of second factor.
+type FactorType string
+
+const (
+	FactorTOTP     FactorType = "totp"
+	FactorSMS      FactorType = "sms"
+	FactorEmail    FactorType = "email"
+	FactorWebAuthn FactorType = "webauthn"
+	// FactorPush sends an approve-or-deny prompt to the user's phone. See
+	// Challenge.WaitForApproval.
+	FactorPush FactorType = "push"
+	// FactorRecoveryCode is offered when the user has unused recovery
+	// codes. See Challenge.UseRecoveryCode.
+	FactorRecoveryCode FactorType = "recovery_code"
+)
+
+// Factor is an enrolled second factor offered by a challenge.
+type Factor struct {
+	ID   string     `json:"id"`
+	Type FactorType `json:"type"`
+	// Label is safe to display, such as a masked phone number.
+	Label string `json:"label,omitempty"`
+}
+
+// sendsCode reports whether selecting the factor delivers a code or prompt.
+func (f Factor) sendsCode() bool {
+	return f.Type == FactorSMS || f.Type == FactorEmail || f.Type == FactorPush
+}
+
+// ChallengeState is the progress of a Challenge.
+type ChallengeState string
+
+const (
+	// ChallengeSelectFactor means a factor must be chosen.
+	ChallengeSelectFactor ChallengeState = "select_factor"
+	// ChallengeAwaitingCode means the user must enter the code for the
+	// selected factor.
+	ChallengeAwaitingCode ChallengeState = "awaiting_code"
+	ChallengeCompleted    ChallengeState = "completed"
+	ChallengeExpired      ChallengeState = "expired"
+)
+
+// Challenge is a login paused for multi-factor authentication. It holds
+// the intermediate MFA token so callers only choose a factor and submit
+// the user's code. It is safe for concurrent use, and can be stored between
+// HTTP requests with json.Marshal and restored with Auth.ResumeChallenge.
+type Challenge struct {
+	MFAToken  string    `json:"mfaToken"`
+	Factors   []Factor  `json:"factors"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// SelectedFactor is the ID of the chosen factor.
+	SelectedFactor string `json:"selectedFactor,omitempty"`
+	Done           bool   `json:"done,omitempty"`
+
+	mu   sync.Mutex
+	auth *AuthService
+}
+
+// ResumeChallenge restores a Challenge saved with json.Marshal.
+func (s *AuthService) ResumeChallenge(data []byte) (*Challenge, error) {
+	var c Challenge
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	c.auth = s
+	return &c, nil
+}
+
+// State returns the challenge's progress.
+func (c *Challenge) State() ChallengeState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state()
+}
+
+func (c *Challenge) state() ChallengeState {
+	switch {
+	case c.Done:
+		return ChallengeCompleted
+	case !c.ExpiresAt.IsZero() && c.now().After(c.ExpiresAt):
+		return ChallengeExpired
+	case c.SelectedFactor == "" && len(c.Factors) != 1:
+		return ChallengeSelectFactor
+	}
+	return ChallengeAwaitingCode
+}
+
+// now returns the time on the client's clock.
+func (c *Challenge) now() time.Time {
+	if c.auth == nil {
+		return time.Now()
+	}
+	return now(c.auth.clock)
+}
+
+func (c *Challenge) usable() error {
+	switch c.state() {
+	case ChallengeCompleted:
+		return ErrChallengeCompleted
+	case ChallengeExpired:
+		return ErrChallengeExpired
+	}
+	return nil
+}
+
+// SelectFactor chooses the factor to verify with. For SMS and email
+// factors it sends the code, and for push factors the prompt; calling it
+// again resends.
+func (c *Challenge) SelectFactor(ctx context.Context, factorID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.usable(); err != nil {
+		return err
+	}
+	f, ok := c.factor(factorID)
+	if !ok {
+		return ErrUnknownFactor
+	}
+	if f.sendsCode() {
+		if err := c.auth.sendChallenge(ctx, c.MFAToken, f.ID); err != nil {
+			return err
+		}
+	}
+	c.SelectedFactor = f.ID
+	return nil
+}
+
+// Complete verifies code against the selected factor, or the only factor
+// offered, and returns the tokens. A wrong code returns a *TokenError and
+// leaves the challenge usable for another attempt.
+func (c *Challenge) Complete(ctx context.Context, code string) (*Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.usable(); err != nil {
+		return nil, err
+	}
+	f, err := c.selected()
+	if err != nil {
+		return nil, err
+	}
+	return c.complete(ctx, f.ID, code)
+}
+
+// selected returns the selected factor, or the only factor offered.
+func (c *Challenge) selected() (Factor, error) {
+	id := c.SelectedFactor
+	if id == "" {
+		if len(c.Factors) != 1 {
+			return Factor{}, ErrNoFactorSelected
+		}
+		id = c.Factors[0].ID
+	}
+	f, ok := c.factor(id)
+	if !ok {
+		return Factor{}, ErrUnknownFactor
+	}
+	return f, nil
+}
+
+// complete verifies code against factorID with c.mu held.
+func (c *Challenge) complete(ctx context.Context, factorID, code string) (*Token, error) {
+	tok, err := c.auth.verifyChallenge(ctx, c.MFAToken, factorID, code)
+	if err != nil {
+		var te *TokenError
+		if errors.As(err, &te) && te.Code == "expired_token" {
+			c.ExpiresAt = c.now()
+			return nil, ErrChallengeExpired
+		}
+		return nil, err
+	}
+	c.Done = true
+	return tok, nil
+}
+
+// WaitForApproval completes a challenge whose selected factor is push by
+// polling until the user responds, every interval (default 2s). It returns
+// ErrPushDenied if they deny the prompt, and ErrNotPushFactor at once if
+// the selected factor is not push.
+//
+// Applications receiving EventMFAPushResponded webhooks can instead call
+// Complete with an empty code once the event arrives.
+func (c *Challenge) WaitForApproval(ctx context.Context, interval time.Duration) (*Token, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	c.mu.Lock()
+	f, err := c.selected()
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if f.Type != FactorPush {
+		return nil, ErrNotPushFactor
+	}
+	for {
+		tok, err := c.Complete(ctx, "")
+		var te *TokenError
+		switch {
+		case err == nil:
+			return tok, nil
+		case errors.As(err, &te) && te.Code == "authorization_pending":
+		case errors.As(err, &te) && te.Code == "access_denied":
+			return nil, ErrPushDenied
+		default:
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// UseRecoveryCode completes the challenge with one of the user's recovery
+// codes instead of their usual factor. Spaces, hyphens and case in code
+// are ignored. Token.RecoveryCodesRemaining reports how many are left.
+// The selected factor is unchanged unless the code is accepted.
+func (c *Challenge) UseRecoveryCode(ctx context.Context, code string) (*Token, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.usable(); err != nil {
+		return nil, err
+	}
+	var id string
+	for _, f := range c.Factors {
+		if f.Type == FactorRecoveryCode {
+			id = f.ID
+		}
+	}
+	if id == "" {
+		return nil, ErrUnknownFactor
+	}
+	tok, err := c.complete(ctx, id, NormalizeRecoveryCode(code))
+	if err != nil {
+		return nil, err
+	}
+	c.SelectedFactor = id
+	return tok, nil
+}
+
+func (c *Challenge) factor(id string) (Factor, bool) {
+	for _, f := range c.Factors {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return Factor{}, false
+}
+
+// sendChallenge asks AuthVital to deliver a code for factorID.
+func (s *AuthService) sendChallenge(ctx context.Context, mfaToken, factorID string) error {
+	return ErrNotImplemented
+}
+
+// verifyChallenge redeems code for tokens.
+func (s *AuthService) verifyChallenge(ctx context.Context, mfaToken, factorID, code string) (*Token, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,82 @@
+package authvital
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// RecoveryCodes is a freshly generated set of recovery codes. The codes
+// are only returned once; show them to the user and do not store them.
+type RecoveryCodes struct {
+	Codes     []string  `json:"codes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RecoveryCodesStatus reports a user's recovery codes without revealing them.
+type RecoveryCodesStatus struct {
+	Remaining int       `json:"remaining"`
+	Total     int       `json:"total"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// MFAService manages users' second factors.
+type MFAService struct{}
+
+// GenerateRecoveryCodes replaces the user's recovery codes with a new set,
+// invalidating any unused old ones.
+func (s *MFAService) GenerateRecoveryCodes(ctx context.Context, userID string) (*RecoveryCodes, error) {
+	return nil, ErrNotImplemented
+}
+
+// RecoveryCodesStatus returns how many of the user's recovery codes are unused.
+func (s *MFAService) RecoveryCodesStatus(ctx context.Context, userID string) (*RecoveryCodesStatus, error) {
+	return nil, ErrNotImplemented
+}
+
+// PushEnrollment is a pending push factor enrollment. Show ActivationURL
+// as a QR code for the AuthVital authenticator app to scan.
+type PushEnrollment struct {
+	FactorID      string    `json:"factorId"`
+	ActivationURL string    `json:"activationUrl"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// ListFactors returns the user's enrolled second factors.
+func (s *MFAService) ListFactors(ctx context.Context, userID string) ([]Factor, error) {
+	return nil, ErrNotImplemented
+}
+
+// EnrollPush starts enrolling a push factor for the user. It becomes
+// usable once the app has scanned the activation code.
+func (s *MFAService) EnrollPush(ctx context.Context, userID string) (*PushEnrollment, error) {
+	return nil, ErrNotImplemented
+}
+
+// EnrollSMS starts enrolling an SMS factor for phone, normalized with
+// NormalizePhone in region. A verification code is sent to the number.
+func (s *MFAService) EnrollSMS(ctx context.Context, userID, phone, region string) (*Factor, error) {
+	if _, err := NormalizePhone(phone, region); err != nil {
+		return nil, err
+	}
+	return nil, ErrNotImplemented
+}
+
+// DeleteFactor removes one of the user's factors.
+func (s *MFAService) DeleteFactor(ctx context.Context, userID, factorID string) error {
+	return ErrNotImplemented
+}
+
+// NormalizeRecoveryCode strips the spaces and hyphens users type into
+// recovery codes and upper-cases them.
+func NormalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '\t' {
+			return -1
+		}
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, code)
+}
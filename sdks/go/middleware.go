@@ -0,0 +1,63 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type claimsKey struct{}
+
+// ContextWithClaims returns a copy of ctx carrying verified claims.
+func ContextWithClaims(ctx context.Context, c Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, c)
+}
+
+// ClaimsFromContext returns the claims stored by Verifier.Middleware.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(Claims)
+	return c, ok
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < 7 || !strings.EqualFold(h[:7], "bearer ") {
+		return "", false
+	}
+	tok := strings.TrimSpace(h[7:])
+	return tok, tok != ""
+}
+
+// Middleware verifies the bearer token of each request and stores its
+// claims in the request context. Requests without a valid token get 401
+// Unauthorized.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tok, ok := BearerToken(r)
+		if !ok {
+			unauthorized(w, "missing bearer token")
+			return
+		}
+		claims, err := v.Verify(r.Context(), tok)
+		if errors.Is(err, ErrTokenExpired) {
+			unauthorized(w, "token expired")
+			return
+		}
+		if err != nil {
+			unauthorized(w, "invalid token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+	})
+}
+
+func unauthorized(w http.ResponseWriter, desc string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="`+desc+`"`)
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// errNoClaims is returned by middleware that needs an authenticated user
+// when none is in the request context.
+var errNoClaims = errors.New("authvital: no verified claims in request context")
@@ -0,0 +1,285 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDeviceFlowUnsupported is returned when device flow is needed but the
+// server does not advertise a device authorization endpoint.
+var ErrDeviceFlowUnsupported = errors.New("authvital: server does not support the device authorization grant")
+
+// DefaultNativeLoginTimeout bounds how long LoginNative waits for the user.
+const DefaultNativeLoginTimeout = 5 * time.Minute
+
+// NativeLoginOptions configure LoginNative.
+type NativeLoginOptions struct {
+	ClientID string
+	// Scope defaults to DefaultScope.
+	Scope string
+	// Port is the loopback port to listen on. Zero picks a free port; the
+	// application must then allow any port on http://127.0.0.1 as a
+	// redirect URI, as RFC 8252 recommends.
+	Port int
+	// Path is the redirect URI path. Defaults to "/callback".
+	Path string
+	// Timeout defaults to DefaultNativeLoginTimeout.
+	Timeout time.Duration
+	// OpenBrowser opens the authorize URL. Defaults to OpenBrowser.
+	OpenBrowser func(url string) error
+	// DeviceFallback switches to the device authorization grant when the
+	// browser cannot be opened, for example over SSH.
+	DeviceFallback bool
+	// OnDeviceCode shows the user code and verification URL. Required
+	// with DeviceFallback.
+	OnDeviceCode func(*DeviceCode)
+	Login        *LoginOptions
+	HTTPClient   *http.Client
+	// Clock sets token expiry; it defaults to the system clock.
+	Clock Clock
+	// Rand generates the state and PKCE verifier; it defaults to
+	// crypto/rand.
+	Rand io.Reader
+}
+
+// LoginNative signs a user in from a desktop or command-line app: it opens
+// the system browser at the authorize URL, receives the redirect on a
+// loopback listener and exchanges the code with PKCE.
+func LoginNative(ctx context.Context, host string, opts *NativeLoginOptions) (*Token, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultNativeLoginTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(opts.Port)))
+	if err != nil {
+		return nil, err
+	}
+	defer ln.Close()
+	path := opts.Path
+	if path == "" {
+		path = "/callback"
+	}
+	redirectURI := "http://" + ln.Addr().String() + path
+
+	state, err := GenerateStateFrom(randReader(opts.Rand))
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := GenerateCodeVerifierFrom(randReader(opts.Rand))
+	if err != nil {
+		return nil, err
+	}
+	authURL, err := BuildAuthorizeURL(host, &AuthorizeParams{
+		ClientID:      opts.ClientID,
+		RedirectURI:   redirectURI,
+		Scope:         opts.Scope,
+		State:         state,
+		CodeChallenge: CodeChallenge(verifier),
+		Login:         opts.Login,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		code string
+		err  error
+	}
+	done := make(chan result, 1)
+	srv := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != path {
+				http.NotFound(w, r)
+				return
+			}
+			q := r.URL.Query()
+			var res result
+			switch {
+			case q.Get("state") != state:
+				// Ignore stray requests rather than failing the login; this
+				// also keeps anything that can reach the port from aborting
+				// it with an error it made up.
+				http.Error(w, "invalid state", http.StatusBadRequest)
+				return
+			case q.Get("error") != "":
+				res.err = &CallbackError{Code: q.Get("error"), Description: q.Get("error_description")}
+			default:
+				res.code = q.Get("code")
+			}
+			msg := "Signed in. You can close this window."
+			if res.err != nil {
+				msg = "Sign-in failed. You can close this window."
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprintln(w, msg)
+			select {
+			case done <- res:
+			default:
+			}
+		}),
+	}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	open := opts.OpenBrowser
+	if open == nil {
+		open = OpenBrowser
+	}
+	if err := open(authURL); err != nil {
+		if !opts.DeviceFallback {
+			return nil, fmt.Errorf("authvital: opening browser: %w", err)
+		}
+		srv.Close()
+		return DeviceLogin(ctx, host, &DeviceLoginOptions{
+			ClientID:   opts.ClientID,
+			Scope:      opts.Scope,
+			Show:       opts.OnDeviceCode,
+			HTTPClient: opts.HTTPClient,
+			Clock:      opts.Clock,
+		})
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return ExchangeCode(ctx, opts.HTTPClient, host, &ExchangeParams{
+			ClientID:     opts.ClientID,
+			Code:         res.code,
+			RedirectURI:  redirectURI,
+			CodeVerifier: verifier,
+			Clock:        opts.Clock,
+		})
+	}
+}
+
+// OpenBrowser opens u in the user's default browser.
+func OpenBrowser(u string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	return cmd.Start()
+}
+
+// DeviceCode is a device authorization response (RFC 8628).
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval,omitempty"`
+}
+
+// DeviceLoginOptions configure DeviceLogin.
+type DeviceLoginOptions struct {
+	ClientID string
+	Scope    string
+	// Show displays the user code and verification URL. Required.
+	Show       func(*DeviceCode)
+	HTTPClient *http.Client
+	// Clock times the code's expiry; it defaults to the system clock.
+	Clock Clock
+}
+
+// DeviceLogin signs a user in with the device authorization grant: it
+// shows a code for the user to enter on another device and polls until
+// they have.
+func DeviceLogin(ctx context.Context, host string, opts *DeviceLoginOptions) (*Token, error) {
+	if opts.Show == nil {
+		return nil, errors.New("authvital: DeviceLoginOptions.Show is required")
+	}
+	hc := opts.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	disc, err := getJSON(ctx, hc, strings.TrimRight(host, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	var meta struct {
+		Endpoint string `json:"device_authorization_endpoint"`
+	}
+	if err := json.Unmarshal(disc.body, &meta); err != nil || meta.Endpoint == "" {
+		return nil, ErrDeviceFlowUnsupported
+	}
+	scope := opts.Scope
+	if scope == "" {
+		scope = DefaultScope
+	}
+	form := url.Values{"client_id": {opts.ClientID}, "scope": {scope}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("authvital: device authorization returned %s", resp.Status)
+	}
+	var dc DeviceCode
+	if err := json.NewDecoder(resp.Body).Decode(&dc); err != nil {
+		return nil, err
+	}
+	opts.Show(&dc)
+
+	interval := time.Duration(dc.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := now(opts.Clock).Add(time.Duration(dc.ExpiresIn) * time.Second)
+	poll := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"client_id":   {opts.ClientID},
+		"device_code": {dc.DeviceCode},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		tok, err := requestToken(ctx, hc, host, poll, opts.Clock)
+		var te *TokenError
+		switch {
+		case err == nil:
+			return tok, nil
+		case errors.As(err, &te) && te.Code == "authorization_pending":
+		case errors.As(err, &te) && te.Code == "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, err
+		}
+		if dc.ExpiresIn > 0 && now(opts.Clock).After(deadline) {
+			return nil, &TokenError{Code: "expired_token", Description: "the user did not finish signing in"}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package authvital
+
+import "context"
+
+// NetworkPolicy restricts which networks may reach a tenant's sign-in and
+// API endpoints.
+type NetworkPolicy struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Allow lists CIDRs or IPs that are permitted. When non-empty, all other
+	// addresses are denied.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists CIDRs or IPs that are always rejected, even if allowed.
+	Deny []string `json:"deny,omitempty"`
+	// AllowCountries lists ISO 3166-1 alpha-2 codes that are permitted.
+	AllowCountries []string `json:"allowCountries,omitempty"`
+	// DenyCountries lists ISO 3166-1 alpha-2 codes that are rejected.
+	DenyCountries []string `json:"denyCountries,omitempty"`
+	Enabled       bool     `json:"enabled"`
+}
+
+// NetworkDecision is the outcome of testing an address against the policies.
+type NetworkDecision struct {
+	Allowed bool   `json:"allowed"`
+	Country string `json:"country,omitempty"`
+	// PolicyID and Rule identify the rule that decided the outcome. They are
+	// empty when no rule matched.
+	PolicyID string `json:"policyId,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+}
+
+// NetworkPoliciesService manages IP and geo restrictions.
+type NetworkPoliciesService struct{}
+
+// List lists the tenant's network policies.
+func (s *NetworkPoliciesService) List(ctx context.Context) ([]NetworkPolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get retrieves a network policy.
+func (s *NetworkPoliciesService) Get(ctx context.Context, policyID string) (*NetworkPolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create creates a network policy.
+func (s *NetworkPoliciesService) Create(ctx context.Context, policy *NetworkPolicy) (*NetworkPolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces a network policy.
+func (s *NetworkPoliciesService) Update(ctx context.Context, policy *NetworkPolicy) (*NetworkPolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete deletes a network policy.
+func (s *NetworkPoliciesService) Delete(ctx context.Context, policyID string) error {
+	return ErrNotImplemented
+}
+
+// Test evaluates ip against the enabled policies without making a request
+// from it, for checking rules before enabling them.
+func (s *NetworkPoliciesService) Test(ctx context.Context, ip string) (*NetworkDecision, error) {
+	return nil, ErrNotImplemented
+}
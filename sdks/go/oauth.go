@@ -0,0 +1,148 @@
+package authvital
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// DefaultScope is requested when AuthorizeParams.Scope is empty.
+const DefaultScope = "openid profile email"
+
+// AuthorizeParams are the parameters of an authorization code + PKCE request.
+type AuthorizeParams struct {
+	ClientID      string
+	RedirectURI   string
+	Scope         string
+	State         string
+	Nonce         string
+	CodeChallenge string
+	// TenantID or TenantSubdomain request a token scoped to that
+	// organization. The user must be a member of it.
+	TenantID        string
+	TenantSubdomain string
+	// Resource lists the APIs the tokens are for (RFC 8707). The refresh
+	// token covers all of them; request an access token for each with
+	// ExchangeParams.Resource or RefreshParams.Resource.
+	Resource []string
+	// Login customizes the hosted login page.
+	Login *LoginOptions
+	// Extra holds additional query parameters.
+	Extra url.Values
+	// RequestObject, when set, moves the parameters into a signed request
+	// object passed as the request parameter (RFC 9101).
+	RequestObject *RequestObjectOptions
+	// Clock and Rand are used by StartFlow for the flow's creation time and
+	// its state, nonce and PKCE values, normally Client.Clock and
+	// Client.Rand. They default to the system clock and crypto/rand.
+	Clock Clock
+	Rand  io.Reader
+}
+
+// values returns the parameters as an authorize query, without request objects.
+func (p *AuthorizeParams) values() url.Values {
+	q := url.Values{}
+	for k, v := range p.Extra {
+		q[k] = append([]string(nil), v...)
+	}
+	scope := p.Scope
+	if scope == "" {
+		scope = DefaultScope
+	}
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", p.RedirectURI)
+	q.Set("response_type", "code")
+	q.Set("scope", scope)
+	q.Set("state", p.State)
+	q.Set("code_challenge", p.CodeChallenge)
+	q.Set("code_challenge_method", "S256")
+	if p.Nonce != "" {
+		q.Set("nonce", p.Nonce)
+	}
+	if p.TenantID != "" {
+		q.Set("tenant_id", p.TenantID)
+	}
+	if p.TenantSubdomain != "" {
+		q.Set("tenant_subdomain", p.TenantSubdomain)
+	}
+	for _, r := range p.Resource {
+		q.Add("resource", r)
+	}
+	if p.Login != nil {
+		p.Login.apply(q)
+	}
+	return q
+}
+
+// BuildAuthorizeURL builds the URL to redirect the user to for sign-in.
+func BuildAuthorizeURL(host string, p *AuthorizeParams) (string, error) {
+	if p.ClientID == "" || p.RedirectURI == "" {
+		return "", errors.New("authvital: client ID and redirect URI are required")
+	}
+	if err := validateResources(p.Resource); err != nil {
+		return "", err
+	}
+	if p.Login != nil {
+		if err := p.Login.validate(); err != nil {
+			return "", err
+		}
+	}
+	path := "/oauth/authorize"
+	if p.TenantID != "" || p.TenantSubdomain != "" {
+		path = "/oauth/authorize-tenant"
+	}
+	u, err := url.Parse(strings.TrimRight(host, "/") + path)
+	if err != nil {
+		return "", err
+	}
+	q := p.values()
+	if p.RequestObject != nil {
+		req, err := signRequestObject(q, p.RequestObject)
+		if err != nil {
+			return "", err
+		}
+		// Only client_id and the OIDC-required response_type and scope stay
+		// outside the request object.
+		q = url.Values{
+			"client_id":     {p.ClientID},
+			"response_type": {"code"},
+			"scope":         {q.Get("scope")},
+			"request":       {req},
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// GenerateCodeVerifier returns a random PKCE code verifier.
+func GenerateCodeVerifier() (string, error) {
+	return randomString(nil, 32)
+}
+
+// CodeChallenge returns the S256 PKCE code challenge of verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return b64.EncodeToString(sum[:])
+}
+
+// GenerateState returns a random state value for CSRF protection.
+func GenerateState() (string, error) {
+	return randomString(nil, 32)
+}
+
+// GenerateNonce returns a random OIDC nonce.
+func GenerateNonce() (string, error) {
+	return randomString(nil, 32)
+}
+
+// randomString returns n bytes read from r, or crypto/rand when r is nil,
+// base64url-encoded.
+func randomString(r io.Reader, n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(randReader(r), b); err != nil {
+		return "", err
+	}
+	return b64.EncodeToString(b), nil
+}
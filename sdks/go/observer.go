@@ -0,0 +1,152 @@
+package authvital
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RequestInfo describes a completed API request.
+type RequestInfo struct {
+	Method string
+	// Route is the request path with IDs replaced by placeholders, so it can
+	// be used as a low-cardinality metric label.
+	Route string
+	// Status is the HTTP status, or 0 if no response was received.
+	Status int
+	// ErrorCode is the API error code of failed requests, e.g.
+	// "insufficient_scope", as parsed into APIError.Code, or "network"
+	// when no response was received.
+	ErrorCode string
+	Duration  time.Duration
+}
+
+// Observer receives SDK telemetry. Implementations must be safe for
+// concurrent use and return quickly.
+type Observer interface {
+	ObserveRequest(RequestInfo)
+	// ObserveTokenCache reports a token lookup served from cache (hit) or
+	// requiring a new token (miss).
+	ObserveTokenCache(hit bool)
+	// ObserveTokenRefresh reports fetching a new access token.
+	ObserveTokenRefresh(d time.Duration, err error)
+}
+
+// WithObserver reports client telemetry to o.
+func WithObserver(o Observer) Option {
+	return func(c *Client) {}
+}
+
+// ObservedTransport is an http.RoundTripper reporting every request to an
+// Observer. With a nil Observer it only forwards requests.
+type ObservedTransport struct {
+	Base     http.RoundTripper
+	Observer Observer
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ObservedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Observer == nil {
+		return base.RoundTrip(req)
+	}
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	info := RequestInfo{Method: req.Method, Route: routeTemplate(req.URL.Path), Duration: time.Since(start)}
+	if err != nil {
+		info.ErrorCode = "network"
+	} else {
+		info.Status = resp.StatusCode
+		if resp.StatusCode >= 400 {
+			info.ErrorCode = peekErrorCode(resp)
+		}
+	}
+	t.Observer.ObserveRequest(info)
+	return resp, err
+}
+
+// peekErrorCode returns the API error code of resp, leaving its body
+// readable by the caller.
+func peekErrorCode(resp *http.Response) string {
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(raw), resp.Body), resp.Body}
+	base, _ := decodeAPIError(resp, raw)
+	return base.Code
+}
+
+// routeTemplate replaces path segments that look like IDs with ":id".
+func routeTemplate(path string) string {
+	b := []byte(path)
+	out := make([]byte, 0, len(b))
+	for len(b) > 0 {
+		if b[0] == '/' {
+			out = append(out, '/')
+			b = b[1:]
+			continue
+		}
+		end := 0
+		for end < len(b) && b[end] != '/' {
+			end++
+		}
+		seg := b[:end]
+		if looksLikeID(seg) {
+			out = append(out, ":id"...)
+		} else {
+			out = append(out, seg...)
+		}
+		b = b[end:]
+	}
+	return string(out)
+}
+
+// looksLikeID reports whether seg has the shape of an identifier rather
+// than a route word such as "v1" or "oauth2": all digits, a UUID, a
+// prefixed ID such as "usr_2b7c9e", or a long opaque value with digits.
+func looksLikeID(seg []byte) bool {
+	digits, hex, alnum := 0, 0, 0
+	for _, c := range seg {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+			hex++
+			alnum++
+		case c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F':
+			hex++
+			alnum++
+		case c >= 'g' && c <= 'z' || c >= 'G' && c <= 'Z':
+			alnum++
+		}
+	}
+	switch {
+	case len(seg) == 0:
+		return false
+	case digits == len(seg):
+		return true
+	case len(seg) == 36 && hex == 32:
+		for _, i := range []int{8, 13, 18, 23} {
+			if seg[i] != '-' {
+				return false
+			}
+		}
+		return true
+	case len(seg) >= 20 && digits > 0:
+		return true
+	}
+	prefix, rest, ok := bytes.Cut(seg, []byte("_"))
+	if !ok || len(prefix) < 2 || len(prefix) > 8 || len(rest) < 6 || !bytes.ContainsAny(rest, "0123456789") {
+		return false
+	}
+	for _, c := range prefix {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return alnum == len(seg)-1
+}
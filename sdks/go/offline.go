@@ -0,0 +1,162 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScopeOfflineAccess requests a refresh token usable while the user is
+// away. Add it to AuthorizeParams.Scope when a background job will act for
+// the user.
+const ScopeOfflineAccess = "offline_access"
+
+// ErrTokenNotFound is returned by TokenStore.Get when no token is stored.
+var ErrTokenNotFound = errors.New("authvital: token not found")
+
+// TokenStore persists tokens for background use. Implementations should
+// encrypt tokens at rest; refresh tokens are long-lived credentials.
+type TokenStore interface {
+	Get(ctx context.Context, key string) (*Token, error)
+	Put(ctx context.Context, key string, tok *Token) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryTokenStore is a TokenStore in process memory, for tests and single
+// instance tools.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: map[string]Token{}}
+}
+
+// Get implements TokenStore.
+func (m *MemoryTokenStore) Get(ctx context.Context, key string) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tok, ok := m.tokens[key]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return &tok, nil
+}
+
+// Put implements TokenStore.
+func (m *MemoryTokenStore) Put(ctx context.Context, key string, tok *Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[key] = *tok
+	return nil
+}
+
+// Delete implements TokenStore.
+func (m *MemoryTokenStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, key)
+	return nil
+}
+
+// JobTokenSource supplies access tokens for a background job acting for a
+// user. Its stored token is confined to Scopes: a job token can never
+// carry more than the job was created with, even though the user's
+// consent may be broader.
+type JobTokenSource struct {
+	Host         string
+	ClientID     string
+	ClientSecret string
+	Store        TokenStore
+	// Key is the job's entry in Store.
+	Key    string
+	Scopes []string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Clock sets and checks token expiry; it defaults to the system clock.
+	Clock Clock
+
+	mu sync.Mutex
+}
+
+// Start derives the job's token from a user's offline refresh token,
+// narrowing it to s.Scopes, and saves it in the store. The user's own
+// refresh token is left untouched unless the server rotates it, so call
+// Start right after sign-in, with the refresh token from that exchange.
+func (s *JobTokenSource) Start(ctx context.Context, userRefreshToken string) error {
+	if !contains(s.Scopes, ScopeOfflineAccess) {
+		return fmt.Errorf("authvital: job scopes must include %s", ScopeOfflineAccess)
+	}
+	// Hold the lock so a concurrent Token cannot refresh the old stored
+	// token and overwrite the one saved here.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tok, err := s.refresh(ctx, userRefreshToken)
+	if err != nil {
+		return err
+	}
+	if tok.RefreshToken == "" {
+		return errors.New("authvital: no refresh token issued; the user must consent to offline_access")
+	}
+	return s.Store.Put(ctx, s.Key, tok)
+}
+
+// Token returns a valid access token, refreshing and saving the stored
+// token when it is about to expire.
+func (s *JobTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tok, err := s.Store.Get(ctx, s.Key)
+	if err != nil {
+		return nil, err
+	}
+	if tok.AccessToken != "" && tok.Expiry.Sub(now(s.Clock)) > time.Minute {
+		return tok, nil
+	}
+	fresh, err := s.refresh(ctx, tok.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if fresh.RefreshToken == "" {
+		fresh.RefreshToken = tok.RefreshToken
+	}
+	if err := s.Store.Put(ctx, s.Key, fresh); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+// Stop deletes the job's token. Revoke the refresh token in AuthVital too
+// if the job will not run again.
+func (s *JobTokenSource) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Store.Delete(ctx, s.Key)
+}
+
+func (s *JobTokenSource) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	tok, err := RefreshToken(ctx, s.HTTPClient, s.Host, &RefreshParams{
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		RefreshToken: refreshToken,
+		Scope:        strings.Join(s.Scopes, " "),
+		Clock:        s.Clock,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tok.Scope != "" {
+		for _, sc := range strings.Fields(tok.Scope) {
+			if !contains(s.Scopes, sc) {
+				return nil, fmt.Errorf("authvital: job token was granted unexpected scope %q", sc)
+			}
+		}
+	}
+	return tok, nil
+}
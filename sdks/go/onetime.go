@@ -0,0 +1,64 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by TokensService.VerifyOneTime.
+var (
+	// ErrTokenConsumed is returned for a one-time token that was already
+	// verified once.
+	ErrTokenConsumed = errors.New("authvital: one-time token already used")
+	// ErrTokenPurpose is returned when a one-time token was issued for a
+	// different purpose than the caller expects.
+	ErrTokenPurpose = errors.New("authvital: one-time token purpose mismatch")
+	// ErrOneTimeExpired is returned for a one-time token past its expiry.
+	// It wraps ErrTokenExpired.
+	ErrOneTimeExpired = fmt.Errorf("%w: one-time token expired", ErrTokenExpired)
+)
+
+// MaxOneTimeTTL is the longest lifetime of a one-time token.
+const MaxOneTimeTTL = 7 * 24 * time.Hour
+
+// OneTimeToken is an issued single-use token.
+type OneTimeToken struct {
+	// Token is the opaque value to embed in a link. It is URL-safe.
+	Token     string    `json:"token"`
+	Purpose   string    `json:"purpose"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// OneTimeClaims is what a verified one-time token was issued with.
+type OneTimeClaims struct {
+	Purpose  string         `json:"purpose"`
+	Claims   map[string]any `json:"claims"`
+	IssuedAt time.Time      `json:"issuedAt"`
+}
+
+// TokensService issues and redeems single-use tokens, such as download
+// links and email action links. The server records consumption, so a
+// token is accepted at most once even across instances.
+type TokensService struct{}
+
+// CreateOneTime issues a token for purpose, valid for ttl (at most
+// MaxOneTimeTTL). claims are returned by VerifyOneTime and never exposed
+// in the token itself.
+func (s *TokensService) CreateOneTime(ctx context.Context, purpose string, ttl time.Duration, claims map[string]any) (*OneTimeToken, error) {
+	return nil, ErrNotImplemented
+}
+
+// VerifyOneTime redeems token and returns its claims. It returns
+// ErrOneTimeExpired, ErrTokenConsumed, or ErrTokenPurpose if purpose does
+// not match the one it was issued for; a mismatched token is not
+// consumed.
+func (s *TokensService) VerifyOneTime(ctx context.Context, token, purpose string) (*OneTimeClaims, error) {
+	return nil, ErrNotImplemented
+}
+
+// RevokeOneTime invalidates an unused token.
+func (s *TokensService) RevokeOneTime(ctx context.Context, token string) error {
+	return ErrNotImplemented
+}
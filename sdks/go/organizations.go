@@ -0,0 +1,53 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// Organization is an AuthVital tenant.
+type Organization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	// ParentID is the parent organization of a sub-organization.
+	ParentID  string       `json:"parentId,omitempty"`
+	Region    Region       `json:"region,omitempty"`
+	Billing   *BillingLink `json:"billing,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// OrganizationsService manages organizations.
+type OrganizationsService struct {
+	// Roles manages roles defined by individual organizations.
+	Roles *OrgRolesService
+	// Groups manages groups of organization members.
+	Groups *GroupsService
+}
+
+// Get retrieves an organization by ID. opts may be nil.
+func (s *OrganizationsService) Get(ctx context.Context, orgID string, opts *GetOptions) (*Organization, error) {
+	return nil, ErrNotImplemented
+}
+
+// List lists organizations. Use opts.Fields to fetch only the fields you need.
+func (s *OrganizationsService) List(ctx context.Context, opts *ListOptions) (*List[Organization], error) {
+	return nil, ErrNotImplemented
+}
+
+// Search lists organizations matching q.
+func (s *OrganizationsService) Search(ctx context.Context, q Query, opts *SearchOptions) (*List[Organization], error) {
+	return nil, ErrNotImplemented
+}
+
+// GetByExternalID retrieves the organization linked to an external billing
+// record, e.g. a Stripe customer.
+func (s *OrganizationsService) GetByExternalID(ctx context.Context, provider string, kind ExternalIDKind, id string) (*Organization, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetBilling links an organization to an external billing record. A nil
+// link removes it.
+func (s *OrganizationsService) SetBilling(ctx context.Context, orgID string, link *BillingLink) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,47 @@
+package authvital
+
+import "context"
+
+// EffectiveRole is a role a member holds in an organization, directly or
+// inherited from a membership in an ancestor.
+type EffectiveRole struct {
+	Role string `json:"role"`
+	// FromOrganizationID is the organization the role was granted in. It
+	// equals the queried organization for direct grants.
+	FromOrganizationID string `json:"fromOrganizationId"`
+	Inherited          bool   `json:"inherited"`
+}
+
+// CreateChild creates org as a sub-organization of parentID, such as a
+// division or subsidiary.
+func (s *OrganizationsService) CreateChild(ctx context.Context, parentID string, org *Organization) (*Organization, error) {
+	return nil, ErrNotImplemented
+}
+
+// Move re-parents an organization. An empty newParentID makes it a
+// top-level organization. Moving under one of its own descendants fails.
+func (s *OrganizationsService) Move(ctx context.Context, orgID, newParentID string) (*Organization, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListChildren lists an organization's direct sub-organizations.
+func (s *OrganizationsService) ListChildren(ctx context.Context, orgID string, opts *ListOptions) (*List[Organization], error) {
+	return nil, ErrNotImplemented
+}
+
+// ListDescendants lists all organizations below orgID, parents before
+// children.
+func (s *OrganizationsService) ListDescendants(ctx context.Context, orgID string, opts *ListOptions) (*List[Organization], error) {
+	return nil, ErrNotImplemented
+}
+
+// Ancestors returns the chain of parents of orgID, nearest first.
+func (s *OrganizationsService) Ancestors(ctx context.Context, orgID string) ([]Organization, error) {
+	return nil, ErrNotImplemented
+}
+
+// EffectiveRoles resolves the roles userID holds in orgID, including
+// those inherited from ancestor organizations.
+func (s *OrganizationsService) EffectiveRoles(ctx context.Context, orgID, userID string) ([]EffectiveRole, error) {
+	return nil, ErrNotImplemented
+}
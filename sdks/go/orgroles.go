@@ -0,0 +1,71 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRoleConflict is returned when creating or renaming a custom role to
+// a slug already used by a global role or another role of the
+// organization.
+var ErrRoleConflict = errors.New("authvital: role slug already in use")
+
+// OrgRolesService manages custom roles scoped to one organization. Custom
+// roles live alongside the global roles from RolesService; their slugs
+// may not shadow a global role.
+type OrgRolesService struct{}
+
+// List lists the organization's custom roles.
+func (s *OrgRolesService) List(ctx context.Context, orgID string) ([]Role, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get retrieves a custom role by slug.
+func (s *OrgRolesService) Get(ctx context.Context, orgID, slug string) (*Role, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create defines a custom role. If role.Slug is empty it is derived from
+// the name with RoleSlug. It returns ErrRoleConflict if the slug is taken.
+func (s *OrgRolesService) Create(ctx context.Context, orgID string, role *Role) (*Role, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces a custom role's name, description and permissions.
+// Changing the slug is not supported; create a new role instead.
+func (s *OrgRolesService) Update(ctx context.Context, orgID, slug string, role *Role) (*Role, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete removes a custom role and its assignments.
+func (s *OrgRolesService) Delete(ctx context.Context, orgID, slug string) error {
+	return ErrNotImplemented
+}
+
+// RoleSlug derives a role slug from a display name: lower case letters,
+// digits and hyphens, such as "billing-admin" for "Billing Admin".
+func RoleSlug(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			hyphen = false
+		case !hyphen && b.Len() > 0:
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// ValidateRoleSlug checks slug's format before a request is made.
+func ValidateRoleSlug(slug string) error {
+	if slug == "" || len(slug) > 64 || slug != RoleSlug(slug) {
+		return fmt.Errorf("authvital: invalid role slug %q; use lower case letters, digits and hyphens", slug)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package authvital
+
+// ListOptions controls pagination of list calls.
+type ListOptions struct {
+	// Limit is the maximum number of items to return. Zero uses the server default.
+	Limit int
+	// Cursor resumes a listing from a previous List.NextCursor.
+	Cursor string
+	// FieldMask limits the response to these fields; see Fields.
+	FieldMask []string
+	// Authorized limits the listing to objects a subject can access; see
+	// AuthorizedFor.
+	Authorized *RelationFilter
+}
+
+// AuthorizedFor filters the listing on the server to objects subject has
+// relation to, instead of fetching everything and checking each item. It
+// returns o for chaining.
+func (o *ListOptions) AuthorizedFor(subject, relation string) *ListOptions {
+	o.Authorized = &RelationFilter{Subject: subject, Relation: relation}
+	return o
+}
+
+// Fields asks the server to return only the named fields, cutting payload
+// size for large listings. Fields not requested are left at their zero
+// value in the decoded structs. It returns o for chaining.
+func (o *ListOptions) Fields(names ...string) *ListOptions {
+	o.FieldMask = append(o.FieldMask, names...)
+	return o
+}
+
+// GetOptions controls single-resource get calls.
+type GetOptions struct {
+	// FieldMask limits the response to these fields; see Fields.
+	FieldMask []string
+}
+
+// Fields asks the server to return only the named fields. Fields not
+// requested are left at their zero value. It returns o for chaining.
+func (o *GetOptions) Fields(names ...string) *GetOptions {
+	o.FieldMask = append(o.FieldMask, names...)
+	return o
+}
+
+// List is one page of results.
+type List[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,140 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPasskeyChallenge is returned for an assertion whose challenge was not
+// issued by PasskeyChallenges, has expired or was already used.
+var ErrPasskeyChallenge = errors.New("authvital: unknown, expired or reused passkey challenge")
+
+// DefaultPasskeyChallengeTTL bounds how long a passkey login challenge is
+// valid. Conditional UI keeps the request pending while the user types, so
+// it is longer than for modal prompts.
+const DefaultPasskeyChallengeTTL = 5 * time.Minute
+
+// PasskeyLoginOptions are WebAuthn PublicKeyCredentialRequestOptions in
+// their JSON form, for navigator.credentials.get. AllowCredentials is
+// always empty so the browser offers any discoverable credential for the
+// relying party, which conditional mediation requires.
+type PasskeyLoginOptions struct {
+	Challenge        string   `json:"challenge"`
+	RPID             string   `json:"rpId"`
+	Timeout          int64    `json:"timeout"`
+	UserVerification string   `json:"userVerification"`
+	AllowCredentials []string `json:"allowCredentials"`
+	// Mediation is "conditional"; pass it as the mediation option of
+	// navigator.credentials.get rather than inside publicKey.
+	Mediation string `json:"mediation"`
+}
+
+// PasskeyAssertion is a PublicKeyCredential from navigator.credentials.get
+// in its JSON form (PublicKeyCredential.toJSON).
+type PasskeyAssertion struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		// UserHandle identifies the user for discoverable credentials.
+		UserHandle string `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// PasskeyChallenges issues passkey login challenges and checks them on the
+// way back. Challenges are kept in Store so any instance can verify an
+// assertion, and each is single-use.
+type PasskeyChallenges struct {
+	Store KeyValueStore
+	// TTL defaults to DefaultPasskeyChallengeTTL.
+	TTL time.Duration
+	// Prefix namespaces keys. Defaults to "authvital:passkey:".
+	Prefix string
+}
+
+func (c *PasskeyChallenges) key(challenge string) string {
+	if c.Prefix != "" {
+		return c.Prefix + challenge
+	}
+	return "authvital:passkey:" + challenge
+}
+
+// ConditionalLogin returns options for a username-less passkey sign-in
+// using conditional mediation (autofill).
+func (c *PasskeyChallenges) ConditionalLogin(ctx context.Context, rpID string) (*PasskeyLoginOptions, error) {
+	challenge, err := randomString(nil, 32)
+	if err != nil {
+		return nil, err
+	}
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultPasskeyChallengeTTL
+	}
+	if err := c.Store.Set(ctx, c.key(challenge), []byte(rpID), ttl); err != nil {
+		return nil, err
+	}
+	return &PasskeyLoginOptions{
+		Challenge:        challenge,
+		RPID:             rpID,
+		Timeout:          ttl.Milliseconds(),
+		UserVerification: "preferred",
+		AllowCredentials: []string{},
+		Mediation:        "conditional",
+	}, nil
+}
+
+// Check consumes the assertion's challenge and checks its client data
+// type and origin, which must be one of origins. The signature itself is
+// verified by Passkeys.VerifyAssertion against the stored credential.
+func (c *PasskeyChallenges) Check(ctx context.Context, a *PasskeyAssertion, origins ...string) error {
+	raw, err := b64.DecodeString(a.Response.ClientDataJSON)
+	if err != nil {
+		return fmt.Errorf("authvital: passkey client data: %w", err)
+	}
+	var cd struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("authvital: passkey client data: %w", err)
+	}
+	if cd.Type != "webauthn.get" {
+		return fmt.Errorf("authvital: passkey client data type %q is not webauthn.get", cd.Type)
+	}
+	if !contains(origins, cd.Origin) {
+		return fmt.Errorf("authvital: passkey origin %q is not allowed", cd.Origin)
+	}
+	rp, err := c.Store.GetDel(ctx, c.key(cd.Challenge))
+	if err != nil {
+		return err
+	}
+	if rp == nil {
+		return ErrPasskeyChallenge
+	}
+	return nil
+}
+
+// PasskeyLogin is the result of a verified passkey assertion.
+type PasskeyLogin struct {
+	UserID       string `json:"userId"`
+	CredentialID string `json:"credentialId"`
+	// Token is the session's tokens.
+	Token *Token `json:"token"`
+}
+
+// PasskeysService verifies passkey sign-ins.
+type PasskeysService struct{}
+
+// VerifyAssertion verifies the assertion's signature against the
+// credential registered in AuthVital, checks its signature counter, and
+// signs the credential's user in. Call PasskeyChallenges.Check first.
+func (s *PasskeysService) VerifyAssertion(ctx context.Context, a *PasskeyAssertion) (*PasskeyLogin, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,140 @@
+package authvital
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Password policy defaults, used when the lengths are zero.
+const (
+	DefaultPasswordMinLength = 8
+	DefaultPasswordMaxLength = 128
+)
+
+// PasswordRule is a requirement of a password policy.
+type PasswordRule string
+
+const (
+	PasswordMinLength PasswordRule = "min_length"
+	PasswordMaxLength PasswordRule = "max_length"
+	PasswordLowercase PasswordRule = "lowercase"
+	PasswordUppercase PasswordRule = "uppercase"
+	PasswordDigit     PasswordRule = "digit"
+	PasswordSymbol    PasswordRule = "symbol"
+	// PasswordIdentifier forbids passwords containing the user's email
+	// local part or username.
+	PasswordIdentifier PasswordRule = "identifier"
+	// PasswordHistory and PasswordBreached depend on server data and are
+	// only enforced by the server.
+	PasswordHistory  PasswordRule = "history"
+	PasswordBreached PasswordRule = "breached"
+)
+
+// PasswordPolicy is the tenant's password policy, enforced whenever a
+// password is set.
+type PasswordPolicy struct {
+	// MinLength and MaxLength count characters, defaulting to
+	// DefaultPasswordMinLength and DefaultPasswordMaxLength.
+	MinLength        int  `json:"minLength,omitempty"`
+	MaxLength        int  `json:"maxLength,omitempty"`
+	RequireLowercase bool `json:"requireLowercase,omitempty"`
+	RequireUppercase bool `json:"requireUppercase,omitempty"`
+	RequireDigit     bool `json:"requireDigit,omitempty"`
+	// RequireSymbol requires a character that is not a letter or digit.
+	RequireSymbol bool `json:"requireSymbol,omitempty"`
+	// DisallowIdentifiers enables PasswordIdentifier.
+	DisallowIdentifiers bool `json:"disallowIdentifiers,omitempty"`
+	// History is how many previous passwords cannot be reused.
+	History int `json:"history,omitempty"`
+	// CheckBreached rejects passwords found in known breaches with
+	// ErrPasswordBreached.
+	CheckBreached bool `json:"checkBreached,omitempty"`
+}
+
+// ValidatePassword returns the rules of p that candidate fails, or nil,
+// for instant feedback on sign-up and password change forms. identifiers
+// are the user's email address and username, for PasswordIdentifier.
+// PasswordHistory and PasswordBreached are never reported, so the server
+// may still reject a password that passes. A nil p uses the defaults.
+func ValidatePassword(p *PasswordPolicy, candidate string, identifiers ...string) []PasswordRule {
+	if p == nil {
+		p = &PasswordPolicy{}
+	}
+	minLen, maxLen := p.MinLength, p.MaxLength
+	if minLen <= 0 {
+		minLen = DefaultPasswordMinLength
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultPasswordMaxLength
+	}
+	var lower, upper, digit, symbol bool
+	for _, r := range candidate {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	var failed []PasswordRule
+	n := utf8.RuneCountInString(candidate)
+	if n < minLen {
+		failed = append(failed, PasswordMinLength)
+	}
+	if n > maxLen {
+		failed = append(failed, PasswordMaxLength)
+	}
+	if p.RequireLowercase && !lower {
+		failed = append(failed, PasswordLowercase)
+	}
+	if p.RequireUppercase && !upper {
+		failed = append(failed, PasswordUppercase)
+	}
+	if p.RequireDigit && !digit {
+		failed = append(failed, PasswordDigit)
+	}
+	if p.RequireSymbol && !symbol {
+		failed = append(failed, PasswordSymbol)
+	}
+	if p.DisallowIdentifiers && containsIdentifier(candidate, identifiers) {
+		failed = append(failed, PasswordIdentifier)
+	}
+	return failed
+}
+
+// containsIdentifier reports whether password contains one of identifiers,
+// or the local part of an email address among them, ignoring case. Parts
+// shorter than three characters are ignored.
+func containsIdentifier(password string, identifiers []string) bool {
+	password = strings.ToLower(password)
+	for _, id := range identifiers {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if local, _, ok := strings.Cut(id, "@"); ok {
+			id = local
+		}
+		if utf8.RuneCountInString(id) >= 3 && strings.Contains(password, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordPolicyService configures the password policy.
+type PasswordPolicyService struct{}
+
+// Get retrieves the password policy, for use with ValidatePassword.
+func (s *PasswordPolicyService) Get(ctx context.Context) (*PasswordPolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces the password policy. Existing passwords are not affected
+// until they are next changed.
+func (s *PasswordPolicyService) Update(ctx context.Context, policy *PasswordPolicy) (*PasswordPolicy, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,182 @@
+package authvital
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPhone is returned for phone numbers that cannot be normalized
+// to E.164.
+var ErrInvalidPhone = errors.New("authvital: invalid phone number")
+
+// phoneRegion describes a region's numbering plan: its calling code, the
+// trunk prefix dialled before national numbers, and the length range of
+// national significant numbers.
+type phoneRegion struct {
+	code     string
+	trunk    string
+	min, max int
+}
+
+// phoneRegions are the numbering plans NormalizePhone knows. Numbers with
+// other calling codes are accepted in international format with only a
+// length check.
+var phoneRegions = map[string]phoneRegion{
+	"US": {"1", "1", 10, 10}, "CA": {"1", "1", 10, 10},
+	"GB": {"44", "0", 9, 10}, "IE": {"353", "0", 7, 9},
+	"DE": {"49", "0", 6, 13}, "AT": {"43", "0", 4, 13}, "CH": {"41", "0", 9, 9},
+	"FR": {"33", "0", 9, 9}, "BE": {"32", "0", 8, 9}, "NL": {"31", "0", 9, 9},
+	"ES": {"34", "", 9, 9}, "PT": {"351", "", 9, 9}, "IT": {"39", "", 6, 11},
+	"SE": {"46", "0", 7, 13}, "NO": {"47", "", 8, 8}, "DK": {"45", "", 8, 8},
+	"FI": {"358", "0", 5, 12}, "PL": {"48", "", 9, 9},
+	"RU": {"7", "8", 10, 10}, "TR": {"90", "0", 10, 10},
+	"IL": {"972", "0", 8, 9}, "AE": {"971", "0", 8, 9}, "ZA": {"27", "0", 9, 9},
+	"IN": {"91", "0", 10, 10}, "CN": {"86", "0", 10, 11}, "JP": {"81", "0", 9, 10},
+	"KR": {"82", "0", 8, 10}, "SG": {"65", "", 8, 8}, "HK": {"852", "", 8, 8},
+	"AU": {"61", "0", 9, 9}, "NZ": {"64", "0", 8, 10},
+	"BR": {"55", "0", 10, 11}, "MX": {"52", "", 10, 10}, "AR": {"54", "0", 10, 10},
+}
+
+// primaryRegion is the region PhoneRegion reports for calling codes that
+// several regions share.
+var primaryRegion = map[string]string{"1": "US", "7": "RU"}
+
+// NormalizePhone converts a phone number as typed by a user, such as
+// "(415) 555-0132" or "+44 20 7946 0958", to E.164 ("+14155550132").
+// region is the ISO 3166 country code used for numbers written without a
+// country code, typically the user's country; it may be empty when
+// numbers are always international.
+//
+// Use it wherever phone numbers enter the system, such as user creation,
+// SMS factor enrollment and searches, so the same number is always stored
+// and matched the same way.
+func NormalizePhone(raw, region string) (string, error) {
+	digits, intl, err := phoneDigits(raw)
+	if err != nil {
+		return "", err
+	}
+	region = strings.ToUpper(region)
+	r, known := phoneRegions[region]
+	if region != "" && !known {
+		return "", fmt.Errorf("%w: unknown region %q", ErrInvalidPhone, region)
+	}
+	switch {
+	case intl:
+	case strings.HasPrefix(digits, "00"):
+		digits, intl = digits[2:], true
+	case r.code == "1" && strings.HasPrefix(digits, "011"):
+		digits, intl = digits[3:], true
+	case !known:
+		return "", fmt.Errorf("%w: %q has no country code and no region was given", ErrInvalidPhone, raw)
+	}
+	if !intl {
+		national := digits
+		if r.trunk != "" && strings.HasPrefix(national, r.trunk) && len(national)-len(r.trunk) >= r.min {
+			national = national[len(r.trunk):]
+		}
+		if len(national) > r.max && strings.HasPrefix(digits, r.code) {
+			// Written in international format without the plus.
+			national = digits[len(r.code):]
+		}
+		digits = r.code + national
+	}
+	e164 := "+" + digits
+	if err := ValidatePhone(e164); err != nil {
+		return "", err
+	}
+	return e164, nil
+}
+
+// phoneDigits strips formatting from raw, reporting whether it had a
+// leading plus.
+func phoneDigits(raw string) (digits string, intl bool, err error) {
+	s := strings.TrimSpace(raw)
+	if strings.HasPrefix(s, "+") {
+		s, intl = s[1:], true
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(" -.()/\u00a0", r):
+		default:
+			return "", false, fmt.Errorf("%w: unexpected %q in %q; extensions are not supported", ErrInvalidPhone, r, raw)
+		}
+	}
+	if b.Len() == 0 {
+		return "", false, fmt.Errorf("%w: %q has no digits", ErrInvalidPhone, raw)
+	}
+	return b.String(), intl, nil
+}
+
+// ValidatePhone checks that e164 is a plausible E.164 number: a plus, at
+// most 15 digits, and a national number of a valid length for the
+// regions NormalizePhone knows.
+func ValidatePhone(e164 string) error {
+	digits, ok := strings.CutPrefix(e164, "+")
+	if !ok || len(digits) < 7 || len(digits) > 15 || digits[0] == '0' {
+		return fmt.Errorf("%w: %q is not in E.164 format", ErrInvalidPhone, e164)
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("%w: %q is not in E.164 format", ErrInvalidPhone, e164)
+		}
+	}
+	code, r, known := phoneCode(digits)
+	if !known {
+		return nil
+	}
+	national := digits[len(code):]
+	if len(national) < r.min || len(national) > r.max {
+		return fmt.Errorf("%w: %q has the wrong length for +%s", ErrInvalidPhone, e164, code)
+	}
+	if code == "1" && (national[0] < '2' || national[3] < '2') {
+		// North American area codes and exchanges start with 2-9.
+		return fmt.Errorf("%w: %q is not a valid North American number", ErrInvalidPhone, e164)
+	}
+	return nil
+}
+
+// PhoneRegion returns the region of an E.164 number, or "" when its
+// calling code is not known. Codes shared by several regions report the
+// largest, such as "US" for +1.
+func PhoneRegion(e164 string) string {
+	code, _, known := phoneCode(strings.TrimPrefix(e164, "+"))
+	if !known {
+		return ""
+	}
+	if r, ok := primaryRegion[code]; ok {
+		return r
+	}
+	for region, r := range phoneRegions {
+		if r.code == code {
+			return region
+		}
+	}
+	return ""
+}
+
+// phoneCode finds the known calling code digits start with. Calling codes
+// are prefix-free, so at most one matches.
+func phoneCode(digits string) (string, phoneRegion, bool) {
+	for n := 1; n <= 3 && n <= len(digits); n++ {
+		for _, r := range phoneRegions {
+			if r.code == digits[:n] {
+				return r.code, r, true
+			}
+		}
+	}
+	return "", phoneRegion{}, false
+}
+
+// PhoneQuery returns a search query matching users with the phone number
+// raw, normalized like stored numbers.
+func PhoneQuery(raw, region string) (Query, error) {
+	e164, err := NormalizePhone(raw, region)
+	if err != nil {
+		return "", err
+	}
+	return Field("phone").Eq(e164), nil
+}
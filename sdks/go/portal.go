@@ -0,0 +1,40 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// PortalIntent selects what the self-serve admin portal opens to.
+type PortalIntent string
+
+const (
+	PortalIntentSSO                PortalIntent = "sso"
+	PortalIntentDirectorySync      PortalIntent = "directory_sync"
+	PortalIntentDomainVerification PortalIntent = "domain_verification"
+	PortalIntentAuditLogs          PortalIntent = "audit_logs"
+)
+
+// PortalSession is a short-lived link into the admin portal.
+type PortalSession struct {
+	// URL opens the portal; embed it in an iframe or redirect to it.
+	URL string `json:"url"`
+	// Token authenticates the portal session for custom embeds.
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PortalSessionOptions configures CreateSession.
+type PortalSessionOptions struct {
+	// ReturnURL is where the portal's "done" button sends the user.
+	ReturnURL string
+}
+
+// PortalService creates sessions for the self-serve admin portal.
+type PortalService struct{}
+
+// CreateSession creates a portal session letting an organization's admins
+// configure intent themselves.
+func (s *PortalService) CreateSession(ctx context.Context, orgID string, intent PortalIntent, opts *PortalSessionOptions) (*PortalSession, error) {
+	return nil, ErrNotImplemented
+}
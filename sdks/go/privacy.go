@@ -0,0 +1,55 @@
+package authvital
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ErasureScope is a category of user data removed by EraseUser.
+type ErasureScope string
+
+const (
+	ErasureSessions ErasureScope = "sessions"
+	ErasureTokens   ErasureScope = "tokens"
+	ErasureLogs     ErasureScope = "logs"
+	ErasureProfile  ErasureScope = "profile"
+)
+
+// EraseOptions configures EraseUser.
+type EraseOptions struct {
+	// Scopes limits erasure to these categories. Empty erases everything.
+	Scopes []ErasureScope
+	// Reason is recorded on the receipt, e.g. a ticket reference.
+	Reason string
+	// RetainAuditTrail keeps pseudonymized audit log entries where the law
+	// requires them.
+	RetainAuditTrail bool
+}
+
+// ErasureReceipt proves a user's data was erased.
+type ErasureReceipt struct {
+	ID       string         `json:"id"`
+	UserID   string         `json:"userId"`
+	Scopes   []ErasureScope `json:"scopes"`
+	Reason   string         `json:"reason,omitempty"`
+	ErasedAt time.Time      `json:"erasedAt"`
+	// Token is the receipt as a JWT signed by the tenant's keys. Keep it as
+	// evidence; it can be checked later with Verifier.Verify.
+	Token string `json:"token"`
+}
+
+// PrivacyService fulfils data subject requests.
+type PrivacyService struct{}
+
+// ExportUserData downloads everything AuthVital holds about a user as a
+// ZIP bundle. The caller must close the returned reader.
+func (s *PrivacyService) ExportUserData(ctx context.Context, userID string) (io.ReadCloser, error) {
+	return nil, ErrNotImplemented
+}
+
+// EraseUser irreversibly erases a user's sessions, tokens, logs, and
+// profile and returns a signed receipt.
+func (s *PrivacyService) EraseUser(ctx context.Context, userID string, opts *EraseOptions) (*ErasureReceipt, error) {
+	return nil, ErrNotImplemented
+}
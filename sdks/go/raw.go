@@ -0,0 +1,48 @@
+package authvital
+
+import (
+	"context"
+	"net/http"
+)
+
+// RawResponse is the undecoded HTTP response of an API call.
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the complete response body, including fields the SDK does
+	// not model yet.
+	Body []byte
+}
+
+// WithRawResponse stores the call's HTTP response in dst, alongside the
+// decoded result. It is filled in for error responses too.
+func WithRawResponse(dst *RawResponse) CallOption {
+	return func(o *CallOptions) { o.Raw = dst }
+}
+
+type callOptionsKey struct{}
+
+// ContextWithCallOptions returns a copy of ctx applying opts to every
+// API call made with it. This lets call options reach service methods,
+// which take only a context:
+//
+//	var raw authvital.RawResponse
+//	ctx := authvital.ContextWithCallOptions(ctx, authvital.WithRawResponse(&raw))
+//	user, err := client.Users.Get(ctx, id, nil)
+//	// raw.Header, raw.Body
+func ContextWithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	if prev, ok := ctx.Value(callOptionsKey{}).([]CallOption); ok {
+		opts = append(append([]CallOption(nil), prev...), opts...)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, opts)
+}
+
+// callOptionsFrom applies the options set by ContextWithCallOptions.
+func callOptionsFrom(ctx context.Context) *CallOptions {
+	o := &CallOptions{}
+	opts, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,100 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// RecoveryMethod is a way to prove ownership of an account during recovery.
+type RecoveryMethod string
+
+const (
+	// RecoveryEmail sends a one-time code to the account's email address.
+	RecoveryEmail RecoveryMethod = "email"
+	// RecoveryCode accepts one of the user's MFA recovery codes.
+	RecoveryCode RecoveryMethod = "recovery_code"
+	// RecoveryAdminApproval waits for an administrator to approve.
+	RecoveryAdminApproval RecoveryMethod = "admin_approval"
+)
+
+// RecoveryStatus is the progress of a recovery attempt.
+type RecoveryStatus string
+
+const (
+	RecoveryPendingVerification RecoveryStatus = "pending_verification"
+	RecoveryPendingApproval     RecoveryStatus = "pending_approval"
+	// RecoveryVerified means Complete may be called.
+	RecoveryVerified  RecoveryStatus = "verified"
+	RecoveryCompleted RecoveryStatus = "completed"
+	RecoveryDenied    RecoveryStatus = "denied"
+	RecoveryExpired   RecoveryStatus = "expired"
+)
+
+// RecoveryAttempt is an account recovery in progress.
+type RecoveryAttempt struct {
+	ID string `json:"id"`
+	// Methods are those available for this account, in the order the
+	// tenant prefers them. Start returns them even for unknown accounts,
+	// so recovery cannot be used to discover which accounts exist.
+	Methods []RecoveryMethod `json:"methods"`
+	Method  RecoveryMethod   `json:"method,omitempty"`
+	Status  RecoveryStatus   `json:"status"`
+	// Risk is the assessment of the request that started the attempt.
+	// High-risk attempts may be restricted to admin approval.
+	Risk      *RiskAssessment `json:"risk,omitempty"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// RecoveryStartOptions configure Recovery.Start.
+type RecoveryStartOptions struct {
+	// Method starts verification immediately, such as sending the email.
+	Method RecoveryMethod
+	// Context describes the requesting client for risk evaluation.
+	Context *RiskContext
+}
+
+// RecoveryCompletion is what to change when completing recovery.
+type RecoveryCompletion struct {
+	NewPassword string `json:"newPassword,omitempty"`
+	// ResetMFA removes the user's second factors so they can enroll again.
+	ResetMFA bool `json:"resetMfa,omitempty"`
+	// RevokeSessions signs the account out everywhere. Defaults to true on
+	// the server when omitted.
+	RevokeSessions *bool `json:"revokeSessions,omitempty"`
+}
+
+// RecoveryService runs guided account recovery.
+type RecoveryService struct{}
+
+// Start begins recovering the account identified by an email address or
+// username.
+func (s *RecoveryService) Start(ctx context.Context, identifier string, opts *RecoveryStartOptions) (*RecoveryAttempt, error) {
+	return nil, ErrNotImplemented
+}
+
+// Verify submits proof for method: the emailed code or a recovery code.
+// For RecoveryAdminApproval, secret is ignored and the attempt moves to
+// RecoveryPendingApproval.
+func (s *RecoveryService) Verify(ctx context.Context, attemptID string, method RecoveryMethod, secret string) (*RecoveryAttempt, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get returns an attempt, for polling an admin approval.
+func (s *RecoveryService) Get(ctx context.Context, attemptID string) (*RecoveryAttempt, error) {
+	return nil, ErrNotImplemented
+}
+
+// Complete applies c to a verified attempt.
+func (s *RecoveryService) Complete(ctx context.Context, attemptID string, c *RecoveryCompletion) (*RecoveryAttempt, error) {
+	return nil, ErrNotImplemented
+}
+
+// Approve approves an attempt pending admin approval.
+func (s *RecoveryService) Approve(ctx context.Context, attemptID, reason string) (*RecoveryAttempt, error) {
+	return nil, ErrNotImplemented
+}
+
+// Deny rejects an attempt pending admin approval.
+func (s *RecoveryService) Deny(ctx context.Context, attemptID, reason string) (*RecoveryAttempt, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,49 @@
+package authvital
+
+import (
+	"context"
+	"fmt"
+)
+
+// Region is a data residency region.
+type Region string
+
+const (
+	RegionEU Region = "eu"
+	RegionUS Region = "us"
+	RegionAP Region = "ap"
+)
+
+// WithRegion pins the client to a region. Requests for organizations
+// resident elsewhere fail with a *ResidencyError instead of being routed
+// across regions.
+func WithRegion(region Region) Option {
+	return func(c *Client) {}
+}
+
+// WithRegionHosts sets the API host of each regional cluster. Requests are
+// routed to the host of the target organization's region, discovered with
+// Organizations.GetRegion and cached.
+func WithRegionHosts(hosts map[Region]string) Option {
+	return func(c *Client) {}
+}
+
+// ResidencyError is returned when a request would read or write an
+// organization's data outside its residency region.
+type ResidencyError struct {
+	OrganizationID string
+	// Resident is where the organization's data must stay.
+	Resident Region
+	// Client is the region the client is pinned to.
+	Client Region
+}
+
+func (e *ResidencyError) Error() string {
+	return fmt.Sprintf("authvital: organization %s is resident in %s; client is pinned to %s", e.OrganizationID, e.Resident, e.Client)
+}
+
+// GetRegion returns the residency region of an organization. It is served
+// by every regional cluster.
+func (s *OrganizationsService) GetRegion(ctx context.Context, orgID string) (Region, error) {
+	return "", ErrNotImplemented
+}
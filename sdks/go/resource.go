@@ -0,0 +1,42 @@
+package authvital
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// validateResources checks resource indicators: each must be an absolute
+// URI without a fragment (RFC 8707, section 2).
+func validateResources(resources []string) error {
+	for _, r := range resources {
+		u, err := url.Parse(r)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return fmt.Errorf("authvital: resource %q must be an absolute URI without a fragment", r)
+		}
+	}
+	return nil
+}
+
+// HasAudience reports whether aud is one of the token's audiences.
+func (c Claims) HasAudience(aud string) bool { return contains(c.Audience(), aud) }
+
+// WithStrictAudience rejects tokens carrying any audience the issuer's
+// configuration does not accept, not just tokens lacking an accepted one.
+// With resource indicators each access token should name only the API it
+// is for, so a token valid at several APIs is usually a misconfiguration.
+func WithStrictAudience() VerifierOption {
+	return func(v *Verifier) { v.strictAudience = true }
+}
+
+// strictAudienceMatches reports whether every audience in got is accepted.
+func strictAudienceMatches(got, accepted []string) bool {
+	if len(got) == 0 {
+		return false
+	}
+	for _, g := range got {
+		if !contains(accepted, g) {
+			return false
+		}
+	}
+	return true
+}
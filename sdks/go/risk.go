@@ -0,0 +1,67 @@
+package authvital
+
+import "context"
+
+// RiskSignal is a factor that contributed to a risk score.
+type RiskSignal string
+
+const (
+	// RiskSignalImpossibleTravel means the user appeared in two distant places too quickly.
+	RiskSignalImpossibleTravel RiskSignal = "impossible_travel"
+	// RiskSignalTOR means the request came from a TOR exit node.
+	RiskSignalTOR RiskSignal = "tor"
+	// RiskSignalAnonymousProxy means the request came from a known VPN or proxy.
+	RiskSignalAnonymousProxy RiskSignal = "anonymous_proxy"
+	// RiskSignalNewDevice means the device has not been seen for this user.
+	RiskSignalNewDevice RiskSignal = "new_device"
+	// RiskSignalNewCountry means the user has not signed in from this country before.
+	RiskSignalNewCountry RiskSignal = "new_country"
+	// RiskSignalBreachedCredential means the user's password is known to be breached.
+	RiskSignalBreachedCredential RiskSignal = "breached_credential"
+)
+
+// RiskLevel buckets a risk score.
+type RiskLevel string
+
+const (
+	// RiskLevelLow needs no extra verification.
+	RiskLevelLow RiskLevel = "low"
+	// RiskLevelMedium warrants step-up verification such as MFA.
+	RiskLevelMedium RiskLevel = "medium"
+	// RiskLevelHigh warrants blocking the attempt or requiring strong MFA.
+	RiskLevelHigh RiskLevel = "high"
+)
+
+// RiskContext describes the request being evaluated.
+type RiskContext struct {
+	IP        string `json:"ip"`
+	DeviceID  string `json:"deviceId,omitempty"`
+	UserID    string `json:"userId,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// RiskAssessment is the result of a risk evaluation.
+type RiskAssessment struct {
+	// Score ranges from 0 (no risk) to 100.
+	Score   int          `json:"score"`
+	Level   RiskLevel    `json:"level"`
+	Signals []RiskSignal `json:"signals"`
+}
+
+// Has reports whether the assessment includes signal.
+func (a *RiskAssessment) Has(signal RiskSignal) bool {
+	for _, s := range a.Signals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// RiskService evaluates request risk.
+type RiskService struct{}
+
+// Evaluate scores the risk of rc, for gating sensitive operations.
+func (s *RiskService) Evaluate(ctx context.Context, rc RiskContext) (*RiskAssessment, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,29 @@
+package authvital
+
+import "context"
+
+// Role is a named set of permissions.
+type Role struct {
+	ID          string   `json:"id"`
+	Slug        string   `json:"slug"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+	// OrganizationID is set for custom roles defined by an organization,
+	// and empty for global roles.
+	OrganizationID string `json:"organizationId,omitempty"`
+}
+
+// RolesService reads global roles. Roles change rarely; combine it with
+// WithCache when listing them on hot paths.
+type RolesService struct{}
+
+// List lists roles.
+func (s *RolesService) List(ctx context.Context) ([]Role, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get retrieves a role by slug.
+func (s *RolesService) Get(ctx context.Context, slug string) (*Role, error) {
+	return nil, ErrNotImplemented
+}
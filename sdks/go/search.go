@@ -0,0 +1,156 @@
+package authvital
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query is a search expression in the AuthVital query language, e.g.
+//
+//	email:*@acme.com AND created_at>2024-01-01
+//
+// Terms are field:value (equality, with * wildcards), field>value,
+// field>=value, field<value, and field<=value, combined with AND, OR, NOT,
+// and parentheses. Values containing spaces or operators are double-quoted.
+// Times are RFC 3339, or relative to evaluation such as now-90d; see Ago.
+//
+// Queries can be written as string literals or built with Field, And, Or,
+// and Not, which handle quoting.
+type Query string
+
+// SearchField is a field reference for building queries.
+type SearchField string
+
+// Field starts a query term on name.
+func Field(name string) SearchField { return SearchField(name) }
+
+// Eq matches values equal to v.
+func (f SearchField) Eq(v any) Query { return f.term(":", quoteValue(v, false)) }
+
+// Like matches values against pattern, where * matches any run of characters.
+func (f SearchField) Like(pattern string) Query { return f.term(":", quoteValue(pattern, true)) }
+
+// Gt matches values greater than v.
+func (f SearchField) Gt(v any) Query { return f.term(">", quoteValue(v, false)) }
+
+// Gte matches values greater than or equal to v.
+func (f SearchField) Gte(v any) Query { return f.term(">=", quoteValue(v, false)) }
+
+// Lt matches values less than v.
+func (f SearchField) Lt(v any) Query { return f.term("<", quoteValue(v, false)) }
+
+// Lte matches values less than or equal to v.
+func (f SearchField) Lte(v any) Query { return f.term("<=", quoteValue(v, false)) }
+
+// Exists matches records where the field is set.
+func (f SearchField) Exists() Query { return f.term(":", "*") }
+
+func (f SearchField) term(op, v string) Query { return Query(string(f) + op + v) }
+
+// And matches records matching all of qs.
+func And(qs ...Query) Query { return join(" AND ", qs) }
+
+// Or matches records matching any of qs.
+func Or(qs ...Query) Query { return join(" OR ", qs) }
+
+// Not matches records not matching q.
+func Not(q Query) Query { return "NOT " + group(q) }
+
+// And is shorthand for And(q, other...).
+func (q Query) And(other ...Query) Query { return And(append([]Query{q}, other...)...) }
+
+// Or is shorthand for Or(q, other...).
+func (q Query) Or(other ...Query) Query { return Or(append([]Query{q}, other...)...) }
+
+func join(sep string, qs []Query) Query {
+	parts := make([]string, 0, len(qs))
+	for _, q := range qs {
+		if q != "" {
+			parts = append(parts, string(group(q)))
+		}
+	}
+	return Query(strings.Join(parts, sep))
+}
+
+// group parenthesizes compound queries so they combine unambiguously.
+func group(q Query) Query {
+	if isCompound(string(q)) {
+		return "(" + q + ")"
+	}
+	return q
+}
+
+// isCompound reports whether q has whitespace outside quoted values.
+func isCompound(q string) bool {
+	quoted, escaped := false, false
+	for _, r := range q {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t'):
+			return true
+		}
+	}
+	return false
+}
+
+func quoteValue(v any, wildcard bool) string {
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(x)
+	case int, int32, int64, uint, uint32, uint64, float32, float64:
+		return fmt.Sprint(x)
+	default:
+		s = fmt.Sprint(x)
+	}
+	special := " \t\"():<>=\\"
+	if !wildcard {
+		special += "*"
+	}
+	if s != "" && !strings.ContainsAny(s, special) && !isKeyword(s) {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "AND", "OR", "NOT":
+		return true
+	}
+	return false
+}
+
+// SortOrder is the direction of a sort.
+type SortOrder string
+
+const (
+	Ascending  SortOrder = "asc"
+	Descending SortOrder = "desc"
+)
+
+// Sort orders search results by a field.
+type Sort struct {
+	Field string
+	Order SortOrder
+}
+
+// SearchOptions controls pagination and ordering of search calls.
+type SearchOptions struct {
+	ListOptions
+	// Sort orders results; earlier entries take precedence. Defaults to
+	// relevance, then created_at descending.
+	Sort []Sort
+}
@@ -0,0 +1,79 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSeatLimitReached is matched by errors.Is for a *SeatLimitError.
+var ErrSeatLimitReached = errors.New("authvital: organization seat limit reached")
+
+// SeatLimitError is returned when adding a member or inviting a user would
+// exceed the organization's seats.
+type SeatLimitError struct {
+	APIError
+	OrganizationID string
+	Seats          Seats
+}
+
+func (e *SeatLimitError) Error() string {
+	return fmt.Sprintf("authvital: organization %s has used %d of %d seats", e.OrganizationID, e.Seats.Used+e.Seats.Pending, e.Seats.Limit)
+}
+
+// Is reports whether target is ErrSeatLimitReached.
+func (e *SeatLimitError) Is(target error) bool { return target == ErrSeatLimitReached }
+
+// Seats is an organization's seat usage.
+type Seats struct {
+	// Used counts active members.
+	Used int `json:"used"`
+	// Pending counts open invitations, which hold a seat.
+	Pending int `json:"pending"`
+	// Limit is zero when unlimited.
+	Limit int `json:"limit"`
+}
+
+// Available returns the number of free seats, or -1 when unlimited.
+func (s Seats) Available() int {
+	if s.Limit == 0 {
+		return -1
+	}
+	if n := s.Limit - s.Used - s.Pending; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Invitation invites someone to join an organization.
+type Invitation struct {
+	ID        string    `json:"id,omitempty"`
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// GetSeats returns the organization's seat usage and limit.
+func (s *OrganizationsService) GetSeats(ctx context.Context, orgID string) (*Seats, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetSeatLimit changes the organization's seat limit, typically from a
+// billing webhook. Zero removes the limit. Lowering it below current use
+// blocks new members without removing any.
+func (s *OrganizationsService) SetSeatLimit(ctx context.Context, orgID string, limit int) (*Seats, error) {
+	return nil, ErrNotImplemented
+}
+
+// Invite sends an invitation. It returns a *SeatLimitError when no seat
+// is free.
+func (s *OrganizationsService) Invite(ctx context.Context, orgID string, inv *Invitation) (*Invitation, error) {
+	return nil, ErrNotImplemented
+}
+
+// AddMember adds an existing user to the organization with roles. It
+// returns a *SeatLimitError when no seat is free.
+func (s *OrganizationsService) AddMember(ctx context.Context, orgID, userID string, roles ...string) error {
+	return ErrNotImplemented
+}
@@ -0,0 +1,62 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// BreachSource identifies where a breached credential was detected.
+type BreachSource string
+
+const (
+	// BreachSourcePasswordSet is a breached password rejected on signup, reset, or change.
+	BreachSourcePasswordSet BreachSource = "password_set"
+	// BreachSourceSignIn is a breached password used in a sign-in attempt.
+	BreachSourceSignIn BreachSource = "sign_in"
+	// BreachSourceCredentialStuffing is a sign-in attempt matching a credential-stuffing pattern.
+	BreachSourceCredentialStuffing BreachSource = "credential_stuffing"
+)
+
+// BreachEvent records a breached or stuffed credential observed for a user.
+type BreachEvent struct {
+	ID         string       `json:"id"`
+	UserID     string       `json:"userId"`
+	Email      string       `json:"email"`
+	Source     BreachSource `json:"source"`
+	IP         string       `json:"ip,omitempty"`
+	OccurredAt time.Time    `json:"occurredAt"`
+}
+
+// BreachEventListOptions filters ListBreachEvents.
+type BreachEventListOptions struct {
+	ListOptions
+	UserID string
+	Since  time.Time
+}
+
+// SecurityService exposes security signals detected by the platform.
+type SecurityService struct{}
+
+// ListBreachEvents lists breached-credential events, newest first.
+func (s *SecurityService) ListBreachEvents(ctx context.Context, opts *BreachEventListOptions) (*List[BreachEvent], error) {
+	return nil, ErrNotImplemented
+}
+
+// SecurityNotifications controls the security emails AuthVital sends for
+// credential lifecycle events.
+type SecurityNotifications struct {
+	// Disabled lists events for which AuthVital sends no email, typically
+	// because you send your own from the webhook; see
+	// CredentialLifecycleEvents.
+	Disabled []EventType `json:"disabled"`
+}
+
+// GetNotifications returns the security email settings.
+func (s *SecurityService) GetNotifications(ctx context.Context) (*SecurityNotifications, error) {
+	return nil, ErrNotImplemented
+}
+
+// UpdateNotifications replaces the security email settings.
+func (s *SecurityService) UpdateNotifications(ctx context.Context, n *SecurityNotifications) (*SecurityNotifications, error) {
+	return nil, ErrNotImplemented
+}
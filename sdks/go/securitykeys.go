@@ -0,0 +1,50 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// SecurityKey is a FIDO2 authenticator registered to a user, such as a
+// hardware key or a platform passkey.
+type SecurityKey struct {
+	ID string `json:"id"`
+	// Name is user-chosen, such as "Blue YubiKey".
+	Name string `json:"name"`
+	// AAGUID identifies the authenticator model. It is all zeros for
+	// authenticators that do not disclose it.
+	AAGUID string `json:"aaguid"`
+	// Authenticator describes the model, from the FIDO Metadata Service,
+	// when the AAGUID is known.
+	Authenticator *AuthenticatorMetadata `json:"authenticator,omitempty"`
+	// Transports are the WebAuthn transports, such as "usb" and "nfc".
+	Transports   []string   `json:"transports,omitempty"`
+	Discoverable bool       `json:"discoverable"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// AuthenticatorMetadata describes an authenticator model.
+type AuthenticatorMetadata struct {
+	Description string `json:"description"`
+	// Icon is a data: URL.
+	Icon string `json:"icon,omitempty"`
+	// CertificationLevel is the FIDO certification, such as "FIDO_CERTIFIED_L1".
+	CertificationLevel string `json:"certificationLevel,omitempty"`
+}
+
+// ListSecurityKeys returns the user's registered security keys.
+func (s *UsersService) ListSecurityKeys(ctx context.Context, userID string) ([]SecurityKey, error) {
+	return nil, ErrNotImplemented
+}
+
+// RenameSecurityKey changes a security key's display name.
+func (s *UsersService) RenameSecurityKey(ctx context.Context, userID, keyID, name string) (*SecurityKey, error) {
+	return nil, ErrNotImplemented
+}
+
+// DeleteSecurityKey removes a security key. The user can no longer sign in
+// or complete MFA with it.
+func (s *UsersService) DeleteSecurityKey(ctx context.Context, userID, keyID string) error {
+	return ErrNotImplemented
+}
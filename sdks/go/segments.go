@@ -0,0 +1,87 @@
+package authvital
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Ago is a time relative to when a query is evaluated, for queries that
+// must keep their meaning over time, such as segments:
+//
+//	Field("last_login_at").Lt(Ago(90 * 24 * time.Hour))
+//
+// renders as last_login_at<now-90d.
+type Ago time.Duration
+
+func (a Ago) String() string {
+	d := time.Duration(a)
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("now-%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("now-%dh", d/time.Hour)
+	}
+	return fmt.Sprintf("now-%dm", d/time.Minute)
+}
+
+// Segment is a named, saved user query, such as users inactive for 90
+// days without MFA. Membership is kept up to date as users change.
+type Segment struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Query selects the members. Use Ago for relative times; absolute
+	// times would freeze the segment.
+	Query Query `json:"query"`
+	// Notify sends EventSegmentEntered and EventSegmentLeft as membership
+	// changes, to drive lifecycle campaigns.
+	Notify bool `json:"notify"`
+	// MemberCount is as of EvaluatedAt.
+	MemberCount int64     `json:"memberCount"`
+	EvaluatedAt time.Time `json:"evaluatedAt,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+}
+
+// SegmentsService manages user segments.
+type SegmentsService struct{}
+
+// List lists segments.
+func (s *SegmentsService) List(ctx context.Context, opts *ListOptions) (*List[Segment], error) {
+	return nil, ErrNotImplemented
+}
+
+// Get returns a segment.
+func (s *SegmentsService) Get(ctx context.Context, id string) (*Segment, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create saves a segment. Its query is validated by the server and its
+// membership computed in the background.
+func (s *SegmentsService) Create(ctx context.Context, seg *Segment) (*Segment, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces a segment's name, query or notification setting.
+// Changing the query sends membership events for the difference when
+// Notify is set.
+func (s *SegmentsService) Update(ctx context.Context, id string, seg *Segment) (*Segment, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete removes a segment. No membership events are sent.
+func (s *SegmentsService) Delete(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// Members lists the segment's current members.
+func (s *SegmentsService) Members(ctx context.Context, id string, opts *ListOptions) (*List[User], error) {
+	return nil, ErrNotImplemented
+}
+
+// Preview lists the users q would select, to check a query before saving
+// it as a segment.
+func (s *SegmentsService) Preview(ctx context.Context, q Query, opts *ListOptions) (*List[User], error) {
+	return nil, ErrNotImplemented
+}
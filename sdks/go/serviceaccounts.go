@@ -0,0 +1,106 @@
+package authvital
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ServiceAccount is a machine principal belonging to an organization. It
+// holds roles like a member and authenticates with keys instead of a
+// password.
+type ServiceAccount struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organizationId"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	// Email is the generated address identifying the account in audit logs.
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ServiceAccountKeyInfo describes a key of a service account. The private
+// key is only returned once, by CreateKey.
+type ServiceAccountKeyInfo struct {
+	ID         string     `json:"id"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ServiceAccountsService manages service accounts and their keys.
+type ServiceAccountsService struct{}
+
+// List lists an organization's service accounts.
+func (s *ServiceAccountsService) List(ctx context.Context, orgID string, opts *ListOptions) (*List[ServiceAccount], error) {
+	return nil, ErrNotImplemented
+}
+
+// Get retrieves a service account by ID.
+func (s *ServiceAccountsService) Get(ctx context.Context, orgID, id string) (*ServiceAccount, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create creates a service account with the given roles. It counts
+// against the organization's seats only if the plan says so.
+func (s *ServiceAccountsService) Create(ctx context.Context, orgID string, sa *ServiceAccount) (*ServiceAccount, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update changes a service account's name and description.
+func (s *ServiceAccountsService) Update(ctx context.Context, orgID, id string, sa *ServiceAccount) (*ServiceAccount, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete deletes a service account and revokes all of its keys and
+// tokens.
+func (s *ServiceAccountsService) Delete(ctx context.Context, orgID, id string) error {
+	return ErrNotImplemented
+}
+
+// SetRoles replaces a service account's roles.
+func (s *ServiceAccountsService) SetRoles(ctx context.Context, orgID, id string, roles []string) error {
+	return ErrNotImplemented
+}
+
+// CreateKey creates a key for a service account. The returned key file
+// contains the private key and cannot be retrieved again; pass it to
+// ParseServiceAccountKey or save it for WithServiceAccountKeyFile.
+func (s *ServiceAccountsService) CreateKey(ctx context.Context, orgID, id string, expiresAt *time.Time) (*ServiceAccountKey, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListKeys lists a service account's keys.
+func (s *ServiceAccountsService) ListKeys(ctx context.Context, orgID, id string) ([]ServiceAccountKeyInfo, error) {
+	return nil, ErrNotImplemented
+}
+
+// DeleteKey revokes a key. Tokens already issued with it remain valid
+// until they expire.
+func (s *ServiceAccountsService) DeleteKey(ctx context.Context, orgID, id, keyID string) error {
+	return ErrNotImplemented
+}
+
+// IsServiceAccount reports whether the token was issued to a machine
+// principal: a service account, or an application using the client
+// credentials grant.
+func (c Claims) IsServiceAccount() bool {
+	return c.str("subject_type") == "service_account" || c.str("token_type") == "m2m"
+}
+
+// ServiceAccountID returns the machine principal the token was issued to,
+// or "" for user tokens. For client credentials tokens it is the
+// application's client ID.
+func (c Claims) ServiceAccountID() string {
+	switch {
+	case c.str("subject_type") == "service_account":
+		return c.Subject()
+	case c.str("token_type") == "m2m":
+		if id := c.str("client_id"); id != "" {
+			return id
+		}
+		return strings.TrimPrefix(c.Subject(), "app:")
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package authvital
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSessionEvicted is returned by Verify for a token whose session was
+// revoked or evicted by the concurrent session limit. It wraps
+// ErrTokenExpired, so callers treat it like an expired token.
+var ErrSessionEvicted = fmt.Errorf("%w: session has ended", ErrTokenExpired)
+
+// SessionLimitAction is what happens when a user at the limit signs in again.
+type SessionLimitAction string
+
+const (
+	// EvictOldest ends the least recently active session.
+	EvictOldest SessionLimitAction = "evict_oldest"
+	// DenyNew refuses the new sign-in.
+	DenyNew SessionLimitAction = "deny_new"
+)
+
+// SessionLimit caps concurrent sessions per user.
+type SessionLimit struct {
+	// MaxConcurrent is the maximum number of concurrent sessions. Zero
+	// means unlimited.
+	MaxConcurrent int                `json:"maxConcurrent"`
+	OnLimit       SessionLimitAction `json:"onLimit"`
+}
+
+// UserSession is one of a user's active sessions.
+type UserSession struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	DeviceID     string    `json:"deviceId,omitempty"`
+	IP           string    `json:"ip,omitempty"`
+	UserAgent    string    `json:"userAgent,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	LastActiveAt time.Time `json:"lastActiveAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// SessionsService manages users' sessions and session limits.
+type SessionsService struct{}
+
+// GetLimit returns the tenant's concurrent session limit.
+func (s *SessionsService) GetLimit(ctx context.Context) (*SessionLimit, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetLimit sets the tenant's concurrent session limit.
+func (s *SessionsService) SetLimit(ctx context.Context, limit *SessionLimit) (*SessionLimit, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetUserLimit overrides the limit for one user. A nil limit removes the
+// override.
+func (s *SessionsService) SetUserLimit(ctx context.Context, userID string, limit *SessionLimit) error {
+	return ErrNotImplemented
+}
+
+// List returns the user's active sessions, most recently active first.
+func (s *SessionsService) List(ctx context.Context, userID string) ([]UserSession, error) {
+	return nil, ErrNotImplemented
+}
+
+// Revoke ends one of the user's sessions.
+func (s *SessionsService) Revoke(ctx context.Context, userID, sessionID string) error {
+	return ErrNotImplemented
+}
+
+// SessionRevocations reports sessions that have ended before their tokens
+// expire. Verifiers configured with WithSessionRevocations reject tokens
+// of those sessions with ErrSessionEvicted.
+type SessionRevocations interface {
+	Revoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// WithSessionRevocations checks the sid claim of every token against r.
+func WithSessionRevocations(r SessionRevocations) VerifierOption {
+	return func(v *Verifier) { v.revocations = r }
+}
+
+// MemorySessionRevocations is an in-process SessionRevocations, typically
+// fed from EventSessionEvicted webhooks. Entries are dropped once the
+// session's tokens would have expired anyway.
+type MemorySessionRevocations struct {
+	// Clock expires entries; it defaults to the system clock.
+	Clock Clock
+
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemorySessionRevocations returns an empty MemorySessionRevocations.
+func NewMemorySessionRevocations() *MemorySessionRevocations {
+	return &MemorySessionRevocations{revoked: map[string]time.Time{}}
+}
+
+// Add records sessionID as ended. until is when its last access token
+// expires; zero keeps the entry for a day.
+func (m *MemorySessionRevocations) Add(sessionID string, until time.Time) {
+	if until.IsZero() {
+		until = now(m.Clock).Add(24 * time.Hour)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := now(m.Clock)
+	for id, exp := range m.revoked {
+		if now.After(exp) {
+			delete(m.revoked, id)
+		}
+	}
+	m.revoked[sessionID] = until
+}
+
+// Revoked implements SessionRevocations.
+func (m *MemorySessionRevocations) Revoked(ctx context.Context, sessionID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exp, ok := m.revoked[sessionID]
+	return ok && now(m.Clock).Before(exp), nil
+}
+
+// SessionID returns the sid claim.
+func (c Claims) SessionID() string { return c.str("sid") }
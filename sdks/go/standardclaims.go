@@ -0,0 +1,95 @@
+package authvital
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// StandardClaims are the registered and common AuthVital claims of a
+// token, decoded by Verifier.VerifyStandard without a map.
+type StandardClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  StringList      `json:"aud"`
+	ExpiresAt NumericDate     `json:"exp"`
+	NotBefore NumericDate     `json:"nbf"`
+	IssuedAt  NumericDate     `json:"iat"`
+	ID        string          `json:"jti"`
+	SessionID string          `json:"sid"`
+	ClientID  string          `json:"client_id"`
+	Scope     string          `json:"scope"`
+	Email     string          `json:"email"`
+	TenantID  string          `json:"tenant_id"`
+	Act       json.RawMessage `json:"act"`
+
+	raw []byte
+}
+
+// Decode unmarshals the full token payload into v, for claims
+// StandardClaims does not have. v is typically a struct with just the
+// claims the caller needs.
+func (c *StandardClaims) Decode(v any) error {
+	return json.Unmarshal(c.raw, v)
+}
+
+// Claims decodes the full payload as a Claims map.
+func (c *StandardClaims) Claims() (Claims, error) {
+	var m Claims
+	if err := json.Unmarshal(c.raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// standard extracts the StandardClaims of a decoded map.
+func (c Claims) standard() *StandardClaims {
+	std := &StandardClaims{
+		Subject:   c.Subject(),
+		Issuer:    c.Issuer(),
+		Audience:  c.Audience(),
+		ID:        c.str("jti"),
+		SessionID: c.SessionID(),
+		ClientID:  c.str("client_id"),
+		Scope:     c.str("scope"),
+		Email:     c.Email(),
+		TenantID:  c.TenantID(),
+	}
+	std.ExpiresAt = c.numericDate("exp")
+	std.NotBefore = c.numericDate("nbf")
+	std.IssuedAt = c.numericDate("iat")
+	return std
+}
+
+func (c Claims) numericDate(name string) NumericDate {
+	t, ok := c.time(name)
+	if !ok {
+		return 0
+	}
+	return NumericDate(float64(t.UnixNano()) / 1e9)
+}
+
+// StringList is a claim that may be a single string or an array.
+type StringList []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *StringList) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*l = StringList{s}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(l))
+}
+
+// NumericDate is a JWT time in seconds since the epoch. Zero means absent.
+type NumericDate float64
+
+// Time converts d to a time.Time.
+func (d NumericDate) Time() time.Time {
+	whole, frac := math.Modf(float64(d))
+	return time.Unix(int64(whole), int64(frac*1e9))
+}
@@ -0,0 +1,131 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrTenantNotResolved is returned by a TenantResolver that cannot map a
+// request to a tenant.
+var ErrTenantNotResolved = errors.New("authvital: tenant could not be resolved")
+
+// Tenant identifies the AuthVital organization and environment a request
+// belongs to.
+type Tenant struct {
+	OrganizationID string
+	Environment    string
+}
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying t. Client calls made with
+// the returned context are scoped to t.
+func ContextWithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// TenantFromContext returns the tenant carried by ctx.
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(tenantKey{}).(Tenant)
+	return t, ok
+}
+
+// TenantResolver maps an incoming request to a tenant. It returns
+// ErrTenantNotResolved when the request carries no recognizable tenant.
+type TenantResolver func(r *http.Request) (Tenant, error)
+
+// HeaderTenantResolver reads the organization ID from header, e.g. "X-Tenant".
+func HeaderTenantResolver(header string) TenantResolver {
+	return func(r *http.Request) (Tenant, error) {
+		id := strings.TrimSpace(r.Header.Get(header))
+		if id == "" {
+			return Tenant{}, ErrTenantNotResolved
+		}
+		return Tenant{OrganizationID: id}, nil
+	}
+}
+
+// HostTenantResolver looks up the request's host, without port, in hosts.
+func HostTenantResolver(hosts map[string]Tenant) TenantResolver {
+	return func(r *http.Request) (Tenant, error) {
+		if t, ok := hosts[requestHost(r)]; ok {
+			return t, nil
+		}
+		return Tenant{}, ErrTenantNotResolved
+	}
+}
+
+// SubdomainTenantResolver maps "<sub>.<baseDomain>" to lookup(sub). lookup
+// returns false for unknown subdomains.
+func SubdomainTenantResolver(baseDomain string, lookup func(ctx context.Context, subdomain string) (Tenant, bool, error)) TenantResolver {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+	return func(r *http.Request) (Tenant, error) {
+		host := requestHost(r)
+		sub, ok := strings.CutSuffix(host, suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return Tenant{}, ErrTenantNotResolved
+		}
+		t, ok, err := lookup(r.Context(), sub)
+		if err != nil {
+			return Tenant{}, err
+		}
+		if !ok {
+			return Tenant{}, ErrTenantNotResolved
+		}
+		return t, nil
+	}
+}
+
+// FirstTenantResolver tries each resolver in order, e.g. an explicit header
+// before the hostname.
+func FirstTenantResolver(resolvers ...TenantResolver) TenantResolver {
+	return func(r *http.Request) (Tenant, error) {
+		for _, resolve := range resolvers {
+			t, err := resolve(r)
+			if !errors.Is(err, ErrTenantNotResolved) {
+				return t, err
+			}
+		}
+		return Tenant{}, ErrTenantNotResolved
+	}
+}
+
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// TenantMiddleware resolves the tenant of each request and stores it in the
+// request context for ContextWithTenant-aware handlers and Client calls.
+// Requests whose tenant cannot be resolved get 404 Not Found; other resolver
+// errors get 500. Use TenantMiddlewareWithErrorHandler to customize this.
+func TenantMiddleware(resolve TenantResolver) func(http.Handler) http.Handler {
+	return TenantMiddlewareWithErrorHandler(resolve, func(w http.ResponseWriter, r *http.Request, err error) {
+		if errors.Is(err, ErrTenantNotResolved) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	})
+}
+
+// TenantMiddlewareWithErrorHandler is TenantMiddleware with a custom
+// response for requests whose tenant cannot be resolved.
+func TenantMiddlewareWithErrorHandler(resolve TenantResolver, onError func(http.ResponseWriter, *http.Request, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t, err := resolve(r)
+			if err != nil {
+				onError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ContextWithTenant(r.Context(), t)))
+		})
+	}
+}
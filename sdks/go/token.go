@@ -0,0 +1,166 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Token is a token endpoint response.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	// RecoveryCodesRemaining is set when MFA was completed with a recovery
+	// code, so the application can prompt the user to generate more.
+	RecoveryCodesRemaining *int `json:"recovery_codes_remaining,omitempty"`
+	// Expiry is computed from ExpiresIn when the response is received.
+	Expiry time.Time `json:"-"`
+}
+
+// TokenError is an OAuth error returned by the token endpoint.
+type TokenError struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+func (e *TokenError) Error() string {
+	if e.Description != "" {
+		return "authvital: token endpoint: " + e.Code + ": " + e.Description
+	}
+	return "authvital: token endpoint: " + e.Code
+}
+
+// ExchangeParams are the parameters for exchanging an authorization code.
+type ExchangeParams struct {
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	// Resource selects the API the access token is for (RFC 8707).
+	Resource []string
+	// Clock sets the token's Expiry; it defaults to the system clock.
+	Clock Clock
+}
+
+// ExchangeCode redeems an authorization code at host's token endpoint. hc
+// may be nil to use http.DefaultClient.
+func ExchangeCode(ctx context.Context, hc *http.Client, host string, p *ExchangeParams) (*Token, error) {
+	if p.Code == "" || p.CodeVerifier == "" {
+		return nil, errors.New("authvital: code and code verifier are required")
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {p.ClientID},
+		"code":          {p.Code},
+		"redirect_uri":  {p.RedirectURI},
+		"code_verifier": {p.CodeVerifier},
+	}
+	if p.ClientSecret != "" {
+		form.Set("client_secret", p.ClientSecret)
+	}
+	if err := addResources(form, p.Resource); err != nil {
+		return nil, err
+	}
+	return requestToken(ctx, hc, host, form, p.Clock)
+}
+
+// RefreshParams are the parameters for refreshing a token.
+type RefreshParams struct {
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	// Scope narrows the scope of the new tokens. Empty keeps it.
+	Scope string
+	// Resource selects the API the access token is for (RFC 8707).
+	Resource []string
+	// Clock sets the token's Expiry; it defaults to the system clock.
+	Clock Clock
+}
+
+// RefreshToken redeems a refresh token at host's token endpoint. hc may be
+// nil to use http.DefaultClient.
+func RefreshToken(ctx context.Context, hc *http.Client, host string, p *RefreshParams) (*Token, error) {
+	if p.RefreshToken == "" {
+		return nil, errors.New("authvital: refresh token is required")
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {p.ClientID},
+		"refresh_token": {p.RefreshToken},
+	}
+	if p.ClientSecret != "" {
+		form.Set("client_secret", p.ClientSecret)
+	}
+	if p.Scope != "" {
+		form.Set("scope", p.Scope)
+	}
+	if err := addResources(form, p.Resource); err != nil {
+		return nil, err
+	}
+	return requestToken(ctx, hc, host, form, p.Clock)
+}
+
+func addResources(form url.Values, resources []string) error {
+	if err := validateResources(resources); err != nil {
+		return err
+	}
+	for _, r := range resources {
+		form.Add("resource", r)
+	}
+	return nil
+}
+
+// requestToken posts form to host's token endpoint, setting the token's
+// Expiry from clock.
+func requestToken(ctx context.Context, hc *http.Client, host string, form url.Values, clock Clock) (*Token, error) {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	endpoint := strings.TrimRight(host, "/") + "/oauth/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		e := &TokenError{StatusCode: resp.StatusCode}
+		var body struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+			e.Code, e.Description = body.Error, body.Description
+		} else {
+			e.Code = resp.Status
+		}
+		return nil, e
+	}
+	var tok Token
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("authvital: decoding token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return nil, errors.New("authvital: token response has no access token")
+	}
+	if tok.ExpiresIn > 0 {
+		tok.Expiry = now(clock).Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	return &tok, nil
+}
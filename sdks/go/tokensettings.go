@@ -0,0 +1,93 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RefreshRotation controls whether refresh tokens are replaced on use.
+type RefreshRotation string
+
+const (
+	// RotateAlways issues a new refresh token on every refresh and revokes
+	// the whole family if an old one is reused.
+	RotateAlways RefreshRotation = "rotate"
+	// RotateNever keeps the refresh token until it expires.
+	RotateNever RefreshRotation = "reuse"
+)
+
+// Seconds is a duration sent to the API as a whole number of seconds,
+// such as an application's accessTokenTtl. Convert with
+// Seconds(time.Hour) and time.Duration(s).
+type Seconds time.Duration
+
+// MarshalJSON implements json.Marshaler, rounding to the nearest second.
+func (s Seconds) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(time.Duration(s).Round(time.Second)/time.Second), 10), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Seconds) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("authvital: duration in seconds: %w", err)
+	}
+	*s = Seconds(f * float64(time.Second))
+	return nil
+}
+
+func (s Seconds) String() string { return time.Duration(s).String() }
+
+// TokenSettings are an application's token lifetimes and refresh policy.
+type TokenSettings struct {
+	AccessTokenTTL Seconds `json:"accessTokenTtl"`
+	IDTokenTTL     Seconds `json:"idTokenTtl"`
+	// RefreshTokenTTL caps a refresh token family's age, however active
+	// the user is.
+	RefreshTokenTTL Seconds `json:"refreshTokenTtl"`
+	// RefreshTokenIdleTTL expires a refresh token not used for this long.
+	// Zero means refresh tokens only expire after RefreshTokenTTL.
+	RefreshTokenIdleTTL Seconds         `json:"refreshTokenIdleTtl,omitempty"`
+	RefreshRotation     RefreshRotation `json:"refreshRotation"`
+	// ReuseInterval tolerates reuse of a rotated refresh token for this
+	// long, for clients racing parallel refreshes.
+	ReuseInterval Seconds `json:"reuseInterval"`
+}
+
+// Diff describes how other differs from s, one line per setting, or nil if
+// they match. Promotion scripts can use it to assert that environments
+// agree.
+func (s *TokenSettings) Diff(other *TokenSettings) []string {
+	var diffs []string
+	check := func(name string, a, b any) {
+		if a != b {
+			diffs = append(diffs, fmt.Sprintf("%s: %v != %v", name, a, b))
+		}
+	}
+	check("accessTokenTtl", s.AccessTokenTTL, other.AccessTokenTTL)
+	check("idTokenTtl", s.IDTokenTTL, other.IDTokenTTL)
+	check("refreshTokenTtl", s.RefreshTokenTTL, other.RefreshTokenTTL)
+	check("refreshTokenIdleTtl", s.RefreshTokenIdleTTL, other.RefreshTokenIdleTTL)
+	check("refreshRotation", s.RefreshRotation, other.RefreshRotation)
+	check("reuseInterval", s.ReuseInterval, other.ReuseInterval)
+	return diffs
+}
+
+// TokenSettingsService reads and updates applications' token settings.
+type TokenSettingsService struct{}
+
+// Get returns the token settings of the application with clientID.
+func (s *TokenSettingsService) Get(ctx context.Context, clientID string) (*TokenSettings, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces the application's token settings.
+func (s *TokenSettingsService) Update(ctx context.Context, clientID string, settings *TokenSettings) (*TokenSettings, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,35 @@
+package authvital
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSecondsJSON(t *testing.T) {
+	b, err := json.Marshal(&TokenSettings{AccessTokenTTL: Seconds(time.Hour), RefreshTokenTTL: Seconds(7 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["accessTokenTtl"] != 3600.0 || m["refreshTokenTtl"] != 604800.0 {
+		t.Errorf("encoded %s", b)
+	}
+	if _, ok := m["refreshTokenIdleTtl"]; ok {
+		t.Errorf("zero idle TTL encoded: %s", b)
+	}
+
+	var s TokenSettings
+	if err := json.Unmarshal([]byte(`{"accessTokenTtl":1800,"refreshTokenTtl":1.5,"idTokenTtl":null}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(s.AccessTokenTTL) != 30*time.Minute || time.Duration(s.RefreshTokenTTL) != 1500*time.Millisecond || s.IDTokenTTL != 0 {
+		t.Errorf("decoded %+v", s)
+	}
+	if err := json.Unmarshal([]byte(`{"accessTokenTtl":"1h"}`), &s); err == nil {
+		t.Error("string duration accepted")
+	}
+}
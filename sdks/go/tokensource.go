@@ -0,0 +1,223 @@
+package authvital
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies access tokens.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// CallOption configures a single API call.
+type CallOption func(*CallOptions)
+
+// CallOptions are the settings applied by CallOption values.
+type CallOptions struct {
+	// Audience selects the API the call's access token is for.
+	Audience string
+	// Raw receives the HTTP response. See WithRawResponse.
+	Raw *RawResponse
+	// ApprovalID authorizes a dual-control operation. See WithApproval.
+	ApprovalID string
+}
+
+// WithAudience makes a call with an access token for aud, for clients
+// calling several AuthVital-protected APIs. Passed to
+// ContextWithCallOptions, it selects the audience like
+// ContextWithAudience, taking precedence over it.
+func WithAudience(aud string) CallOption {
+	return func(o *CallOptions) { o.Audience = aud }
+}
+
+type audienceKey struct{}
+
+// ContextWithAudience returns a copy of ctx selecting access tokens for
+// aud. ClientCredentials and BearerTransport honour it.
+func ContextWithAudience(ctx context.Context, aud string) context.Context {
+	return context.WithValue(ctx, audienceKey{}, aud)
+}
+
+// AudienceFromContext returns the audience set by WithAudience or
+// ContextWithAudience.
+func AudienceFromContext(ctx context.Context) string {
+	if aud := callOptionsFrom(ctx).Audience; aud != "" {
+		return aud
+	}
+	aud, _ := ctx.Value(audienceKey{}).(string)
+	return aud
+}
+
+// tokenRefreshMargin is how long before expiry a cached token is replaced.
+const tokenRefreshMargin = time.Minute
+
+// ClientCredentials is a TokenSource for the application's own access
+// tokens, using the client credentials grant. It caches one token per
+// audience, so one client can call several APIs.
+type ClientCredentials struct {
+	Host         string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+	// Audience is used when the context selects none.
+	Audience   string
+	HTTPClient *http.Client
+	// Observer, if set, is told about cache hits and refreshes.
+	Observer Observer
+	// Credentials, if set, overrides ClientID and ClientSecret. Cached
+	// tokens are discarded when the credentials it returns change.
+	Credentials CredentialProvider
+	// Clock sets token expiry; it defaults to the system clock.
+	Clock Clock
+
+	mu     sync.Mutex
+	creds  Credentials
+	tokens map[string]*Token
+	// locks serializes fetches per audience so concurrent callers share
+	// one request.
+	locks map[string]*sync.Mutex
+}
+
+// Token returns a token for the context's audience, or s.Audience.
+func (s *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	aud := AudienceFromContext(ctx)
+	if aud == "" {
+		aud = s.Audience
+	}
+	return s.TokenFor(ctx, aud)
+}
+
+// TokenFor returns a token for aud, from cache when it is not about to
+// expire.
+func (s *ClientCredentials) TokenFor(ctx context.Context, aud string) (*Token, error) {
+	if err := s.refreshCredentials(ctx); err != nil {
+		return nil, err
+	}
+	if tok := s.cached(aud); tok != nil {
+		s.observeCache(true)
+		return tok, nil
+	}
+	s.mu.Lock()
+	if s.locks == nil {
+		s.locks = map[string]*sync.Mutex{}
+	}
+	l := s.locks[aud]
+	if l == nil {
+		l = &sync.Mutex{}
+		s.locks[aud] = l
+	}
+	s.mu.Unlock()
+
+	l.Lock()
+	defer l.Unlock()
+	if tok := s.cached(aud); tok != nil {
+		s.observeCache(true)
+		return tok, nil
+	}
+	s.observeCache(false)
+
+	s.mu.Lock()
+	creds := s.creds
+	s.mu.Unlock()
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {creds.ClientID}, "client_secret": {creds.ClientSecret}}
+	if s.Scope != "" {
+		form.Set("scope", s.Scope)
+	}
+	if aud != "" {
+		form.Set("audience", aud)
+	}
+	start := time.Now()
+	tok, err := requestToken(ctx, s.HTTPClient, s.Host, form, s.Clock)
+	if s.Observer != nil {
+		s.Observer.ObserveTokenRefresh(time.Since(start), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	// Drop the token if the credentials rotated while it was fetched.
+	if s.creds == creds {
+		if s.tokens == nil {
+			s.tokens = map[string]*Token{}
+		}
+		s.tokens[aud] = tok
+	}
+	s.mu.Unlock()
+	return tok, nil
+}
+
+// refreshCredentials picks up rotated credentials, discarding tokens
+// obtained with the old ones.
+func (s *ClientCredentials) refreshCredentials(ctx context.Context) error {
+	creds := Credentials{ClientID: s.ClientID, ClientSecret: s.ClientSecret}
+	if s.Credentials != nil {
+		c, err := s.Credentials.Credentials(ctx)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return errNoCredentials
+		}
+		creds = *c
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.creds != creds {
+		s.creds = creds
+		s.tokens = nil
+	}
+	return nil
+}
+
+// Invalidate drops all cached tokens.
+func (s *ClientCredentials) Invalidate() {
+	s.mu.Lock()
+	s.tokens = nil
+	s.mu.Unlock()
+}
+
+func (s *ClientCredentials) cached(aud string) *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tok := s.tokens[aud]
+	if tok == nil || (!tok.Expiry.IsZero() && tok.Expiry.Sub(now(s.Clock)) < tokenRefreshMargin) {
+		return nil
+	}
+	return tok
+}
+
+func (s *ClientCredentials) observeCache(hit bool) {
+	if s.Observer != nil {
+		s.Observer.ObserveTokenCache(hit)
+	}
+}
+
+// BearerTransport is an http.RoundTripper adding an access token from
+// Source to each request. The request context selects the audience.
+type BearerTransport struct {
+	Base   http.RoundTripper
+	Source TokenSource
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tok, err := t.Source.Token(req.Context())
+	if err != nil {
+		// RoundTrip must close the body, even on errors.
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
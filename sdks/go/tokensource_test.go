@@ -0,0 +1,46 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAudienceFromContext(t *testing.T) {
+	ctx := context.Background()
+	if got := AudienceFromContext(ctx); got != "" {
+		t.Errorf("empty context: %q", got)
+	}
+	ctx = ContextWithAudience(ctx, "https://a.example.com")
+	if got := AudienceFromContext(ctx); got != "https://a.example.com" {
+		t.Errorf("ContextWithAudience: %q", got)
+	}
+	ctx = ContextWithCallOptions(ctx, WithAudience("https://b.example.com"))
+	if got := AudienceFromContext(ctx); got != "https://b.example.com" {
+		t.Errorf("WithAudience: %q", got)
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) Token(context.Context) (*Token, error) { return nil, errors.New("no token") }
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error { c.closed = true; return nil }
+
+func TestBearerTransportClosesBodyOnError(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("{}")}
+	req, _ := http.NewRequest("POST", "https://api.example.com/", body)
+	if _, err := (&BearerTransport{Source: failingSource{}}).RoundTrip(req); err == nil {
+		t.Fatal("no error")
+	}
+	if !body.closed {
+		t.Error("request body not closed")
+	}
+}
@@ -0,0 +1,87 @@
+package authvital
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// UserInfoParams are the parameters for fetching userinfo.
+type UserInfoParams struct {
+	AccessToken string
+	// DecryptionKey decrypts encrypted responses, normally the client's
+	// DecryptionKey. Encrypted responses fail without it.
+	DecryptionKey crypto.Decrypter
+	// Verifier verifies signed responses, which fail without it. Its
+	// audience must include the application's client ID, and its usual
+	// checks apply, so signed responses need iss, aud and exp.
+	Verifier *Verifier
+}
+
+// FetchUserInfo returns the claims of the access token's user from host's
+// userinfo endpoint. Applications registered for signed or encrypted
+// userinfo receive a JWT, which is decrypted and verified with p. hc may
+// be nil to use http.DefaultClient.
+func FetchUserInfo(ctx context.Context, hc *http.Client, host string, p *UserInfoParams) (Claims, error) {
+	if p.AccessToken == "" {
+		return nil, errors.New("authvital: access token is required")
+	}
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(host, "/")+"/oauth/userinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.AccessToken)
+	req.Header.Set("Accept", "application/json, application/jwt")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); ct != "application/jwt" {
+		return decodeUserInfo(body)
+	}
+
+	token := strings.TrimSpace(string(body))
+	if strings.Count(token, ".") == 4 {
+		if p.DecryptionKey == nil {
+			return nil, errors.New("authvital: userinfo is encrypted and no decryption key is configured")
+		}
+		plain, err := DecryptJWE(token, p.DecryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Count(string(plain), ".") != 2 {
+			// Encrypted but not signed: the plaintext is the JSON claims.
+			return decodeUserInfo(plain)
+		}
+		token = string(plain)
+	}
+	if p.Verifier == nil {
+		return nil, errors.New("authvital: userinfo is signed and no verifier is configured")
+	}
+	return p.Verifier.Verify(ctx, token)
+}
+
+func decodeUserInfo(b []byte) (Claims, error) {
+	var c Claims
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("authvital: decoding userinfo: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,112 @@
+package authvital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidUsername is returned for usernames the username policy does
+// not allow.
+var ErrInvalidUsername = errors.New("authvital: invalid username")
+
+// Username policy defaults, used when the limits are zero.
+const (
+	DefaultUsernameMinLength = 3
+	DefaultUsernameMaxLength = 30
+)
+
+// UsernamePolicy configures the handles users may choose. Usernames are
+// unique case-insensitively.
+type UsernamePolicy struct {
+	// MinLength and MaxLength count characters, defaulting to
+	// DefaultUsernameMinLength and DefaultUsernameMaxLength.
+	MinLength int `json:"minLength,omitempty"`
+	MaxLength int `json:"maxLength,omitempty"`
+	// Punctuation lists the characters allowed besides letters and
+	// digits, such as "_.-". They may not start or end a username.
+	Punctuation string `json:"punctuation,omitempty"`
+	// AllowUnicode allows non-ASCII letters and digits.
+	AllowUnicode bool `json:"allowUnicode,omitempty"`
+	// Reserved usernames cannot be chosen, compared case-insensitively,
+	// e.g. "admin" or "support".
+	Reserved []string `json:"reserved,omitempty"`
+	// BlockProfanity rejects usernames containing words from the
+	// server's profanity list. It is only enforced by the server.
+	BlockProfanity bool `json:"blockProfanity,omitempty"`
+}
+
+// ValidateUsername checks username against p locally, so forms can give
+// feedback before calling CheckAvailability. A nil p uses the defaults.
+func ValidateUsername(p *UsernamePolicy, username string) error {
+	if p == nil {
+		p = &UsernamePolicy{}
+	}
+	minLen, maxLen := p.MinLength, p.MaxLength
+	if minLen <= 0 {
+		minLen = DefaultUsernameMinLength
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultUsernameMaxLength
+	}
+	var errs []error
+	if n := utf8.RuneCountInString(username); n < minLen || n > maxLen {
+		errs = append(errs, fmt.Errorf("must be %d to %d characters", minLen, maxLen))
+	}
+	for i, r := range username {
+		switch {
+		case r < utf8.RuneSelf && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'):
+		case r >= utf8.RuneSelf && p.AllowUnicode && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+		case strings.ContainsRune(p.Punctuation, r):
+			if i == 0 || i+utf8.RuneLen(r) == len(username) {
+				errs = append(errs, fmt.Errorf("cannot start or end with %q", r))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%q is not allowed", r))
+		}
+	}
+	if containsFold(p.Reserved, username) {
+		errs = append(errs, errors.New("is reserved"))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w %q: %w", ErrInvalidUsername, username, errors.Join(errs...))
+}
+
+// UsernameAvailability is the result of CheckAvailability.
+type UsernameAvailability struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+	// Reason explains an unavailable username: "taken", "reserved",
+	// "profanity" or "invalid".
+	Reason string `json:"reason,omitempty"`
+	// Suggestions are similar usernames that are available, when Username
+	// is not.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// UsernamePolicyService configures usernames, for products where users
+// pick handles.
+type UsernamePolicyService struct{}
+
+// Get retrieves the username policy.
+func (s *UsernamePolicyService) Get(ctx context.Context) (*UsernamePolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// Update replaces the username policy. Existing usernames are kept even
+// if the new policy does not allow them.
+func (s *UsernamePolicyService) Update(ctx context.Context, policy *UsernamePolicy) (*UsernamePolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+// CheckAvailability reports whether username can be chosen, suggesting
+// alternatives when it cannot. Availability is not reserved; the username
+// may be taken before it is set.
+func (s *UsernamePolicyService) CheckAvailability(ctx context.Context, username string) (*UsernameAvailability, error) {
+	return nil, ErrNotImplemented
+}
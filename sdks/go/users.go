@@ -0,0 +1,144 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// UserStatus describes whether a user is allowed to sign in.
+type UserStatus string
+
+const (
+	// UserStatusActive is a user in good standing.
+	UserStatusActive UserStatus = "active"
+	// UserStatusBlocked is a user blocked indefinitely by an administrator.
+	UserStatusBlocked UserStatus = "blocked"
+	// UserStatusSuspended is a user blocked until SuspendedUntil.
+	UserStatusSuspended UserStatus = "suspended"
+	// UserStatusLocked is a user temporarily locked out after repeated failed sign-ins.
+	UserStatusLocked UserStatus = "locked"
+	// UserStatusDeleted is a soft-deleted user that can still be restored
+	// until PurgeAt.
+	UserStatusDeleted UserStatus = "deleted"
+)
+
+// User is an AuthVital user account.
+type User struct {
+	ID             string         `json:"id"`
+	Email          string         `json:"email"`
+	CanonicalEmail string         `json:"canonicalEmail,omitempty"`
+	Username       string         `json:"username,omitempty"`
+	Phone          string         `json:"phone,omitempty"`
+	MFAEnabled     bool           `json:"mfaEnabled"`
+	Profile        map[string]any `json:"profile,omitempty"`
+	Status         UserStatus     `json:"status"`
+	StatusReason   string         `json:"statusReason,omitempty"`
+	SuspendedUntil *time.Time     `json:"suspendedUntil,omitempty"`
+	Billing        *BillingLink   `json:"billing,omitempty"`
+	DeletedAt      *time.Time     `json:"deletedAt,omitempty"`
+	PurgeAt        *time.Time     `json:"purgeAt,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
+// Lockout is the brute-force lockout state of a user.
+type Lockout struct {
+	Locked         bool       `json:"locked"`
+	FailedAttempts int        `json:"failedAttempts"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"`
+	LastFailedAt   *time.Time `json:"lastFailedAt,omitempty"`
+	LastFailedIP   string     `json:"lastFailedIp,omitempty"`
+}
+
+// SuspendOptions configures a user suspension.
+type SuspendOptions struct {
+	// Until is when the suspension lifts automatically.
+	Until time.Time
+	// Reason is recorded on the user and in the audit log.
+	Reason string
+}
+
+// DeleteOptions configures Users.Delete.
+type DeleteOptions struct {
+	// Hard erases the user immediately and irreversibly. By default the
+	// user is soft-deleted: signed out, unable to sign in, and restorable
+	// until the retention window ends.
+	Hard bool
+	// Retention overrides the tenant's soft-delete retention window.
+	Retention time.Duration
+}
+
+// UsersService manages user accounts.
+type UsersService struct{}
+
+// Get retrieves a user by ID. opts may be nil.
+func (s *UsersService) Get(ctx context.Context, userID string, opts *GetOptions) (*User, error) {
+	return nil, ErrNotImplemented
+}
+
+// List lists users. Use opts.Fields to fetch only the fields you need.
+func (s *UsersService) List(ctx context.Context, opts *ListOptions) (*List[User], error) {
+	return nil, ErrNotImplemented
+}
+
+// Search lists users matching q.
+func (s *UsersService) Search(ctx context.Context, q Query, opts *SearchOptions) (*List[User], error) {
+	return nil, ErrNotImplemented
+}
+
+// GetByExternalID retrieves the user linked to an external billing record,
+// e.g. a Stripe customer.
+func (s *UsersService) GetByExternalID(ctx context.Context, provider string, kind ExternalIDKind, id string) (*User, error) {
+	return nil, ErrNotImplemented
+}
+
+// SetBilling links a user to an external billing record. A nil link
+// removes it.
+func (s *UsersService) SetBilling(ctx context.Context, userID string, link *BillingLink) error {
+	return ErrNotImplemented
+}
+
+// SetPassword sets a user's password. It returns ErrPasswordBreached if the
+// password appears in a known breach.
+func (s *UsersService) SetPassword(ctx context.Context, userID, password string) error {
+	return ErrNotImplemented
+}
+
+// Block prevents a user from signing in until Unblock is called.
+func (s *UsersService) Block(ctx context.Context, userID, reason string) error {
+	return ErrNotImplemented
+}
+
+// Unblock lifts a block or suspension.
+func (s *UsersService) Unblock(ctx context.Context, userID string) error {
+	return ErrNotImplemented
+}
+
+// Suspend prevents a user from signing in until opts.Until.
+func (s *UsersService) Suspend(ctx context.Context, userID string, opts SuspendOptions) error {
+	return ErrNotImplemented
+}
+
+// GetLockout retrieves the brute-force lockout state of a user.
+func (s *UsersService) GetLockout(ctx context.Context, userID string) (*Lockout, error) {
+	return nil, ErrNotImplemented
+}
+
+// ClearLockout resets failed sign-in attempts and lifts any lockout.
+func (s *UsersService) ClearLockout(ctx context.Context, userID string) error {
+	return ErrNotImplemented
+}
+
+// Delete deletes a user. opts may be nil for a soft delete.
+func (s *UsersService) Delete(ctx context.Context, userID string, opts *DeleteOptions) error {
+	return ErrNotImplemented
+}
+
+// Restore undoes a soft delete.
+func (s *UsersService) Restore(ctx context.Context, userID string) (*User, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListDeleted lists soft-deleted users that have not been purged yet.
+func (s *UsersService) ListDeleted(ctx context.Context, opts *ListOptions) (*List[User], error) {
+	return nil, ErrNotImplemented
+}
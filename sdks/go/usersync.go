@@ -0,0 +1,309 @@
+package authvital
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrTooManyDeactivations is returned by UserSync when a run would
+// deactivate more users than UserSync.MaxDeactivations allows, which
+// usually means the source returned a partial list.
+var ErrTooManyDeactivations = errors.New("authvital: sync would deactivate too many users")
+
+// DesiredUser is a user as an external source of truth, such as an HR
+// system, says it should be.
+type DesiredUser struct {
+	// ExternalID identifies the user in the source. It is required when
+	// UserSync.ExternalIDField is set.
+	ExternalID string
+	Email      string
+	// Profile fields are compared and written; fields absent here are left
+	// alone.
+	Profile map[string]any
+	// Disabled users are deactivated.
+	Disabled bool
+}
+
+// DesiredUsers iterates over a source's users. Next returns io.EOF after
+// the last one.
+type DesiredUsers interface {
+	Next(ctx context.Context) (*DesiredUser, error)
+}
+
+// SliceUsers adapts a slice to DesiredUsers.
+func SliceUsers(users []DesiredUser) DesiredUsers {
+	return &sliceUsers{users: users}
+}
+
+type sliceUsers struct {
+	users []DesiredUser
+	i     int
+}
+
+func (s *sliceUsers) Next(ctx context.Context) (*DesiredUser, error) {
+	if s.i >= len(s.users) {
+		return nil, io.EOF
+	}
+	s.i++
+	return &s.users[s.i-1], nil
+}
+
+// SyncReason is recorded on users the sync deactivates. Only users
+// deactivated with this reason are reactivated by it.
+const SyncReason = "deactivated by directory sync"
+
+// UserSync reconciles AuthVital users with an external source: it creates
+// missing users, updates changed ones, and deactivates users the source no
+// longer lists.
+type UserSync struct {
+	Users *UsersService
+	// Scope limits the AuthVital users the sync manages, e.g.
+	// Field("profile.source").Eq("hr"). Users outside it are never
+	// changed. Empty manages all users.
+	Scope Query
+	// ExternalIDField is the profile field holding DesiredUser.ExternalID,
+	// used to match users. Empty matches on email, ignoring case.
+	ExternalIDField string
+	// BatchSize defaults to MaxBulkChanges.
+	BatchSize int
+	// Rate limits BulkApply calls per second. Zero means no limit.
+	Rate float64
+	// Partial means the desired users are only those changed since the
+	// last run, as in an incremental directory sync: missing users are
+	// then not deactivated.
+	Partial bool
+	// MaxDeactivations aborts a run that would deactivate more users. Zero
+	// means no limit.
+	MaxDeactivations int
+}
+
+// SyncFailure is a change the server rejected.
+type SyncFailure struct {
+	Change UserChange
+	Err    string
+}
+
+// SyncReport describes a sync run.
+type SyncReport struct {
+	Created, Updated, Deactivated, Reactivated, Unchanged int
+	// Changes are all changes, in the order they were applied.
+	Changes []UserChange
+	Failed  []SyncFailure
+}
+
+func (r *SyncReport) String() string {
+	return fmt.Sprintf("%d created, %d updated, %d deactivated, %d reactivated, %d unchanged, %d failed",
+		r.Created, r.Updated, r.Deactivated, r.Reactivated, r.Unchanged, len(r.Failed))
+}
+
+func (r *SyncReport) add(c UserChange) {
+	r.Changes = append(r.Changes, c)
+	switch c.Op {
+	case UserCreate:
+		r.Created++
+	case UserUpdate:
+		r.Updated++
+	case UserDeactivate:
+		r.Deactivated++
+	case UserReactivate:
+		r.Reactivated++
+	}
+}
+
+// Plan computes the changes a run would make without applying them.
+func (s *UserSync) Plan(ctx context.Context, desired DesiredUsers) (*SyncReport, error) {
+	existing, err := s.existing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep := &SyncReport{}
+	seen := map[string]bool{}
+	for {
+		d, err := desired.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		key := s.desiredKey(d)
+		if key == "" {
+			return nil, fmt.Errorf("authvital: desired user %q has no match key", d.Email)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("authvital: duplicate desired user %q", key)
+		}
+		seen[key] = true
+		changes := s.diff(existing[key], d)
+		if len(changes) == 0 {
+			rep.Unchanged++
+		}
+		for _, c := range changes {
+			rep.add(c)
+		}
+	}
+	var gone []string
+	for key, u := range existing {
+		if !s.Partial && !seen[key] && u.Status == UserStatusActive {
+			gone = append(gone, key)
+		}
+	}
+	sort.Strings(gone)
+	for _, key := range gone {
+		rep.add(UserChange{Op: UserDeactivate, UserID: existing[key].ID, Reason: SyncReason})
+	}
+	if s.MaxDeactivations > 0 && rep.Deactivated > s.MaxDeactivations {
+		return rep, fmt.Errorf("%w: %d of at most %d", ErrTooManyDeactivations, rep.Deactivated, s.MaxDeactivations)
+	}
+	return rep, nil
+}
+
+// Run plans and applies the changes in batches. It returns the report of
+// what was applied so far along with any error.
+func (s *UserSync) Run(ctx context.Context, desired DesiredUsers) (*SyncReport, error) {
+	plan, err := s.Plan(ctx, desired)
+	if err != nil {
+		return plan, err
+	}
+	size := s.BatchSize
+	if size <= 0 || size > MaxBulkChanges {
+		size = MaxBulkChanges
+	}
+	var tick <-chan time.Time
+	if s.Rate > 0 {
+		t := time.NewTicker(time.Duration(float64(time.Second) / s.Rate))
+		defer t.Stop()
+		tick = t.C
+	}
+	rep := &SyncReport{Unchanged: plan.Unchanged}
+	for start := 0; start < len(plan.Changes); start += size {
+		if start > 0 && tick != nil {
+			select {
+			case <-ctx.Done():
+				return rep, ctx.Err()
+			case <-tick:
+			}
+		}
+		batch := plan.Changes[start:min(start+size, len(plan.Changes))]
+		res, err := s.Users.BulkApply(ctx, batch)
+		if err != nil {
+			return rep, err
+		}
+		failed := map[int64]string{}
+		for _, e := range res.Errors {
+			failed[e.Index] = e.Message
+		}
+		for i, c := range batch {
+			if msg, ok := failed[int64(i)]; ok {
+				rep.Failed = append(rep.Failed, SyncFailure{Change: c, Err: msg})
+				continue
+			}
+			rep.add(c)
+		}
+	}
+	return rep, nil
+}
+
+// existing loads the managed users keyed like desiredKey.
+func (s *UserSync) existing(ctx context.Context) (map[string]*User, error) {
+	users := map[string]*User{}
+	opts := &SearchOptions{}
+	for {
+		var page *List[User]
+		var err error
+		if s.Scope != "" {
+			page, err = s.Users.Search(ctx, s.Scope, opts)
+		} else {
+			page, err = s.Users.List(ctx, &opts.ListOptions)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.Items {
+			u := &page.Items[i]
+			if u.Status == UserStatusDeleted {
+				continue
+			}
+			if key := s.userKey(u); key != "" {
+				users[key] = u
+			}
+		}
+		if page.NextCursor == "" {
+			return users, nil
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+func (s *UserSync) userKey(u *User) string {
+	if s.ExternalIDField == "" {
+		return strings.ToLower(u.Email)
+	}
+	if v, ok := u.Profile[s.ExternalIDField]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func (s *UserSync) desiredKey(d *DesiredUser) string {
+	if s.ExternalIDField == "" {
+		return strings.ToLower(d.Email)
+	}
+	return d.ExternalID
+}
+
+// diff returns the changes that make u match d; u is nil for new users.
+func (s *UserSync) diff(u *User, d *DesiredUser) []UserChange {
+	if u == nil {
+		if d.Disabled {
+			return nil
+		}
+		c := UserChange{Op: UserCreate, Email: d.Email, Profile: map[string]any{}}
+		for k, v := range d.Profile {
+			c.Profile[k] = v
+		}
+		if s.ExternalIDField != "" {
+			c.Profile[s.ExternalIDField] = d.ExternalID
+		}
+		return []UserChange{c}
+	}
+	var changes []UserChange
+	switch {
+	case d.Disabled && u.Status == UserStatusActive:
+		return []UserChange{{Op: UserDeactivate, UserID: u.ID, Reason: SyncReason}}
+	case d.Disabled:
+		return nil
+	case u.Status == UserStatusBlocked && u.StatusReason == SyncReason:
+		changes = append(changes, UserChange{Op: UserReactivate, UserID: u.ID})
+	}
+	upd := UserChange{Op: UserUpdate, UserID: u.ID}
+	if !strings.EqualFold(u.Email, d.Email) && d.Email != "" {
+		upd.Email = d.Email
+	}
+	for k, v := range d.Profile {
+		if !sameJSON(u.Profile[k], v) {
+			if upd.Profile == nil {
+				upd.Profile = map[string]any{}
+			}
+			upd.Profile[k] = v
+		}
+	}
+	if upd.Email != "" || upd.Profile != nil {
+		changes = append(changes, upd)
+	}
+	return changes
+}
+
+// sameJSON compares values by their JSON encoding, so an int from the
+// source equals the float64 decoded from the API.
+func sameJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
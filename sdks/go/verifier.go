@@ -0,0 +1,682 @@
+package authvital
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned by Verifier.Verify. They may be wrapped with detail;
+// test with errors.Is.
+var (
+	ErrTokenMalformed      = errors.New("authvital: malformed token")
+	ErrTokenExpired        = errors.New("authvital: token is expired")
+	ErrTokenNotYetValid    = errors.New("authvital: token is not valid yet")
+	ErrTokenIssuedInFuture = errors.New("authvital: token used before issued; check the local clock")
+	ErrInvalidSignature    = errors.New("authvital: invalid token signature")
+	ErrUnknownIssuer       = errors.New("authvital: token issuer is not trusted")
+	ErrInvalidAudience     = errors.New("authvital: token audience is not accepted")
+	ErrAlgorithmRejected   = errors.New("authvital: token signing algorithm is not allowed")
+	ErrKeyNotPinned        = errors.New("authvital: token signing key is not pinned")
+	ErrInvalidTokenType    = errors.New("authvital: token typ header is not accepted")
+	ErrUnsupportedCritical = errors.New("authvital: token has unsupported critical headers")
+)
+
+// IssuerConfig describes one trusted token issuer.
+type IssuerConfig struct {
+	// Issuer is the exact iss value, e.g. "https://acme.authvital.app".
+	Issuer string
+	// JWKSURL defaults to Issuer + "/.well-known/jwks.json".
+	JWKSURL string
+	// Audiences lists accepted aud values. A token is accepted if any of its
+	// audiences is listed. Empty accepts any audience.
+	Audiences []string
+}
+
+// IssuerResolver looks up the configuration of an issuer not configured
+// statically, e.g. a customer's custom domain loaded from a database. It
+// returns nil, nil when the issuer is not trusted.
+type IssuerResolver func(ctx context.Context, issuer string) (*IssuerConfig, error)
+
+// Verifier validates AuthVital JWTs. It is safe for concurrent use; create
+// one and share it. Concurrent verifications needing a key refresh share a
+// single JWKS fetch per issuer.
+type Verifier struct {
+	httpClient *http.Client
+	leeway     time.Duration
+	jwksTTL    time.Duration
+	resolver   IssuerResolver
+	missTTL    time.Duration
+
+	allowedAlgs    map[string]bool
+	pins           map[string]bool
+	typ            string
+	typMode        EnforcementMode
+	crit           map[string]bool
+	critMode       EnforcementMode
+	onWarning      func(error)
+	revocations    SessionRevocations
+	maxDelegation  int
+	strictAudience bool
+	clock          Clock
+	decryptionKey  crypto.Decrypter
+	schema         *ClaimsSchema
+
+	maxCompensation time.Duration
+	skewWarn        time.Duration
+	onSkew          func(time.Duration)
+
+	mu      sync.RWMutex
+	issuers map[string]*trustedIssuer
+	misses  map[string]time.Time // unresolved issuer -> retry after
+	skew    time.Duration
+	skewSet bool
+}
+
+type trustedIssuer struct {
+	config IssuerConfig
+	keys   *keySet
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithIssuer trusts tokens from issuer, optionally restricted to audiences.
+// Use it once per issuer to verify tokens from several tenants or custom
+// domains with one Verifier.
+func WithIssuer(issuer string, audiences ...string) VerifierOption {
+	return WithIssuerConfig(IssuerConfig{Issuer: issuer, Audiences: audiences})
+}
+
+// WithIssuerConfig trusts the issuer described by cfg.
+func WithIssuerConfig(cfg IssuerConfig) VerifierOption {
+	return func(v *Verifier) {
+		v.issuers[cfg.Issuer] = &trustedIssuer{config: cfg}
+	}
+}
+
+// WithIssuerResolver consults resolve for issuers not configured with
+// WithIssuer. Resolved issuers are cached for the Verifier's lifetime;
+// issuers it rejects or fails on are not retried for DefaultIssuerMissTTL,
+// so tokens with a forged iss cannot make every verification call it.
+func WithIssuerResolver(resolve IssuerResolver) VerifierOption {
+	return func(v *Verifier) { v.resolver = resolve }
+}
+
+// DefaultIssuerMissTTL is how long an issuer the resolver did not accept is
+// remembered as unknown.
+const DefaultIssuerMissTTL = 30 * time.Second
+
+// maxIssuerMisses bounds the unknown-issuer cache against tokens carrying
+// a different forged iss each time.
+const maxIssuerMisses = 1024
+
+// WithIssuerMissTTL overrides DefaultIssuerMissTTL. Zero or negative
+// consults the resolver for every unknown issuer.
+func WithIssuerMissTTL(d time.Duration) VerifierOption {
+	return func(v *Verifier) { v.missTTL = d }
+}
+
+// WithVerifierHTTPClient sets the HTTP client used to fetch JWKS.
+func WithVerifierHTTPClient(c *http.Client) VerifierOption {
+	return func(v *Verifier) { v.httpClient = c }
+}
+
+// WithLeeway allows for clock differences when checking exp and nbf.
+// The default is one minute.
+func WithLeeway(d time.Duration) VerifierOption {
+	return func(v *Verifier) { v.leeway = d }
+}
+
+// WithJWKSCacheTTL sets how long fetched keys are used before refetching.
+// The default is one hour.
+func WithJWKSCacheTTL(d time.Duration) VerifierOption {
+	return func(v *Verifier) { v.jwksTTL = d }
+}
+
+// WithAllowedAlgorithms restricts accepted signing algorithms, e.g. to
+// SigES256 and SigEdDSA. By default every supported asymmetric algorithm is
+// accepted; "none" and HMAC algorithms are always rejected.
+func WithAllowedAlgorithms(algs ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.allowedAlgs = make(map[string]bool, len(algs))
+		for _, a := range algs {
+			v.allowedAlgs[a] = true
+		}
+	}
+}
+
+// WithPinnedKeys accepts only tokens signed by keys with one of pins as
+// their kid or RFC 7638 thumbprint, so a compromised JWKS endpoint cannot
+// introduce new signing keys. Remember to add the next key's pin before a
+// key rotation.
+func WithPinnedKeys(pins ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.pins = make(map[string]bool, len(pins))
+		for _, p := range pins {
+			v.pins[p] = true
+		}
+	}
+}
+
+// EnforcementMode selects whether a header check rejects tokens or only
+// reports them.
+type EnforcementMode int
+
+const (
+	// EnforceStrict rejects tokens that fail the check.
+	EnforceStrict EnforcementMode = iota
+	// EnforceWarn accepts tokens that fail the check and reports the
+	// failure to the WithVerifierWarnings handler, for rolling out a check.
+	EnforceWarn
+)
+
+// WithTokenType requires the typ header to equal typ, compared
+// case-insensitively with or without an "application/" prefix. Use "at+jwt"
+// to accept only RFC 9068 access tokens, which keeps ID tokens and other
+// JWTs from the same issuer from being replayed as access tokens.
+func WithTokenType(typ string, mode EnforcementMode) VerifierOption {
+	return func(v *Verifier) { v.typ, v.typMode = typ, mode }
+}
+
+// WithCriticalHeaders declares the crit header parameters the application
+// understands and checks itself. Tokens listing any other critical header
+// fail verification as RFC 7515 requires; with EnforceWarn they are
+// reported instead.
+func WithCriticalHeaders(mode EnforcementMode, names ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.critMode = mode
+		v.crit = make(map[string]bool, len(names))
+		for _, n := range names {
+			v.crit[n] = true
+		}
+	}
+}
+
+// WithVerifierWarnings receives failures of checks in EnforceWarn mode.
+func WithVerifierWarnings(fn func(error)) VerifierOption {
+	return func(v *Verifier) { v.onWarning = fn }
+}
+
+// WithClockSkewCompensation corrects for a local clock that drifts from
+// AuthVital's by up to max. The skew is measured from the Date header of
+// JWKS responses; larger skews are reported but not corrected.
+func WithClockSkewCompensation(max time.Duration) VerifierOption {
+	return func(v *Verifier) { v.maxCompensation = max }
+}
+
+// WithClockSkewWarning calls fn whenever a measured skew exceeds threshold,
+// e.g. to log an NTP drift warning.
+func WithClockSkewWarning(threshold time.Duration, fn func(skew time.Duration)) VerifierOption {
+	return func(v *Verifier) { v.skewWarn, v.onSkew = threshold, fn }
+}
+
+// NewVerifier creates a Verifier. At least one issuer or a resolver must be
+// configured.
+func NewVerifier(opts ...VerifierOption) (*Verifier, error) {
+	v := &Verifier{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		leeway:     time.Minute,
+		jwksTTL:    time.Hour,
+		missTTL:    DefaultIssuerMissTTL,
+		issuers:    map[string]*trustedIssuer{},
+		misses:     map[string]time.Time{},
+		clock:      SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if len(v.issuers) == 0 && v.resolver == nil {
+		return nil, errors.New("authvital: verifier needs at least one issuer")
+	}
+	for _, ti := range v.issuers {
+		v.initIssuer(ti)
+	}
+	return v, nil
+}
+
+func (v *Verifier) initIssuer(ti *trustedIssuer) {
+	u := ti.config.JWKSURL
+	if u == "" {
+		u = strings.TrimRight(ti.config.Issuer, "/") + "/.well-known/jwks.json"
+	}
+	ti.keys = newKeySet(u, v.httpClient, v.jwksTTL, v.clock)
+	ti.keys.onDate = v.recordServerTime
+}
+
+// recordServerTime updates the measured clock skew from a server Date.
+func (v *Verifier) recordServerTime(server, local time.Time) {
+	// Date has one-second resolution; rounding avoids reporting noise.
+	skew := local.Sub(server).Round(time.Second)
+	v.mu.Lock()
+	v.skew, v.skewSet = skew, true
+	v.mu.Unlock()
+	if v.onSkew != nil && absDuration(skew) > v.skewWarn {
+		v.onSkew(skew)
+	}
+}
+
+// WarmUp fetches the JWKS of every statically configured issuer, so the
+// first requests after startup do not wait for it. Issuers found through
+// a resolver are fetched on first use. Call it before marking the service
+// ready; an error means at least one issuer's keys could not be fetched,
+// and they will be retried on demand.
+func (v *Verifier) WarmUp(ctx context.Context) error {
+	v.mu.RLock()
+	issuers := make([]*trustedIssuer, 0, len(v.issuers))
+	for _, ti := range v.issuers {
+		issuers = append(issuers, ti)
+	}
+	v.mu.RUnlock()
+
+	errs := make([]error, len(issuers))
+	var wg sync.WaitGroup
+	for i, ti := range issuers {
+		wg.Add(1)
+		go func(i int, ti *trustedIssuer) {
+			defer wg.Done()
+			if err := ti.keys.refresh(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", ti.config.Issuer, err)
+			}
+		}(i, ti)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// ClockSkew returns the local clock minus AuthVital's, as last measured.
+// It returns false before the first JWKS fetch.
+func (v *Verifier) ClockSkew() (time.Duration, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.skew, v.skewSet
+}
+
+// now returns the current time, corrected for skew when compensation is
+// enabled and the skew is within bounds.
+func (v *Verifier) now() time.Time {
+	now := v.clock.Now()
+	if v.maxCompensation <= 0 {
+		return now
+	}
+	skew, ok := v.ClockSkew()
+	if !ok || absDuration(skew) > v.maxCompensation {
+		return now
+	}
+	return now.Add(-skew)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func (v *Verifier) issuer(ctx context.Context, iss string) (*trustedIssuer, error) {
+	v.mu.RLock()
+	ti := v.issuers[iss]
+	retry, missed := v.misses[iss]
+	v.mu.RUnlock()
+	if ti != nil {
+		return ti, nil
+	}
+	if v.resolver == nil || iss == "" || missed && v.clock.Now().Before(retry) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIssuer, iss)
+	}
+	cfg, err := v.resolver(ctx, iss)
+	if err == nil && (cfg == nil || cfg.Issuer != iss) {
+		err = fmt.Errorf("%w: %q", ErrUnknownIssuer, iss)
+	}
+	if err != nil {
+		// A cancelled request says nothing about the issuer.
+		if ctx.Err() == nil {
+			v.recordMiss(iss)
+		}
+		return nil, err
+	}
+	ti = &trustedIssuer{config: *cfg}
+	v.initIssuer(ti)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.misses, iss)
+	if existing := v.issuers[iss]; existing != nil {
+		return existing, nil
+	}
+	v.issuers[iss] = ti
+	return ti, nil
+}
+
+// recordMiss remembers that the resolver did not accept iss.
+func (v *Verifier) recordMiss(iss string) {
+	if v.missTTL <= 0 {
+		return
+	}
+	now := v.clock.Now()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.misses) >= maxIssuerMisses {
+		for k, retry := range v.misses {
+			if !now.Before(retry) {
+				delete(v.misses, k)
+			}
+		}
+	}
+	if len(v.misses) >= maxIssuerMisses {
+		// Still full of live entries: drop an arbitrary one.
+		for k := range v.misses {
+			delete(v.misses, k)
+			break
+		}
+	}
+	v.misses[iss] = now.Add(v.missTTL)
+}
+
+// Verify checks the signature and standard claims of token and returns its
+// claims. The issuer is selected by the token's iss claim.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	var claims Claims
+	if err := v.verify(ctx, token, func(payload []byte) (*StandardClaims, any, Claims, error) {
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+		}
+		return claims.standard(), claims["act"], claims, nil
+	}); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// VerifyStandard is Verify decoding the registered and common AuthVital
+// claims into a struct instead of a Claims map, which allocates less per
+// token (see BenchmarkVerifyStandard); other claims are decoded on demand
+// with StandardClaims.Decode. An act claim is still decoded generically,
+// and with WithClaimsSchema the payload is decoded as a map once, as by
+// Verify, so the schema can see every claim.
+func (v *Verifier) VerifyStandard(ctx context.Context, token string) (*StandardClaims, error) {
+	var std *StandardClaims
+	if err := v.verify(ctx, token, func(payload []byte) (*StandardClaims, any, Claims, error) {
+		if v.schema != nil {
+			var all Claims
+			if err := json.Unmarshal(payload, &all); err != nil {
+				return nil, nil, nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+			}
+			std = all.standard()
+			std.raw = payload
+			if act, ok := all["act"]; ok {
+				std.Act, _ = json.Marshal(act)
+			}
+			return std, all["act"], all, nil
+		}
+		std = &StandardClaims{raw: payload}
+		if err := json.Unmarshal(payload, std); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+		}
+		if std.Act == nil {
+			return std, nil, nil, nil
+		}
+		var act any
+		if err := json.Unmarshal(std.Act, &act); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: act: %v", ErrTokenMalformed, err)
+		}
+		return std, act, nil, nil
+	}); err != nil {
+		return nil, err
+	}
+	return std, nil
+}
+
+// verify runs all checks on token. decode parses the payload, returning
+// the standard claims, the act claim and, when it built one, the full
+// Claims map. The map is required when a claims schema is set.
+func (v *Verifier) verify(ctx context.Context, token string, decode func(payload []byte) (*StandardClaims, any, Claims, error)) error {
+	if strings.Count(token, ".") == 4 {
+		// An encrypted token; the plaintext is the signed JWT.
+		if v.decryptionKey == nil {
+			return fmt.Errorf("%w: token is encrypted and no decryption key is configured", ErrTokenMalformed)
+		}
+		plain, err := DecryptJWE(token, v.decryptionKey)
+		if err != nil {
+			return err
+		}
+		token = string(plain)
+	}
+	head, rest, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrTokenMalformed
+	}
+	body, sigSeg, ok := strings.Cut(rest, ".")
+	if !ok || strings.IndexByte(sigSeg, '.') >= 0 {
+		return ErrTokenMalformed
+	}
+	var hdr jwsHeader
+	if err := decodeSegment(head, &hdr); err != nil {
+		return err
+	}
+	if err := v.checkHeader(head, &hdr); err != nil {
+		return err
+	}
+	payload, err := b64.DecodeString(body)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	claims, act, all, err := decode(payload)
+	if err != nil {
+		return err
+	}
+
+	ti, err := v.issuer(ctx, claims.Issuer)
+	if err != nil {
+		return err
+	}
+	if !v.algorithmAllowed(hdr.Alg) {
+		return fmt.Errorf("%w: %q", ErrAlgorithmRejected, hdr.Alg)
+	}
+	key, err := ti.keys.key(ctx, hdr.Kid)
+	if err != nil {
+		return err
+	}
+	if key.alg != "" && key.alg != hdr.Alg {
+		// The key is published for a different algorithm; accepting the
+		// token's choice would allow algorithm confusion.
+		return fmt.Errorf("%w: %q with a %q key", ErrAlgorithmRejected, hdr.Alg, key.alg)
+	}
+	if v.pins != nil && !v.pins[hdr.Kid] && !v.pins[key.thumbprint] {
+		return fmt.Errorf("%w: kid %q, thumbprint %s", ErrKeyNotPinned, hdr.Kid, key.thumbprint)
+	}
+	sig, err := b64.DecodeString(sigSeg)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	// The signing input is a prefix of token, so no copy is needed.
+	if err := verifySignature(hdr.Alg, key.pub, token[:len(head)+1+len(body)], sig); err != nil {
+		return err
+	}
+
+	if err := v.validateTimes(claims); err != nil {
+		return err
+	}
+	aud := []string(claims.Audience)
+	if len(ti.config.Audiences) > 0 && !audienceMatches(aud, ti.config.Audiences) {
+		return fmt.Errorf("%w: %v", ErrInvalidAudience, aud)
+	}
+	if v.strictAudience && len(ti.config.Audiences) > 0 && !strictAudienceMatches(aud, ti.config.Audiences) {
+		return fmt.Errorf("%w: %v includes audiences not accepted", ErrInvalidAudience, aud)
+	}
+	if err := v.validateDelegation(act); err != nil {
+		return err
+	}
+	if sid := claims.SessionID; v.revocations != nil && sid != "" {
+		revoked, err := v.revocations.Revoked(ctx, sid)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrSessionEvicted
+		}
+	}
+	if v.schema != nil {
+		return v.schema.Validate(all)
+	}
+	return nil
+}
+
+// checkHeader applies the typ and crit checks.
+func (v *Verifier) checkHeader(seg string, hdr *jwsHeader) error {
+	if v.typ != "" && !typMatches(hdr.Typ, v.typ) {
+		err := fmt.Errorf("%w: got %q, want %q", ErrInvalidTokenType, hdr.Typ, v.typ)
+		if err := v.enforce(v.typMode, err); err != nil {
+			return err
+		}
+	}
+	if hdr.Crit == nil {
+		return nil
+	}
+	var raw map[string]json.RawMessage
+	if err := decodeSegment(seg, &raw); err != nil {
+		return err
+	}
+	if len(hdr.Crit) == 0 {
+		return fmt.Errorf("%w: crit must not be empty", ErrTokenMalformed)
+	}
+	for _, name := range hdr.Crit {
+		if _, ok := raw[name]; !ok {
+			return fmt.Errorf("%w: critical header %q is missing", ErrTokenMalformed, name)
+		}
+		if !v.crit[name] {
+			// An unknown critical header must not be silently accepted
+			// unless the operator explicitly chose to warn.
+			mode := EnforceStrict
+			if v.crit != nil {
+				mode = v.critMode
+			}
+			if err := v.enforce(mode, fmt.Errorf("%w: %q", ErrUnsupportedCritical, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) enforce(mode EnforcementMode, err error) error {
+	if mode == EnforceWarn {
+		if v.onWarning != nil {
+			v.onWarning(err)
+		}
+		return nil
+	}
+	return err
+}
+
+func typMatches(got, want string) bool {
+	trim := func(s string) string {
+		s = strings.ToLower(s)
+		return strings.TrimPrefix(s, "application/")
+	}
+	return trim(got) == trim(want)
+}
+
+func (v *Verifier) algorithmAllowed(alg string) bool {
+	if alg == "" || alg == "none" || strings.HasPrefix(alg, "HS") {
+		return false
+	}
+	return v.allowedAlgs == nil || v.allowedAlgs[alg]
+}
+
+func (v *Verifier) validateTimes(c *StandardClaims) error {
+	now := v.now()
+	if c.ExpiresAt == 0 {
+		return fmt.Errorf("%w: missing exp", ErrTokenMalformed)
+	} else if now.After(c.ExpiresAt.Time().Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Before(c.NotBefore.Time()) {
+		return ErrTokenNotYetValid
+	}
+	if iat := c.IssuedAt.Time(); c.IssuedAt != 0 && now.Add(v.leeway).Before(iat) {
+		return fmt.Errorf("%w: issued %s ahead", ErrTokenIssuedInFuture, iat.Sub(now).Round(time.Second))
+	}
+	return nil
+}
+
+func audienceMatches(got, accepted []string) bool {
+	for _, g := range got {
+		for _, a := range accepted {
+			if g == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(seg string, v any) error {
+	raw, err := b64.DecodeString(seg)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, input string, sig []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("%w: %q", ErrAlgorithmRejected, alg)
+	}
+	var h crypto.Hash
+	switch alg[2:] {
+	case "256":
+		h = crypto.SHA256
+	case "384":
+		h = crypto.SHA384
+	case "512":
+		h = crypto.SHA512
+	}
+	ok := false
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if h == 0 {
+			break
+		}
+		d := h.New()
+		d.Write([]byte(input))
+		switch alg[:2] {
+		case "RS":
+			ok = rsa.VerifyPKCS1v15(k, h, d.Sum(nil), sig) == nil
+		case "PS":
+			ok = rsa.VerifyPSS(k, h, d.Sum(nil), sig, nil) == nil
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || h == 0 || len(sig) != 2*size {
+			break
+		}
+		d := h.New()
+		d.Write([]byte(input))
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		ok = ecdsa.Verify(k, d.Sum(nil), r, s)
+	case ed25519.PublicKey:
+		if alg == SigEdDSA {
+			ok = ed25519.Verify(k, []byte(input), sig)
+		}
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package authvital
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// benchVerifier returns a Verifier trusting a test issuer and a token it
+// signed with the usual AuthVital access token claims.
+func benchVerifier(b *testing.B) (*Verifier, string) {
+	b.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	jwk := JWK{
+		Kty: "EC", Kid: "bench", Alg: "ES256", Use: "sig", Crv: "P-256",
+		X: b64.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y: b64.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []JWK{jwk}})
+	}))
+	b.Cleanup(srv.Close)
+
+	now := time.Now()
+	token, err := signJWT(key, "ES256", "bench", "at+jwt", map[string]any{
+		"iss":       srv.URL,
+		"sub":       "usr_2b7c9e",
+		"aud":       []string{"https://api.example.com"},
+		"exp":       now.Add(time.Hour).Unix(),
+		"iat":       now.Unix(),
+		"nbf":       now.Unix(),
+		"jti":       "tok_8f3a1d",
+		"sid":       "ses_41c0aa",
+		"client_id": "app_9d2e",
+		"scope":     "openid profile email",
+		"email":     "jane@example.com",
+		"tenant_id": "org_5a1f",
+		"roles":     []string{"admin", "billing"},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	v, err := NewVerifier(WithIssuer(srv.URL, "https://api.example.com"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		b.Fatal(err)
+	}
+	return v, token
+}
+
+func BenchmarkVerify(b *testing.B) {
+	v, token := benchVerifier(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := v.Verify(ctx, token); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyStandard(b *testing.B) {
+	v, token := benchVerifier(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := v.VerifyStandard(ctx, token); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// testIssuer serves a JWKS for a new key and returns its URL and a
+// function signing tokens for sub with that key.
+func testIssuer(t *testing.T) (string, func(iss, sub string, now time.Time) string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk := JWK{
+		Kty: "EC", Kid: "k1", Alg: "ES256", Use: "sig", Crv: "P-256",
+		X: b64.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y: b64.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []JWK{jwk}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, func(iss, sub string, now time.Time) string {
+		tok, err := signJWT(key, "ES256", "k1", "at+jwt", map[string]any{
+			"iss": iss,
+			"sub": sub,
+			"aud": "https://api.example.com",
+			"exp": now.Add(time.Hour).Unix(),
+			"iat": now.Unix(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok
+	}
+}
+
+func TestVerifierMultipleIssuers(t *testing.T) {
+	clock := &stepClock{t: time.Now()}
+	static, signStatic := testIssuer(t)
+	dynamic, signDynamic := testIssuer(t)
+	forged := "https://evil.example.com"
+	calls := map[string]int{}
+	v, err := NewVerifier(
+		WithIssuer(static, "https://api.example.com"),
+		WithIssuerResolver(func(ctx context.Context, iss string) (*IssuerConfig, error) {
+			calls[iss]++
+			if iss == dynamic {
+				return &IssuerConfig{Issuer: dynamic, Audiences: []string{"https://api.example.com"}}, nil
+			}
+			return nil, nil
+		}),
+		WithVerifierClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name, token, sub string
+	}{
+		{"static", signStatic(static, "usr_static", clock.t), "usr_static"},
+		{"resolved", signDynamic(dynamic, "usr_dynamic", clock.t), "usr_dynamic"},
+		{"resolved again", signDynamic(dynamic, "usr_dynamic", clock.t), "usr_dynamic"},
+	} {
+		claims, err := v.Verify(ctx, tc.token)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if claims.Subject() != tc.sub {
+			t.Fatalf("%s: subject = %q, want %q", tc.name, claims.Subject(), tc.sub)
+		}
+	}
+	if calls[static] != 0 || calls[dynamic] != 1 {
+		t.Fatalf("resolver calls = %v, want one for the resolved issuer only", calls)
+	}
+
+	// A key from one issuer must not verify tokens naming another.
+	if _, err := v.Verify(ctx, signStatic(dynamic, "usr_x", clock.t)); err == nil {
+		t.Fatal("token signed by the wrong issuer's key verified")
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := v.Verify(ctx, signStatic(forged, "usr_x", clock.t)); !errors.Is(err, ErrUnknownIssuer) {
+			t.Fatalf("forged issuer: err = %v, want ErrUnknownIssuer", err)
+		}
+	}
+	if calls[forged] != 1 {
+		t.Fatalf("resolver called %d times for a rejected issuer within the miss TTL, want 1", calls[forged])
+	}
+	clock.t = clock.t.Add(DefaultIssuerMissTTL + time.Second)
+	if _, err := v.Verify(ctx, signStatic(forged, "usr_x", clock.t)); !errors.Is(err, ErrUnknownIssuer) {
+		t.Fatalf("forged issuer: err = %v, want ErrUnknownIssuer", err)
+	}
+	if calls[forged] != 2 {
+		t.Fatalf("resolver not retried after the miss TTL: %d calls", calls[forged])
+	}
+}
+
+func TestVerifierResolverErrorsAreCached(t *testing.T) {
+	clock := &stepClock{t: time.Now()}
+	iss, sign := testIssuer(t)
+	fail := true
+	calls := 0
+	v, err := NewVerifier(
+		WithIssuerResolver(func(ctx context.Context, got string) (*IssuerConfig, error) {
+			calls++
+			if fail {
+				return nil, errors.New("database unavailable")
+			}
+			return &IssuerConfig{Issuer: got, Audiences: []string{"https://api.example.com"}}, nil
+		}),
+		WithVerifierClock(clock),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	tok := sign(iss, "usr_1", clock.t)
+	if _, err := v.Verify(ctx, tok); err == nil {
+		t.Fatal("verified while the resolver was failing")
+	}
+	if _, err := v.Verify(ctx, tok); !errors.Is(err, ErrUnknownIssuer) {
+		t.Fatalf("err = %v, want ErrUnknownIssuer from the miss cache", err)
+	}
+	if calls != 1 {
+		t.Fatalf("resolver calls = %d, want 1", calls)
+	}
+
+	fail = false
+	clock.t = clock.t.Add(DefaultIssuerMissTTL)
+	if _, err := v.Verify(ctx, sign(iss, "usr_1", clock.t)); err != nil {
+		t.Fatalf("after recovery: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("resolver calls = %d, want 2", calls)
+	}
+}
+
+func TestVerifierMissCacheIsBounded(t *testing.T) {
+	v, err := NewVerifier(WithIssuerResolver(func(ctx context.Context, iss string) (*IssuerConfig, error) {
+		return nil, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxIssuerMisses+10; i++ {
+		v.issuer(context.Background(), fmt.Sprintf("https://forged-%d.example.com", i))
+	}
+	if n := len(v.misses); n > maxIssuerMisses {
+		t.Fatalf("miss cache holds %d entries, want at most %d", n, maxIssuerMisses)
+	}
+}
@@ -0,0 +1,125 @@
+package authvital
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookEndpoint is a URL receiving webhook events.
+type WebhookEndpoint struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events are the event types delivered. Empty means all events.
+	Events      []EventType `json:"events"`
+	Description string      `json:"description,omitempty"`
+	// Headers are added to every delivery, e.g. for a gateway API key.
+	Headers   map[string]string `json:"headers,omitempty"`
+	Disabled  bool              `json:"disabled"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// WebhookSecret is an endpoint's signing secret. It is only returned by
+// Create and RotateSecret.
+type WebhookSecret struct {
+	Secret string `json:"secret"`
+	// PreviousExpiresAt is when the replaced secret stops signing
+	// deliveries; until then both are sent.
+	PreviousExpiresAt *time.Time `json:"previousExpiresAt,omitempty"`
+}
+
+// WebhookDeliveryStatus is the outcome of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookSucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookRetrying means an attempt failed and another is scheduled.
+	WebhookRetrying WebhookDeliveryStatus = "retrying"
+	// WebhookExhausted means all retries failed; see Replay.
+	WebhookExhausted WebhookDeliveryStatus = "exhausted"
+)
+
+// WebhookDelivery is one event sent to one endpoint.
+type WebhookDelivery struct {
+	ID         string                `json:"id"`
+	EndpointID string                `json:"endpointId"`
+	EventID    string                `json:"eventId"`
+	EventType  EventType             `json:"eventType"`
+	Status     WebhookDeliveryStatus `json:"status"`
+	Attempts   []WebhookAttempt      `json:"attempts"`
+	// NextAttemptAt is set while Status is WebhookRetrying.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// WebhookAttempt is one HTTP request of a delivery.
+type WebhookAttempt struct {
+	At time.Time `json:"at"`
+	// StatusCode is zero when no response was received; Error says why.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int    `json:"durationMs"`
+	// ResponseBody is the first few kilobytes of the response.
+	ResponseBody string `json:"responseBody,omitempty"`
+}
+
+// WebhookDeliveryListOptions filters ListDeliveries.
+type WebhookDeliveryListOptions struct {
+	ListOptions
+	Status    WebhookDeliveryStatus
+	EventType EventType
+	Since     time.Time
+}
+
+// WebhooksService manages webhook endpoints and their deliveries.
+type WebhooksService struct{}
+
+// List lists webhook endpoints.
+func (s *WebhooksService) List(ctx context.Context) ([]WebhookEndpoint, error) {
+	return nil, ErrNotImplemented
+}
+
+// Get retrieves an endpoint.
+func (s *WebhooksService) Get(ctx context.Context, id string) (*WebhookEndpoint, error) {
+	return nil, ErrNotImplemented
+}
+
+// Create registers an endpoint and returns it with its signing secret.
+func (s *WebhooksService) Create(ctx context.Context, e *WebhookEndpoint) (*WebhookEndpoint, *WebhookSecret, error) {
+	return nil, nil, ErrNotImplemented
+}
+
+// Update replaces an endpoint's URL, events, headers and disabled flag.
+func (s *WebhooksService) Update(ctx context.Context, id string, e *WebhookEndpoint) (*WebhookEndpoint, error) {
+	return nil, ErrNotImplemented
+}
+
+// Delete removes an endpoint. Pending deliveries to it are dropped.
+func (s *WebhooksService) Delete(ctx context.Context, id string) error {
+	return ErrNotImplemented
+}
+
+// RotateSecret issues a new signing secret. The old one keeps signing
+// alongside it for grace, so receivers can be updated without dropping
+// deliveries; zero revokes it immediately.
+func (s *WebhooksService) RotateSecret(ctx context.Context, id string, grace time.Duration) (*WebhookSecret, error) {
+	return nil, ErrNotImplemented
+}
+
+// ListDeliveries lists an endpoint's deliveries, newest first, with their
+// attempts.
+func (s *WebhooksService) ListDeliveries(ctx context.Context, id string, opts *WebhookDeliveryListOptions) (*List[WebhookDelivery], error) {
+	return nil, ErrNotImplemented
+}
+
+// Replay sends a delivery again, regardless of its status. It returns the
+// new delivery.
+func (s *WebhooksService) Replay(ctx context.Context, deliveryID string) (*WebhookDelivery, error) {
+	return nil, ErrNotImplemented
+}
+
+// ReplayFailed replays every exhausted delivery of an endpoint since the
+// given time and returns how many were queued.
+func (s *WebhooksService) ReplayFailed(ctx context.Context, id string, since time.Time) (int, error) {
+	return 0, ErrNotImplemented
+}
@@ -0,0 +1,141 @@
+package authvital
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType identifies a webhook event.
+type EventType string
+
+const (
+	// EventLoginHighRisk is sent when a sign-in is assessed as high risk.
+	// Its data is a HighRiskLoginEvent.
+	EventLoginHighRisk EventType = "login.high_risk"
+	// EventLoginBlockedByRisk is sent when a sign-in is denied because of its risk.
+	// Its data is a HighRiskLoginEvent.
+	EventLoginBlockedByRisk EventType = "login.blocked_by_risk"
+	// EventMFAPushResponded is sent when a user approves or denies a push
+	// MFA prompt. Its data is a PushResponseEvent.
+	EventMFAPushResponded EventType = "mfa.push_responded"
+	// EventSessionEvicted is sent when a session is ended to make room for
+	// a new one under the concurrent session limit. Its data is a
+	// SessionEvictedEvent.
+	EventSessionEvicted EventType = "session.evicted"
+	// EventPasswordChanged is sent when a user's password is changed or
+	// reset. Its data is a CredentialEvent.
+	EventPasswordChanged EventType = "user.password_changed"
+	// EventMFAEnrolled is sent when a user adds an MFA factor. Its data is
+	// a CredentialEvent.
+	EventMFAEnrolled EventType = "user.mfa_enrolled"
+	// EventEmailChanged is sent when a user's email address changes. Its
+	// data is an EmailChangedEvent.
+	EventEmailChanged EventType = "user.email_changed"
+	// EventSessionRevoked is sent when a session is revoked by the user or
+	// an admin. Its data is a SessionRevokedEvent.
+	EventSessionRevoked EventType = "session.revoked"
+	// EventSegmentEntered is sent when a user starts matching a segment
+	// with Notify set. Its data is a SegmentMembershipEvent.
+	EventSegmentEntered EventType = "segment.entered"
+	// EventSegmentLeft is sent when a user stops matching a segment with
+	// Notify set. Its data is a SegmentMembershipEvent.
+	EventSegmentLeft EventType = "segment.left"
+)
+
+// CredentialLifecycleEvents are the events to subscribe to for sending
+// your own security notifications.
+var CredentialLifecycleEvents = []EventType{
+	EventPasswordChanged,
+	EventMFAEnrolled,
+	EventEmailChanged,
+	EventSessionRevoked,
+}
+
+// Event is a webhook delivery envelope.
+type Event struct {
+	ID            string          `json:"id"`
+	Type          EventType       `json:"type"`
+	Timestamp     time.Time       `json:"timestamp"`
+	TenantID      string          `json:"tenant_id"`
+	ApplicationID string          `json:"application_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Decode unmarshals the event data into v.
+func (e *Event) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// HighRiskLoginEvent is the data of EventLoginHighRisk and EventLoginBlockedByRisk.
+type HighRiskLoginEvent struct {
+	UserID    string       `json:"user_id"`
+	Email     string       `json:"email"`
+	IP        string       `json:"ip"`
+	DeviceID  string       `json:"device_id,omitempty"`
+	UserAgent string       `json:"user_agent,omitempty"`
+	Score     int          `json:"score"`
+	Level     RiskLevel    `json:"level"`
+	Signals   []RiskSignal `json:"signals"`
+}
+
+// PushResponseEvent is the data of EventMFAPushResponded.
+type PushResponseEvent struct {
+	UserID   string `json:"user_id"`
+	FactorID string `json:"factor_id"`
+	// MFAToken matches Challenge.MFAToken of the waiting challenge.
+	MFAToken string `json:"mfa_token"`
+	Approved bool   `json:"approved"`
+}
+
+// SessionEvictedEvent is the data of EventSessionEvicted.
+type SessionEvictedEvent struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	// ReplacedBy is the new session that caused the eviction.
+	ReplacedBy string `json:"replaced_by,omitempty"`
+	// ExpiresAt is when the evicted session's last token expires.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CredentialEvent is the data of EventPasswordChanged and EventMFAEnrolled.
+type CredentialEvent struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	// ActorID is the admin who made the change; it is empty when the user
+	// did.
+	ActorID string `json:"actor_id,omitempty"`
+	// FactorType is set for EventMFAEnrolled.
+	FactorType FactorType `json:"factor_type,omitempty"`
+	// Reset is true when a password was reset rather than changed.
+	Reset     bool   `json:"reset,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// EmailChangedEvent is the data of EventEmailChanged. Notify OldEmail so
+// a hijacked account is noticed.
+type EmailChangedEvent struct {
+	UserID   string `json:"user_id"`
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+	ActorID  string `json:"actor_id,omitempty"`
+}
+
+// SessionRevokedEvent is the data of EventSessionRevoked.
+type SessionRevokedEvent struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	// ActorID is the admin who revoked the session; it is empty when the
+	// user signed out or revoked it themselves.
+	ActorID string `json:"actor_id,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// SegmentMembershipEvent is the data of EventSegmentEntered and
+// EventSegmentLeft.
+type SegmentMembershipEvent struct {
+	SegmentID   string `json:"segment_id"`
+	SegmentName string `json:"segment_name"`
+	UserID      string `json:"user_id"`
+	Email       string `json:"email"`
+}